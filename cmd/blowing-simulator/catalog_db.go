@@ -0,0 +1,328 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"blowing-simulator/internal/simulator"
+	"github.com/jmoiron/sqlx"
+)
+
+const catalogSeedFile = "schema/catalog_seed.json"
+
+// loadCatalogCables returns all catalog cables
+func loadCatalogCables(db *sqlx.DB) ([]simulator.CatalogCable, error) {
+	var cables []simulator.CatalogCable
+	err := db.Select(&cables, `
+		SELECT id, COALESCE(manufacturer, '') AS manufacturer, designation, diameter_mm,
+		       COALESCE(weight_kg_per_m, 0) AS weight_kg_per_m, COALESCE(fiber_count, 0) AS fiber_count,
+		       COALESCE(max_push_force_n, 0) AS max_push_force_n
+		FROM catalog_cables
+		ORDER BY manufacturer, designation`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load catalog cables: %v", err)
+	}
+	return cables, nil
+}
+
+// loadCatalogDucts returns all catalog ducts
+func loadCatalogDucts(db *sqlx.DB) ([]simulator.CatalogDuct, error) {
+	var ducts []simulator.CatalogDuct
+	err := db.Select(&ducts, `
+		SELECT id, COALESCE(manufacturer, '') AS manufacturer, COALESCE(bundle, '') AS bundle,
+		       COALESCE(pipe_type, '') AS pipe_type, outer_diameter_mm, inner_diameter_mm,
+		       COALESCE(inner_wall, '') AS inner_wall
+		FROM catalog_ducts
+		ORDER BY manufacturer, bundle`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load catalog ducts: %v", err)
+	}
+	return ducts, nil
+}
+
+// insertCatalogCable stores a new catalog cable and returns its ID
+func insertCatalogCable(db *sqlx.DB, c simulator.CatalogCable) (int, error) {
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO catalog_cables (manufacturer, designation, diameter_mm, weight_kg_per_m, fiber_count, max_push_force_n)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		c.Manufacturer, c.Designation, c.DiameterMM, c.WeightKgPerM, c.FiberCount, c.MaxPushForceN,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert catalog cable: %v", err)
+	}
+	return id, nil
+}
+
+// insertCatalogDuct stores a new catalog duct and returns its ID
+func insertCatalogDuct(db *sqlx.DB, d simulator.CatalogDuct) (int, error) {
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO catalog_ducts (manufacturer, bundle, pipe_type, outer_diameter_mm, inner_diameter_mm, inner_wall)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		d.Manufacturer, d.Bundle, d.PipeType, d.OuterDiameterMM, d.InnerDiameterMM, d.InnerWall,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert catalog duct: %v", err)
+	}
+	return id, nil
+}
+
+// seedCatalog fills an empty catalog from the seed file
+func seedCatalog(db *sqlx.DB, path string) error {
+	var count int
+	if err := db.Get(&count, "SELECT (SELECT COUNT(*) FROM catalog_cables) + (SELECT COUNT(*) FROM catalog_ducts)"); err != nil {
+		return fmt.Errorf("failed to count catalog entries: %v", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open catalog seed: %v", err)
+	}
+	defer file.Close()
+	seed, err := simulator.ParseCatalogSeed(file)
+	if err != nil {
+		return err
+	}
+	for _, c := range seed.Cables {
+		if _, err := insertCatalogCable(db, c); err != nil {
+			return err
+		}
+	}
+	for _, d := range seed.Ducts {
+		if _, err := insertCatalogDuct(db, d); err != nil {
+			return err
+		}
+	}
+	log.Printf("seedCatalog: Seeded %d cables and %d ducts from %s", len(seed.Cables), len(seed.Ducts), path)
+	return nil
+}
+
+// linkProtocolToCatalog matches the parsed cable and pipe of a protocol to catalog entries
+func linkProtocolToCatalog(db *sqlx.DB, protocolID int) error {
+	var eq struct {
+		PipeManufacturer  sql.NullString  `db:"pipe_manufacturer"`
+		PipeBundle        sql.NullString  `db:"pipe_bundle"`
+		PipeType          sql.NullString  `db:"pipe_type"`
+		CableManufacturer sql.NullString  `db:"cable_manufacturer"`
+		CableDesignation  sql.NullString  `db:"cable_designation"`
+		CableDiameter     sql.NullFloat64 `db:"cable_diameter"`
+	}
+	err := db.Get(&eq, `
+		SELECT pipe_manufacturer, pipe_bundle, pipe_type, cable_manufacturer, cable_designation, cable_diameter
+		FROM protocol_equipment WHERE protocol_id = $1
+		ORDER BY id LIMIT 1`, protocolID)
+	if err != nil {
+		return fmt.Errorf("failed to load equipment: %v", err)
+	}
+
+	cables, err := loadCatalogCables(db)
+	if err != nil {
+		return err
+	}
+	ducts, err := loadCatalogDucts(db)
+	if err != nil {
+		return err
+	}
+
+	var cableID, ductID sql.NullInt64
+	if c := simulator.MatchCable(cables, eq.CableManufacturer.String, eq.CableDesignation.String, eq.CableDiameter.Float64); c != nil {
+		cableID = sql.NullInt64{Int64: int64(c.ID), Valid: true}
+	}
+	if d := simulator.MatchDuct(ducts, eq.PipeManufacturer.String, eq.PipeBundle.String, eq.PipeType.String); d != nil {
+		ductID = sql.NullInt64{Int64: int64(d.ID), Valid: true}
+	}
+
+	_, err = db.Exec(`
+		UPDATE protocol_equipment SET catalog_cable_id = $2, catalog_duct_id = $3
+		WHERE protocol_id = $1`, protocolID, cableID, ductID)
+	if err != nil {
+		return fmt.Errorf("failed to link catalog entries: %v", err)
+	}
+	log.Printf("linkProtocolToCatalog: Protocol %d -> cable %v, duct %v", protocolID, cableID.Int64, ductID.Int64)
+	return nil
+}
+
+// CatalogCablesHandler lists (GET), creates (POST), updates (PUT ?id=X) and deletes (DELETE ?id=X) catalog cables
+func CatalogCablesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		cables, err := loadCatalogCables(db)
+		if err != nil {
+			http.Error(w, "Error fetching catalog cables: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if cables == nil {
+			cables = []simulator.CatalogCable{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cables)
+
+	case http.MethodPost, http.MethodPut:
+		var cable simulator.CatalogCable
+		if err := json.NewDecoder(r.Body).Decode(&cable); err != nil {
+			http.Error(w, "Invalid cable: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := cable.Validate(); err != nil {
+			http.Error(w, "Invalid cable: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			id, err := insertCatalogCable(db, cable)
+			if err != nil {
+				http.Error(w, "Error saving cable: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			cable.ID = id
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			id, err := strconv.Atoi(r.URL.Query().Get("id"))
+			if err != nil {
+				http.Error(w, "Invalid cable ID", http.StatusBadRequest)
+				return
+			}
+			result, err := db.Exec(`
+				UPDATE catalog_cables SET manufacturer = $2, designation = $3, diameter_mm = $4,
+				       weight_kg_per_m = $5, fiber_count = $6, max_push_force_n = $7
+				WHERE id = $1`,
+				id, cable.Manufacturer, cable.Designation, cable.DiameterMM, cable.WeightKgPerM, cable.FiberCount, cable.MaxPushForceN)
+			if err != nil {
+				http.Error(w, "Error updating cable: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if n, _ := result.RowsAffected(); n == 0 {
+				http.Error(w, "Cable not found", http.StatusNotFound)
+				return
+			}
+			cable.ID = id
+		}
+		json.NewEncoder(w).Encode(cable)
+
+	case http.MethodDelete:
+		deleteCatalogEntry(w, r, "catalog_cables")
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// CatalogDuctsHandler lists (GET), creates (POST), updates (PUT ?id=X) and deletes (DELETE ?id=X) catalog ducts
+func CatalogDuctsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		ducts, err := loadCatalogDucts(db)
+		if err != nil {
+			http.Error(w, "Error fetching catalog ducts: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if ducts == nil {
+			ducts = []simulator.CatalogDuct{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ducts)
+
+	case http.MethodPost, http.MethodPut:
+		var duct simulator.CatalogDuct
+		if err := json.NewDecoder(r.Body).Decode(&duct); err != nil {
+			http.Error(w, "Invalid duct: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		duct = duct.WithDimensions()
+		if err := duct.Validate(); err != nil {
+			http.Error(w, "Invalid duct: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			id, err := insertCatalogDuct(db, duct)
+			if err != nil {
+				http.Error(w, "Error saving duct: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			duct.ID = id
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			id, err := strconv.Atoi(r.URL.Query().Get("id"))
+			if err != nil {
+				http.Error(w, "Invalid duct ID", http.StatusBadRequest)
+				return
+			}
+			result, err := db.Exec(`
+				UPDATE catalog_ducts SET manufacturer = $2, bundle = $3, pipe_type = $4,
+				       outer_diameter_mm = $5, inner_diameter_mm = $6, inner_wall = $7
+				WHERE id = $1`,
+				id, duct.Manufacturer, duct.Bundle, duct.PipeType, duct.OuterDiameterMM, duct.InnerDiameterMM, duct.InnerWall)
+			if err != nil {
+				http.Error(w, "Error updating duct: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if n, _ := result.RowsAffected(); n == 0 {
+				http.Error(w, "Duct not found", http.StatusNotFound)
+				return
+			}
+			duct.ID = id
+		}
+		json.NewEncoder(w).Encode(duct)
+
+	case http.MethodDelete:
+		deleteCatalogEntry(w, r, "catalog_ducts")
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// deleteCatalogEntry deletes the catalog entry ?id=X; linked protocols keep their parsed values
+func deleteCatalogEntry(w http.ResponseWriter, r *http.Request, table string) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid catalog ID", http.StatusBadRequest)
+		return
+	}
+	result, err := db.Exec("DELETE FROM "+table+" WHERE id = $1", id)
+	if err != nil {
+		http.Error(w, "Error deleting catalog entry: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		http.Error(w, "Catalog entry not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CatalogRelinkHandler matches all stored protocols against the current catalog
+func CatalogRelinkHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var ids []int
+	if err := db.Select(&ids, "SELECT DISTINCT protocol_id FROM protocol_equipment WHERE protocol_id IS NOT NULL"); err != nil {
+		http.Error(w, "Error fetching protocols: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	linked := 0
+	for _, id := range ids {
+		if err := linkProtocolToCatalog(db, id); err != nil {
+			log.Printf("CatalogRelinkHandler: Protocol %d: %v", id, err)
+			continue
+		}
+		linked++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"protocols": len(ids), "linked": linked})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"blowing-simulator/internal/simulator"
+)
+
+// SimulationComparisonResponse is returned by /protocols/compare-simulation
+type SimulationComparisonResponse struct {
+	ProtocolID int                             `json:"protocol_id"`
+	RouteID    *int                            `json:"route_id"` // planned route used as duct geometry (nil = straight duct)
+	Input      simulator.SimulationInput       `json:"input"`
+	Comparison *simulator.SimulationComparison `json:"comparison"`
+}
+
+// CompareSimulationHandler simulates a protocol's recorded setup and returns the predicted and
+// actual curves with deviation metrics. ?friction=X overrides the planning friction coefficient.
+func CompareSimulationHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid protocol ID", http.StatusBadRequest)
+		return
+	}
+
+	input, samples, err := loadProtocolRun(id)
+	if err != nil {
+		http.Error(w, "Comparison failed: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if frictionStr := r.URL.Query().Get("friction"); frictionStr != "" {
+		friction, err := strconv.ParseFloat(frictionStr, 64)
+		if err != nil || friction <= 0 {
+			http.Error(w, "Invalid friction coefficient", http.StatusBadRequest)
+			return
+		}
+		input.FrictionCoefficient = friction
+	}
+
+	response := SimulationComparisonResponse{ProtocolID: id}
+
+	// Use the planned route of the section if one is stored
+	var sectionNVT string
+	db.Get(&sectionNVT, "SELECT COALESCE(section_nvt, '') FROM protocols WHERE id = $1", id)
+	if route, err := findRouteForSection(sectionNVT); err != nil {
+		log.Printf("CompareSimulationHandler: Route lookup for protocol %d failed: %v", id, err)
+	} else if route != nil {
+		input.Duct.Segments = route.Segments
+		response.RouteID = &route.ID
+	}
+
+	comparison, err := simulator.CompareSimulation(input, samples)
+	if err != nil {
+		http.Error(w, "Comparison failed: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	response.Input = input
+	response.Comparison = comparison
+	log.Printf("CompareSimulationHandler: Protocol %d -> RMS speed error %.2f m/min, assessment %s",
+		id, comparison.RMSSpeedErrorMMin, comparison.Assessment)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
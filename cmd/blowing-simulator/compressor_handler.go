@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"blowing-simulator/internal/simulator"
+)
+
+// CompressorCheckListItem is one protocol in the compressor check overview
+type CompressorCheckListItem struct {
+	ProtocolID   int      `json:"protocol_id"`
+	SystemName   string   `json:"system_name"`
+	Model        string   `json:"compressor_model"`
+	Status       string   `json:"status"`
+	SagSuspected bool     `json:"sag_suspected"`
+	Warnings     []string `json:"warnings"`
+}
+
+// checkProtocolCompressor checks the recorded compressor of a stored protocol against the
+// airflow needed for its duct (planned route length if stored) and its measured pressure curve
+func checkProtocolCompressor(protocolID int) (*simulator.CompressorCheck, error) {
+	var sectionNVT string
+	err := db.Get(&sectionNVT, "SELECT COALESCE(section_nvt, '') FROM protocols WHERE id = $1", protocolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get protocol: %v", err)
+	}
+	eq, err := loadProtocolEquipment(protocolID)
+	if err != nil {
+		return nil, err
+	}
+	// Missing duct dimensions are reported by the check itself
+	input, _ := simulationInputFromEquipment(eq)
+
+	route, err := findRouteForSection(sectionNVT)
+	if err != nil {
+		log.Printf("checkProtocolCompressor: Route lookup for protocol %d failed: %v", protocolID, err)
+	} else if route != nil {
+		input.Duct.Segments = route.Segments
+	}
+
+	measurements, err := loadProtocolMeasurements(protocolID)
+	if err != nil {
+		return nil, err
+	}
+	samples := simulator.FitSamples(measurements, input.PushForceN)
+
+	check := simulator.CheckCompressor(eq.CompressorModel.String, input, samples)
+	return &check, nil
+}
+
+// CompressorCheckHandler returns the compressor airflow check of a protocol (?id=X) as JSON.
+// Without an id all protocols are checked and returned as an overview.
+func CompressorCheckHandler(w http.ResponseWriter, r *http.Request) {
+	if idStr := r.URL.Query().Get("id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			http.Error(w, "Invalid protocol ID", http.StatusBadRequest)
+			return
+		}
+		check, err := checkProtocolCompressor(id)
+		if err != nil {
+			http.Error(w, "Compressor check failed: "+err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(check)
+		return
+	}
+
+	var protocols []struct {
+		ID         int    `db:"id"`
+		SystemName string `db:"system_name"`
+	}
+	err := db.Select(&protocols, "SELECT id, COALESCE(system_name, '') AS system_name FROM protocols ORDER BY id")
+	if err != nil {
+		http.Error(w, "Error fetching protocols: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []CompressorCheckListItem{}
+	for _, p := range protocols {
+		check, err := checkProtocolCompressor(p.ID)
+		if err != nil {
+			log.Printf("CompressorCheckHandler: Protocol %d skipped: %v", p.ID, err)
+			continue
+		}
+		item := CompressorCheckListItem{
+			ProtocolID: p.ID,
+			SystemName: p.SystemName,
+			Model:      check.Model,
+			Status:     check.Status,
+			Warnings:   check.Warnings,
+		}
+		if check.PressureSag != nil {
+			item.SagSuspected = check.PressureSag.Suspected
+		}
+		items = append(items, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// CompressorsHandler returns the known compressor models as JSON
+func CompressorsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(simulator.CompressorModels())
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"blowing-simulator/internal/simulator"
+)
+
+// sqlExecer is a database or transaction statements can be executed on
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// saveParseDiagnostics stores the warnings and errors of a parsed protocol
+func saveParseDiagnostics(protocolID int, result *simulator.ParseResult) error {
+	return insertParseDiagnostics(db, protocolID, result)
+}
+
+// replaceParseDiagnostics replaces the stored diagnostics of a protocol by
+// those of a new parse, e.g. of the machine log that replaced its measurements
+func replaceParseDiagnostics(tx *sql.Tx, protocolID int, result *simulator.ParseResult) error {
+	if _, err := tx.Exec("DELETE FROM protocol_diagnostics WHERE protocol_id = $1", protocolID); err != nil {
+		return fmt.Errorf("failed to delete parse diagnostics: %v", err)
+	}
+	return insertParseDiagnostics(tx, protocolID, result)
+}
+
+// insertParseDiagnostics inserts the diagnostics of a parse result
+func insertParseDiagnostics(ex sqlExecer, protocolID int, result *simulator.ParseResult) error {
+	if result == nil {
+		return nil
+	}
+	for _, d := range result.Diagnostics() {
+		_, err := ex.Exec(`
+			INSERT INTO protocol_diagnostics (protocol_id, severity, line_number, field, raw_text, reason)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			protocolID, d.Severity, d.Line, d.Field, d.Raw, d.Reason)
+		if err != nil {
+			return fmt.Errorf("failed to save parse diagnostics: %v", err)
+		}
+	}
+	return nil
+}
+
+// loadParseDiagnostics returns the stored diagnostics of a protocol, errors first
+func loadParseDiagnostics(protocolID int) ([]simulator.Diagnostic, error) {
+	var diagnostics []simulator.Diagnostic
+	err := db.Select(&diagnostics, `
+		SELECT severity, line_number, field, raw_text, reason
+		FROM protocol_diagnostics
+		WHERE protocol_id = $1
+		ORDER BY severity = 'error' DESC, line_number, id`, protocolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parse diagnostics: %v", err)
+	}
+	return diagnostics, nil
+}
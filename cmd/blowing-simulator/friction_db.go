@@ -0,0 +1,350 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"blowing-simulator/internal/simulator"
+)
+
+// ProtocolEquipmentRecord holds the protocol_equipment columns used by simulation and analytics
+type ProtocolEquipmentRecord struct {
+	DeviceModel      sql.NullString  `db:"device_model"`
+	PipeManufacturer sql.NullString  `db:"pipe_manufacturer"`
+	PipeBundle       sql.NullString  `db:"pipe_bundle"`
+	PipeType         sql.NullString  `db:"pipe_type"`
+	PipeInnerWall    sql.NullString  `db:"pipe_inner_wall"`
+	CableDiameter    sql.NullFloat64 `db:"cable_diameter"`
+	CableLubricant   sql.NullString  `db:"cable_lubricant"`
+	CompressorModel  sql.NullString  `db:"compressor_model"`
+
+	// Physical properties of the matched catalog entries
+	CatalogInnerDiameter sql.NullFloat64 `db:"catalog_inner_diameter_mm"`
+	CatalogInnerWall     sql.NullString  `db:"catalog_inner_wall"`
+	CatalogCableDiameter sql.NullFloat64 `db:"catalog_cable_diameter_mm"`
+	CatalogCableWeight   sql.NullFloat64 `db:"catalog_cable_weight_kg_per_m"`
+}
+
+// FrictionCalibration represents one row of friction_calibration_view
+type FrictionCalibration struct {
+	PipeManufacturer string  `db:"pipe_manufacturer" json:"pipe_manufacturer"`
+	PipeType         string  `db:"pipe_type" json:"pipe_type"`
+	CableLubricant   string  `db:"cable_lubricant" json:"cable_lubricant"`
+	ProtocolCount    int     `db:"protocol_count" json:"protocol_count"`
+	AvgFriction      float64 `db:"avg_friction" json:"avg_friction"`
+	StddevFriction   float64 `db:"stddev_friction" json:"stddev_friction"`
+	MinFriction      float64 `db:"min_friction" json:"min_friction"`
+	MaxFriction      float64 `db:"max_friction" json:"max_friction"`
+}
+
+// loadProtocolEquipment loads the equipment record of a protocol
+func loadProtocolEquipment(protocolID int) (*ProtocolEquipmentRecord, error) {
+	var eq ProtocolEquipmentRecord
+	err := db.Get(&eq, `
+		SELECT pe.device_model, pe.pipe_manufacturer, pe.pipe_bundle, pe.pipe_type, pe.pipe_inner_wall,
+		       pe.cable_diameter, pe.cable_lubricant, pe.compressor_model,
+		       cd.inner_diameter_mm AS catalog_inner_diameter_mm, cd.inner_wall AS catalog_inner_wall,
+		       cc.diameter_mm AS catalog_cable_diameter_mm, cc.weight_kg_per_m AS catalog_cable_weight_kg_per_m
+		FROM protocol_equipment pe
+		LEFT JOIN catalog_ducts cd ON cd.id = pe.catalog_duct_id
+		LEFT JOIN catalog_cables cc ON cc.id = pe.catalog_cable_id
+		WHERE pe.protocol_id = $1
+		ORDER BY pe.id LIMIT 1`, protocolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load equipment: %v", err)
+	}
+	return &eq, nil
+}
+
+// simulationInputFromEquipment builds the duct and cable parameters of a stored protocol.
+// Matched catalog entries take precedence over the parsed free text.
+func simulationInputFromEquipment(eq *ProtocolEquipmentRecord) (simulator.SimulationInput, error) {
+	input := simulator.SimulationInput{
+		Duct: simulator.DuctParams{
+			InnerWall: eq.PipeInnerWall.String,
+		},
+		Cable: simulator.CableParams{
+			DiameterMM: eq.CableDiameter.Float64,
+			Lubricant:  eq.CableLubricant.String,
+		},
+		PushForceN: simulator.DevicePushForce(eq.DeviceModel.String),
+	}
+	if eq.CatalogInnerWall.Valid && input.Duct.InnerWall == "" {
+		input.Duct.InnerWall = eq.CatalogInnerWall.String
+	}
+	if eq.CatalogCableDiameter.Valid && input.Cable.DiameterMM <= 0 {
+		input.Cable.DiameterMM = eq.CatalogCableDiameter.Float64
+	}
+	input.Cable.WeightKgPerM = eq.CatalogCableWeight.Float64
+
+	innerDiameter, ok := eq.CatalogInnerDiameter.Float64, eq.CatalogInnerDiameter.Valid
+	if !ok {
+		innerDiameter, ok = simulator.InnerDiameterFromPipeType(eq.PipeType.String)
+	}
+	if !ok {
+		innerDiameter, ok = simulator.InnerDiameterFromPipeType(eq.PipeBundle.String)
+	}
+	if !ok {
+		return input, fmt.Errorf("pipe dimensions unknown (pipe type %q)", eq.PipeType.String)
+	}
+	input.Duct.InnerDiameterMM = innerDiameter
+	if input.Cable.DiameterMM <= 0 {
+		return input, fmt.Errorf("cable diameter unknown")
+	}
+	return input, nil
+}
+
+// fillCheckFromEquipment checks the recorded cable against the recorded duct, using the catalog inner diameter if matched
+func fillCheckFromEquipment(eq *ProtocolEquipmentRecord) simulator.FillCheck {
+	if eq.CatalogInnerDiameter.Valid {
+		return simulator.CheckCableDuctFill(eq.CableDiameter.Float64, eq.CatalogInnerDiameter.Float64)
+	}
+	return simulator.CheckPipeFill(eq.CableDiameter.Float64, eq.PipeType.String, eq.PipeBundle.String)
+}
+
+// loadProtocolRun loads the recorded setup and measured samples of a stored protocol
+func loadProtocolRun(protocolID int) (simulator.SimulationInput, []simulator.FitSample, error) {
+	eq, err := loadProtocolEquipment(protocolID)
+	if err != nil {
+		return simulator.SimulationInput{}, nil, err
+	}
+	input, err := simulationInputFromEquipment(eq)
+	if err != nil {
+		return input, nil, err
+	}
+	measurements, err := loadProtocolMeasurements(protocolID)
+	if err != nil {
+		return input, nil, err
+	}
+	return input, simulator.FitSamples(measurements, input.PushForceN), nil
+}
+
+// FitProtocolFriction back-fits the friction coefficient of a stored protocol and persists
+// the result. A protocol that cannot be fitted (unknown duct, too few samples) is stored
+// with the reason, so that it is not refitted until its data changes. Training runs are
+// simulated and never fitted.
+func FitProtocolFriction(protocolID int) (*simulator.FrictionFit, error) {
+	var training bool
+	if err := db.Get(&training, "SELECT is_training FROM protocols WHERE id = $1", protocolID); err != nil {
+		return nil, fmt.Errorf("failed to load protocol: %v", err)
+	}
+	if training {
+		return nil, fmt.Errorf("protocol %d is a training run and is not fitted", protocolID)
+	}
+	eq, err := loadProtocolEquipment(protocolID)
+	if err != nil {
+		return nil, err
+	}
+	measurements, err := loadProtocolMeasurements(protocolID)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := simulationInputFromEquipment(eq)
+	var fit *simulator.FrictionFit
+	if err == nil {
+		fit, err = simulator.FitFriction(input, simulator.FitSamples(measurements, input.PushForceN))
+	}
+	if err != nil {
+		if saveErr := saveFrictionFitError(protocolID, err); saveErr != nil {
+			log.Printf("FitProtocolFriction: %v", saveErr)
+		}
+		return nil, err
+	}
+	log.Printf("FitProtocolFriction: Protocol %d -> friction %.4f (RMS %.3f N, %d samples)", protocolID, fit.FrictionCoefficient, fit.RMSErrorN, fit.SampleCount)
+
+	if err := saveFrictionFit(protocolID, fit); err != nil {
+		return nil, err
+	}
+	return fit, nil
+}
+
+// fitSavedProtocol fits a protocol after its measurements were saved or replaced;
+// failures are stored with the protocol and only logged
+func fitSavedProtocol(protocolID int) {
+	if _, err := FitProtocolFriction(protocolID); err != nil {
+		log.Printf("fitSavedProtocol: Friction fit for protocol %d unavailable: %v", protocolID, err)
+	}
+}
+
+// saveFrictionFit stores the fitted parameters, replacing an earlier fit of the same protocol
+func saveFrictionFit(protocolID int, fit *simulator.FrictionFit) error {
+	_, err := db.Exec(`
+		INSERT INTO protocol_friction_fit (
+			protocol_id, friction_coefficient, pressure_dominance_m, rms_error_n,
+			sample_count, pressure_bar, model_version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (protocol_id) DO UPDATE SET
+			friction_coefficient = EXCLUDED.friction_coefficient,
+			pressure_dominance_m = EXCLUDED.pressure_dominance_m,
+			rms_error_n = EXCLUDED.rms_error_n,
+			sample_count = EXCLUDED.sample_count,
+			pressure_bar = EXCLUDED.pressure_bar,
+			model_version = EXCLUDED.model_version,
+			fit_error = NULL,
+			fitted_at = NOW()`,
+		protocolID,
+		fit.FrictionCoefficient,
+		fit.PressureDominanceM,
+		fit.RMSErrorN,
+		fit.SampleCount,
+		fit.PressureBar,
+		fit.ModelVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save friction fit: %v", err)
+	}
+	return nil
+}
+
+// saveFrictionFitError records why a protocol cannot be fitted, replacing an earlier fit
+func saveFrictionFitError(protocolID int, fitErr error) error {
+	_, err := db.Exec(`
+		INSERT INTO protocol_friction_fit (protocol_id, model_version, fit_error)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (protocol_id) DO UPDATE SET
+			friction_coefficient = NULL,
+			pressure_dominance_m = NULL,
+			rms_error_n = NULL,
+			sample_count = NULL,
+			pressure_bar = NULL,
+			model_version = EXCLUDED.model_version,
+			fit_error = EXCLUDED.fit_error,
+			fitted_at = NOW()`,
+		protocolID, simulator.FitModelVersion, fitErr.Error())
+	if err != nil {
+		return fmt.Errorf("failed to save friction fit error: %v", err)
+	}
+	return nil
+}
+
+// loadFrictionFit returns the stored fit of a protocol, or the reason the stored
+// fit failed. Both are empty if the protocol was never fitted.
+func loadFrictionFit(protocolID int) (*simulator.FrictionFit, string, error) {
+	var row struct {
+		FrictionCoefficient sql.NullFloat64 `db:"friction_coefficient"`
+		PressureDominanceM  sql.NullFloat64 `db:"pressure_dominance_m"`
+		RMSErrorN           sql.NullFloat64 `db:"rms_error_n"`
+		SampleCount         sql.NullInt64   `db:"sample_count"`
+		PressureBar         sql.NullFloat64 `db:"pressure_bar"`
+		ModelVersion        sql.NullString  `db:"model_version"`
+		FitError            sql.NullString  `db:"fit_error"`
+	}
+	err := db.Get(&row, `
+		SELECT friction_coefficient, pressure_dominance_m, rms_error_n, sample_count,
+		       pressure_bar, model_version, fit_error
+		FROM protocol_friction_fit WHERE protocol_id = $1`, protocolID)
+	if err == sql.ErrNoRows {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load friction fit: %v", err)
+	}
+	if row.FitError.Valid {
+		return nil, row.FitError.String, nil
+	}
+
+	fit := &simulator.FrictionFit{
+		FrictionCoefficient: row.FrictionCoefficient.Float64,
+		RMSErrorN:           row.RMSErrorN.Float64,
+		SampleCount:         int(row.SampleCount.Int64),
+		PressureBar:         row.PressureBar.Float64,
+		ModelVersion:        row.ModelVersion.String,
+	}
+	if row.PressureDominanceM.Valid {
+		fit.PressureDominanceM = &row.PressureDominanceM.Float64
+	}
+	return fit, "", nil
+}
+
+// loadFrictionCalibration returns the calibrated friction values per pipe manufacturer, pipe type and lubricant
+func loadFrictionCalibration() ([]FrictionCalibration, error) {
+	var rows []FrictionCalibration
+	err := db.Select(&rows, `
+		SELECT pipe_manufacturer, pipe_type, cable_lubricant, protocol_count,
+		       avg_friction, stddev_friction, min_friction, max_friction
+		FROM friction_calibration_view
+		ORDER BY protocol_count DESC, pipe_manufacturer, pipe_type, cable_lubricant`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load friction calibration: %v", err)
+	}
+	return rows, nil
+}
+
+// loadFrictionDistribution returns the spread of fitted friction coefficients across
+// stored protocols with the given pipe type, or nil if fewer than two were fitted
+func loadFrictionDistribution(pipeType string) (*simulator.Distribution, int, error) {
+	var row struct {
+		Count  int             `db:"protocol_count"`
+		Mean   sql.NullFloat64 `db:"avg_friction"`
+		StdDev sql.NullFloat64 `db:"stddev_friction"`
+		Min    sql.NullFloat64 `db:"min_friction"`
+		Max    sql.NullFloat64 `db:"max_friction"`
+	}
+	err := db.Get(&row, `
+		SELECT COUNT(*) AS protocol_count, AVG(f.friction_coefficient) AS avg_friction,
+		       STDDEV_SAMP(f.friction_coefficient) AS stddev_friction,
+		       MIN(f.friction_coefficient) AS min_friction, MAX(f.friction_coefficient) AS max_friction
+		FROM protocol_friction_fit f
+		JOIN protocol_equipment pe ON pe.protocol_id = f.protocol_id
+		JOIN protocols p ON p.id = f.protocol_id
+		WHERE pe.pipe_type = $1 AND f.fit_error IS NULL AND NOT p.is_training`, pipeType)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load friction distribution: %v", err)
+	}
+	if row.Count < 2 || !row.StdDev.Valid {
+		return nil, row.Count, nil
+	}
+	return &simulator.Distribution{
+		Mean:   row.Mean.Float64,
+		StdDev: row.StdDev.Float64,
+		Min:    row.Min.Float64,
+		Max:    row.Max.Float64,
+	}, row.Count, nil
+}
+
+// FitFrictionHandler (re)fits the friction coefficient of a protocol and returns the result
+// as JSON. With view=true it redirects to the protocol, which shows the fit or its error.
+func FitFrictionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid protocol ID", http.StatusBadRequest)
+		return
+	}
+
+	fit, err := FitProtocolFriction(id)
+	if r.FormValue("view") == "true" {
+		http.Redirect(w, r, "/protocols/view?id="+strconv.Itoa(id), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Friction fit failed: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fit)
+}
+
+// FrictionCalibrationHandler returns the calibrated friction values as JSON
+func FrictionCalibrationHandler(w http.ResponseWriter, r *http.Request) {
+	rows, err := loadFrictionCalibration()
+	if err != nil {
+		http.Error(w, "Error fetching friction calibration: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rows == nil {
+		rows = []FrictionCalibration{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rows)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"blowing-simulator/internal/simulator"
+)
+
+// findProtocolForMachineLog returns the stored protocol a machine log belongs to,
+// chosen by simulator.MachineLog.MatchProtocol among the protocols of its format and date
+func findProtocolForMachineLog(l *simulator.MachineLog) (int, bool, error) {
+	started, ok := l.StartedAt()
+	if !ok {
+		return 0, false, nil
+	}
+
+	var candidates []simulator.MachineLogCandidate
+	err := db.Select(&candidates, `
+		SELECT p.id, COALESCE(p.start_time::text, '') AS start_time,
+		       COALESCE(TRIM(pe.controller_sn), '') AS controller_sn,
+		       COALESCE(MAX(m.length_m), 0) AS max_length
+		FROM protocols p
+		LEFT JOIN protocol_equipment pe ON pe.protocol_id = p.id
+		LEFT JOIN protocol_measurements m ON m.protocol_id = p.id
+		WHERE p.protocol_type = $1 AND p.protocol_date = $2
+		GROUP BY p.id, p.start_time, pe.controller_sn`,
+		l.Format, started.Format("2006-01-02"))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to find protocol for machine log: %v", err)
+	}
+	id, found := l.MatchProtocol(candidates)
+	return id, found, nil
+}
+
+// attachMachineLog replaces the measurements of a protocol by the rows of its machine log
+func attachMachineLog(tx *sql.Tx, protocolID int, l *simulator.MachineLog) error {
+	if _, err := tx.Exec("DELETE FROM protocol_measurements WHERE protocol_id = $1", protocolID); err != nil {
+		return fmt.Errorf("failed to delete measurements: %v", err)
+	}
+	if _, err := tx.Exec("DELETE FROM protocol_source_columns WHERE protocol_id = $1", protocolID); err != nil {
+		return fmt.Errorf("failed to delete source columns: %v", err)
+	}
+	if err := saveMeasurementSeries(tx, protocolID, l.MeasurementSeries()); err != nil {
+		return err
+	}
+	// The friction fit of the PDF table is redone from the log after the import
+	if _, err := tx.Exec("DELETE FROM protocol_friction_fit WHERE protocol_id = $1", protocolID); err != nil {
+		return fmt.Errorf("failed to delete friction fit: %v", err)
+	}
+	return recordMachineLog(tx, protocolID, l)
+}
+
+// recordMachineLog stores the file name and import time of the log a protocol's measurements come from
+func recordMachineLog(tx *sql.Tx, protocolID int, l *simulator.MachineLog) error {
+	if _, err := tx.Exec(`
+		UPDATE protocols SET log_filename = $1, log_imported_at = NOW(), updated_at = NOW()
+		WHERE id = $2`, l.SourceFilename, protocolID); err != nil {
+		return fmt.Errorf("failed to record machine log: %v", err)
+	}
+	return nil
+}
+
+// importMachineLog stores a parsed machine log: attached to the matching PDF
+// protocol if there is one, otherwise (or with newProtocol) as a protocol of its
+// own. The measurements and the log's parse diagnostics are written in one
+// transaction, so a failed import leaves no partial protocol behind.
+func importMachineLog(l *simulator.MachineLog, parsed *simulator.ParseResult, newProtocol bool) (int, bool, error) {
+	protocolID, attached := 0, false
+	if !newProtocol {
+		var err error
+		if protocolID, attached, err = findProtocolForMachineLog(l); err != nil {
+			return 0, false, err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if attached {
+		err = attachMachineLog(tx, protocolID, l)
+	} else if protocolID, err = saveProtocolTx(tx, l.Protocol(), l.MeasurementSeries()); err == nil {
+		err = recordMachineLog(tx, protocolID, l)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if err := replaceParseDiagnostics(tx, protocolID, parsed); err != nil {
+		return 0, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	if attached {
+		log.Printf("importMachineLog: %s attached to protocol ID %d (%d measurements)", l.SourceFilename, protocolID, l.DataPointCount())
+	} else {
+		if err := linkProtocolToCatalog(db, protocolID); err != nil {
+			log.Printf("importMachineLog: Catalog matching failed for protocol ID %d: %v", protocolID, err)
+		}
+		log.Printf("importMachineLog: %s saved as new protocol ID %d (%d measurements)", l.SourceFilename, protocolID, l.DataPointCount())
+	}
+	fitSavedProtocol(protocolID)
+	return protocolID, attached, nil
+}
+
+// processMachineLog parses one uploaded machine log and, with save, imports it
+func processMachineLog(filename string, content []byte, save, newProtocol bool, opts simulator.ParseOptions) map[string]interface{} {
+	result := map[string]interface{}{
+		"filename": filename,
+		"success":  false,
+	}
+
+	l, parsed, err := simulator.ParseMachineLog(string(content), filename, opts)
+	if parsed != nil {
+		result["diagnostics"] = parsed
+	}
+	if err != nil {
+		result["error"] = "Machine log parsing failed: " + err.Error()
+		log.Printf("Machine log error - %s: %v", filename, err)
+		return result
+	}
+	result["format"] = l.Format
+	result["machine_log"] = true
+	result["controller_sn"] = l.ControllerSN
+	result["measurements"] = l.DataPointCount()
+	log.Printf("Machine log - %s: %s log parsed - %d measurements, controller S/N %q, start %s %s",
+		filename, l.Format, l.DataPointCount(), l.ControllerSN, l.Date, l.StartTime)
+
+	if save && db != nil {
+		protocolID, attached, err := importMachineLog(l, parsed, newProtocol)
+		if err != nil {
+			result["error"] = "Database save failed: " + err.Error()
+			log.Printf("Machine log error - %s: database save failed - %v", filename, err)
+			return result
+		}
+		result["saved"] = true
+		result["protocol_id"] = protocolID
+		result["attached"] = attached
+	}
+
+	result["success"] = true
+	return result
+}
+
+// ImportMachineLogHandler imports raw controller logs (POST multipart "logFiles").
+// Each log is attached to the PDF protocol with the same controller S/N and
+// start time, or saved as a new protocol if there is none or mode=new.
+// With view=true a single imported log redirects to its protocol.
+func ImportMachineLogHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+	if err := r.ParseMultipartForm(100 << 20); err != nil {
+		http.Error(w, "Error parsing form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	files := r.MultipartForm.File["logFiles"]
+	if len(files) == 0 {
+		http.Error(w, "No log files uploaded (logFiles)", http.StatusBadRequest)
+		return
+	}
+	newProtocol := r.FormValue("mode") == "new"
+	opts := simulator.ParseOptions{Strict: r.FormValue("strict") == "true"}
+
+	results := make([]map[string]interface{}, 0, len(files))
+	for _, fh := range files {
+		file, err := fh.Open()
+		if err != nil {
+			results = append(results, map[string]interface{}{"filename": fh.Filename, "success": false, "error": "Error opening file: " + err.Error()})
+			continue
+		}
+		content, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			results = append(results, map[string]interface{}{"filename": fh.Filename, "success": false, "error": "Error reading file: " + err.Error()})
+			continue
+		}
+		results = append(results, processMachineLog(fh.Filename, content, true, newProtocol, opts))
+	}
+
+	if r.FormValue("view") == "true" && len(results) == 1 {
+		if id, ok := results[0]["protocol_id"].(int); ok {
+			http.Redirect(w, r, "/protocols/view?id="+strconv.Itoa(id), http.StatusSeeOther)
+			return
+		}
+		if msg, ok := results[0]["error"].(string); ok {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
@@ -1,8 +1,8 @@
 package main
 
 import (
+	"blowing-simulator/internal/pdftext"
 	"blowing-simulator/internal/simulator"
-	"bytes"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/csv"
@@ -13,7 +13,6 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -24,7 +23,6 @@ import (
 
 	"github.com/jung-kurt/gofpdf"
 	"github.com/jmoiron/sqlx"
-	"github.com/ledongthuc/pdf"
 	_ "github.com/lib/pq"
 )
 
@@ -141,45 +139,6 @@ func CreateJettingHandler(w http.ResponseWriter, r *http.Request) {
 	tmpl.Execute(w, nil)
 }
 
-func extractTextWithGoLib(pdfPath string) (string, error) {
-	f, r, err := pdf.Open(pdfPath)
-	if err != nil {
-		return "", err
-	}
-	defer f.Close()
-	var buf bytes.Buffer
-	b, err := r.GetPlainText()
-	if err != nil {
-		return "", err
-	}
-	_, err = buf.ReadFrom(b)
-	if err != nil {
-		return "", err
-	}
-	return buf.String(), nil
-}
-
-// Extract text using pdftotext CLI tool
-func extractTextWithPdftotext(pdfPath string) (string, error) {
-	txtPath := pdfPath + ".pdftotext.txt"
-	cmd := "pdftotext"
-	args := []string{"-layout", pdfPath, txtPath}
-	if err := runCommand(cmd, args); err != nil {
-		return "", err
-	}
-	data, err := os.ReadFile(txtPath)
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
-}
-
-// Helper to run external command
-func runCommand(cmd string, args []string) error {
-	c := exec.Command(cmd, args...)
-	return c.Run()
-}
-
 func Pdf2TextHandler(w http.ResponseWriter, r *http.Request) {
 	tmpl := template.New("pdf2text.html").Funcs(template.FuncMap{
 		"formatTime": func(t string) string {
@@ -194,7 +153,7 @@ func Pdf2TextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if r.Method == http.MethodGet {
-		tmpl.Execute(w, nil)
+		tmpl.Execute(w, map[string]interface{}{"Time": "", "Formats": protocolFormats()})
 		return
 	}
 	// Handle POST (single file)
@@ -224,284 +183,95 @@ func Pdf2TextHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Cannot save PDF", http.StatusInternalServerError)
 		return
 	}
-	baseName := strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
-	// Support both Jetting and Fremco filename formats
-	var date, time, address, nvt, project string
-	if strings.Contains(baseName, ",") {
-		// Jetting format: "29.10.2025, 11 20, Eiermarkt 15 B NVT 1V2200"
-		parts := strings.Split(baseName, ",")
-		   if len(parts) >= 3 {
-			   // Try to extract date from filename, fallback to content if missing
-			   dateFromFilename := strings.TrimSpace(parts[0])
-			   if dateFromFilename != "" {
-				   date = dateFromFilename
-			   }
-			   timeRaw := strings.TrimSpace(parts[1])
-			   if strings.Contains(timeRaw, " ") {
-				   timeParts := strings.Fields(timeRaw)
-				   if len(timeParts) == 2 {
-					   time = timeParts[0] + ":" + timeParts[1]
-				   }
-			   } else {
-				   time = timeRaw
-			   }
-			   rest := strings.TrimSpace(parts[2])
-			   nvtIdx := strings.Index(rest, "NVT ")
-			   if nvtIdx != -1 {
-				   address = strings.TrimSpace(rest[:nvtIdx])
-				   nvt = strings.TrimSpace(rest[nvtIdx+4:])
-			   } else {
-				   address = rest
-			   }
-		   }
-		log.Printf("[Jetting] Parsed filename: %s | Date: %s | Time: %s | Address: %s | NVT: %s", baseName, date, time, address, nvt)
-	} else {
-		// Fremco format: "SM209214964_2025-10-22 10_51_Oldenburger Koppel_10_NVT1V3400"
-		parts := strings.Split(baseName, "_")
-		if len(parts) >= 4 {
-			project = parts[0]
-			
-			// Parse date and time from second part "2025-10-22 10"
-			dateTimePart := parts[1]
-			if strings.Contains(dateTimePart, " ") {
-				dateTimeFields := strings.Fields(dateTimePart)
-				if len(dateTimeFields) >= 2 {
-					// Date part: "2025-10-22"
-					dateParts := strings.Split(dateTimeFields[0], "-")
-					if len(dateParts) == 3 {
-						date = dateParts[2] + "." + dateParts[1] + "." + dateParts[0] // Convert to DD.MM.YYYY
-					}
-					// Time part: combine hour from dateTimePart and minutes from parts[2]
-					hour := dateTimeFields[1]
-					minute := parts[2]
-					time = hour + ":" + minute
-				}
-			}
-			
-			// Find NVT part (starts with "NVT")
-			nvtIndex := -1
-			for i, part := range parts {
-				if strings.HasPrefix(part, "NVT") {
-					nvtIndex = i
-					nvt = part
-					break
-				}
-			}
-			
-			// Address is everything between time part and NVT
-			if nvtIndex > 3 {
-				addressParts := parts[3:nvtIndex]
-				address = strings.TrimSpace(strings.Join(addressParts, " "))
-			}
-		}
-		log.Printf("[Fremco] Parsed filename: %s | Project: %s | Date: %s | Time: %s | Address: %s | NVT: %s", baseName, project, date, time, address, nvt)
-	}
+	info := simulator.ParseProtocolFilename(header.Filename)
+	log.Printf("Pdf2TextHandler: Parsed filename: %s | Project: %s | Date: %s | Time: %s | Address: %s | NVT: %s",
+		header.Filename, info.Project, info.Date, info.Time, info.Address, info.NVT)
 
-	tempTxt := filepath.Join(os.TempDir(), baseName+".txt")
-	format := r.FormValue("format")
-	var normalized string
-	var jsonOutput []byte
-
-	// Try Go-native extraction first
-	text, err := extractTextWithGoLib(tempPdf)
+	rawStr, err := pdftext.ExtractText(tempPdf)
 	if err != nil {
 		http.Error(w, "PDF extraction failed: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	err = os.WriteFile(tempTxt, []byte(text), 0644)
-	if err != nil {
-		http.Error(w, "Cannot save extracted text", http.StatusInternalServerError)
-		return
+	log.Printf("Pdf2TextHandler: Extracted %d characters", len(rawStr))
+
+	// Detect the format; a format chosen in the form overrides detection, e.g. for files held for review
+	detection := simulator.DetectFormat(rawStr, header.Filename)
+	parser := detection.Parser
+	if detection.Review {
+		parser = nil
+		log.Printf("Pdf2TextHandler: %s held for review: %s", header.Filename, detection.ReviewReason)
 	}
-	raw, err := os.ReadFile(tempTxt)
-	if err != nil {
-		http.Error(w, "Cannot read extracted text", http.StatusInternalServerError)
-		return
+	if chosen := simulator.LookupParser(r.FormValue("format")); chosen != nil {
+		parser = chosen
 	}
-	rawStr := string(raw)
-
-	var measurements []simulator.SimpleMeasurement
-	var jettingMeasurements []simulator.JettingMeasurement
-	var pdfMeta map[string]string
-	var fremcoProtocol *simulator.FremcoProtocol
-	var jettingProtocol *simulator.JettingProtocol
-	
-	// Check for Fremco first (most specific indicators)
-	if (strings.Contains(rawStr, "Streckenabschnitt") && strings.Contains(rawStr, "Einblasgerät")) || 
-	   strings.Contains(rawStr, "Fremco") || 
-	   (strings.Contains(rawStr, "Streckenlänge [m]") && strings.Contains(rawStr, "Geschwindigkeit [m/min]") && strings.Contains(rawStr, "Rohr-Druck [bar]") && strings.Contains(rawStr, "Drehmoment [%]")) {
-		// Fremco format detected, use pdftotext extraction
-		text, err := extractTextWithPdftotext(tempPdf)
+	var normalized, format string
+	jsonOutput := []byte("[]")
+	var protocolID int
+	var fremcoMeta map[string]string
+	var diagnostics []simulator.Diagnostic
+	if parser != nil {
+		format = parser.Name()
+		log.Printf("Pdf2TextHandler: Parsing as %s (detected %s, confidence %.2f)", format, detection.Format, detection.Confidence)
+		result, norm, err := simulator.ParseWith(parser, rawStr, simulator.ParseOptions{})
+		normalized = norm
 		if err != nil {
-			http.Error(w, "pdftotext extraction failed: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-		normalized = simulator.NormalizeFremcoTxt(text)
-		log.Println("Normalized text (Fremco/pdftotext):")
-		log.Println(normalized)
-		
-		// Parse comprehensive protocol data
-		fremcoProtocol = simulator.ParseFremcoProtocol(normalized)
-		// Fill in filename-based metadata
-		   if fremcoProtocol != nil {
-			   fremcoProtocol.ProtocolInfo.ProjectNumber = project
-			   // Prefer date from filename, fallback to parsed content if missing
-			   if date != "" {
-				   fremcoProtocol.ProtocolInfo.Date = date
-			   }
-			   fremcoProtocol.ProtocolInfo.StartTime = time
-			   fremcoProtocol.ProtocolInfo.SectionNVT = address + " / " + nvt
-			   fremcoProtocol.ExportMetadata.SourceFilename = header.Filename
-		   }
-		
-		measurements = simulator.ParseFremcoSimple(normalized)
-		log.Printf("Parsed measurements (Fremco): %+v\n", measurements)
-		log.Printf("Comprehensive protocol (Fremco): %+v\n", fremcoProtocol)
-		jsonOutput, _ = json.MarshalIndent(measurements, "", "  ")
-		if string(jsonOutput) == "null" {
-			jsonOutput = []byte("[]")
-		}
-		format = "fremco"
-	} else if strings.Contains(rawStr, "Länge") && strings.Contains(rawStr, "Schubkraft") {
-		// Old Jetting format detected (vertical block format), use Go-native extraction
-		pdfMeta = simulator.ExtractJettingMetadata(rawStr)
-		normalized = simulator.NormalizeJettingTxt(rawStr)
-		log.Println("Normalized text (Old Jetting):")
-		log.Println(normalized)
-		
-		// Parse comprehensive protocol data
-		jettingProtocol = simulator.ParseJettingProtocol(normalized)
-		// Fill in filename-based metadata
-		   if jettingProtocol != nil {
-			   // Prefer date from filename, fallback to parsed content if missing
-			   if date != "" {
-				   jettingProtocol.ProtocolInfo.Date = date
-			   }
-			   jettingProtocol.ProtocolInfo.StartTime = time
-			   jettingProtocol.ProtocolInfo.SectionNVT = address + " / " + nvt
-			   jettingProtocol.ExportMetadata.SourceFilename = header.Filename
-		   }
-		
-		jettingMeasurements = simulator.ParseJettingTxt(normalized)
-		log.Printf("Parsed measurements (Old Jetting): %+v\n", jettingMeasurements)
-		log.Printf("Comprehensive protocol (Jetting): %+v\n", jettingProtocol)
-		jsonOutput, _ = json.MarshalIndent(jettingMeasurements, "", "  ")
-		if string(jsonOutput) == "null" {
-			jsonOutput = []byte("[]")
-		}
-		format = "jetting"
-	} else if strings.Contains(rawStr, "Streckenlänge") && strings.Contains(rawStr, "Drehmoment") && !strings.Contains(rawStr, "Fremco") {
-		// New Jetting format detected (Messwerte Tabelle), use Go-native extraction
-		pdfMeta = simulator.ExtractJettingMetadata(rawStr)
-		normalized = simulator.NormalizeJettingTxt(rawStr)
-		log.Println("Normalized text (New Jetting - Messwerte Tabelle):")
-		log.Println(normalized)
-		jettingMeasurements = simulator.ParseJettingTxt(normalized)
-		log.Printf("Parsed measurements (New Jetting): %+v\n", jettingMeasurements)
-		jsonOutput, _ = json.MarshalIndent(jettingMeasurements, "", "  ")
-		if string(jsonOutput) == "null" {
-			jsonOutput = []byte("[]")
-		}
-		format = "jetting"
-	} else {
-		// Fallback to user selection
-		if format == "jetting" {
-			normalized = simulator.NormalizeJettingTxt(rawStr)
-			log.Println("Normalized text (Jetting fallback):")
-			log.Println(normalized)
-			jettingMeasurements = simulator.ParseJettingTxt(normalized)
-			log.Printf("Parsed measurements (Jetting fallback): %+v\n", jettingMeasurements)
-			jsonOutput, _ = json.MarshalIndent(jettingMeasurements, "", "  ")
+			log.Printf("Pdf2TextHandler: %s parsing failed: %v", format, err)
 		} else {
-			// Fallback to Fremco, use pdftotext
-			text, err := extractTextWithPdftotext(tempPdf)
-			if err != nil {
-				http.Error(w, "pdftotext extraction failed: "+err.Error(), http.StatusInternalServerError)
-				return
+			protocol := result.Protocol
+			diagnostics = result.Diagnostics()
+			protocol.ApplyFilenameInfo(info)
+			if data, err := json.MarshalIndent(protocol.MeasurementSeries().Measurements, "", "  "); err == nil {
+				jsonOutput = data
+			}
+			if _, ok := protocol.(*simulator.FremcoProtocol); ok {
+				fremcoMeta = simulator.ExtractFremcoMetadata(normalized)
 			}
-			normalized = simulator.NormalizeFremcoTxt(text)
-			log.Println("Normalized text (Fremco fallback/pdftotext):")
-			log.Println(normalized)
-			measurements = simulator.ParseFremcoSimple(normalized)
-			log.Printf("Parsed measurements (Fremco fallback): %+v\n", measurements)
-			jsonOutput, _ = json.MarshalIndent(measurements, "", "  ")
-		}
-	}
-
-	// Calculate last and sum
-	var lastMeasurement map[string]interface{}
-	var sumMeasurement map[string]interface{}
-	var totalLength float64
-	if len(measurements) > 0 {
-		last := measurements[len(measurements)-1]
-		lastMeasurement = map[string]interface{}{
-			"SourceFile": "last",
-			"Length":     last.Length,
-			"Speed":      last.Speed,
-			"Pressure":   last.Pressure,
-			"Torque":     last.Torque,
-			"Time":       "", // Add time if available
-		}
-		var totalSpeed, totalPressure, totalTorque float64
-		for _, m := range measurements {
-			totalLength += m.Length
-			totalSpeed += m.Speed
-			totalPressure += m.Pressure
-			totalTorque += m.Torque
-		}
-		sumMeasurement = map[string]interface{}{
-			"Length":   totalLength,
-			"Speed":    totalSpeed,
-			"Pressure": totalPressure,
-			"Torque":   totalTorque,
-		}
-	}
-	var fremcoMeta map[string]string
-	if format == "fremco" {
-		fremcoMeta = simulator.ExtractFremcoMetadata(normalized)
-	}
 
-	// Save protocol data to database
-	var protocolID int
-	var dbErr error
-	if fremcoProtocol != nil {
-		protocolID, dbErr = SaveFremcoProtocol(db, fremcoProtocol)
-		if dbErr != nil {
-			log.Printf("Failed to save Fremco protocol to database: %v", dbErr)
-		} else {
-			log.Printf("Successfully saved Fremco protocol with ID: %d", protocolID)
-		}
-	} else if jettingProtocol != nil {
-		protocolID, dbErr = SaveJettingProtocol(db, jettingProtocol)
-		if dbErr != nil {
-			log.Printf("Failed to save Jetting protocol to database: %v", dbErr)
-		} else {
-			log.Printf("Successfully saved Jetting protocol with ID: %d", protocolID)
+			// Save protocol data to database
+			protocolID, err = SaveProtocol(db, protocol)
+			if err != nil {
+				log.Printf("Failed to save %s protocol to database: %v", format, err)
+			} else {
+				log.Printf("Successfully saved %s protocol with ID: %d", format, protocolID)
+				if err := saveParseDiagnostics(protocolID, result); err != nil {
+					log.Printf("Pdf2TextHandler: %v", err)
+				}
+				fitSavedProtocol(protocolID)
+			}
 		}
+	} else {
+		normalized = simulator.NormalizeFremcoTxt(rawStr)
+		log.Printf("Pdf2TextHandler: Not parsed, format of %s needs review", header.Filename)
 	}
 
 	tmpl.Execute(w, map[string]interface{}{
-		"Text":            rawStr,
-		"Normalized":      normalized,
-		"JSON":            string(jsonOutput),
-		"Format":          format,
-		"LastMeasurement": lastMeasurement,
-		"SumMeasurement":  sumMeasurement,
-		"TotalLength":     totalLength,
-		"Date":            date,
-		"Time":            time,
-		"Address":         address,
-		"NVT":             nvt,
-		"Project":         project,
-		"Filename":        header.Filename,
-		"PDFDate":         pdfMeta["Date"],
-		"PDFTime":         pdfMeta["Time"],
-		"PDFAddress":      pdfMeta["Address"],
-		"PDFNVT":          pdfMeta["NVT"],
-		"FremcoMeta":      fremcoMeta,
+		"Text":        rawStr,
+		"Normalized":  normalized,
+		"JSON":        string(jsonOutput),
+		"Format":      format,
+		"Date":        info.Date,
+		"Time":        info.Time,
+		"Address":     info.Address,
+		"NVT":         info.NVT,
+		"Project":     info.Project,
+		"Filename":    header.Filename,
+		"FremcoMeta":  fremcoMeta,
+		"ProtocolID":  protocolID,
+		"Detection":   detection,
+		"Formats":     protocolFormats(),
+		"Diagnostics": diagnostics,
 	})
 }
 
+// protocolFormats returns the names of the registered protocol parsers
+func protocolFormats() []string {
+	var names []string
+	for _, p := range simulator.Parsers() {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
 func IndexHandler(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, "web/templates/index.html")
 }
@@ -583,6 +353,13 @@ func main() {
 	}
 	
 	log.Println("Successfully connected to database")
+	if err := seedCatalog(db, catalogSeedFile); err != nil {
+		log.Printf("Warning: Could not seed catalog: %v", err)
+	}
+	companiesFile := getEnvOrDefault("KNOWN_COMPANIES_FILE", "schema/known_companies.json")
+	if err := simulator.LoadKnownCompanies(companiesFile); err != nil {
+		log.Printf("Warning: Could not load known companies, using built-in list: %v", err)
+	}
 	
 	http.HandleFunc("/", IndexHandler)
 	http.HandleFunc("/download-json", DownloadJSONHandler)
@@ -601,9 +378,30 @@ func main() {
 	http.HandleFunc("/protocols", ProtocolsHandler)
 	http.HandleFunc("/protocols/view", ViewProtocolHandler)
 	http.HandleFunc("/protocols/measurements", ProtocolMeasurementsHandler)
+	http.HandleFunc("/protocols/measurements/export", ExportMeasurementsHandler)
 	http.HandleFunc("/protocols/length-report", LengthReportHandler)
+	http.HandleFunc("/protocols/fit-friction", FitFrictionHandler)
+	http.HandleFunc("/protocols/friction-calibration", FrictionCalibrationHandler)
+	http.HandleFunc("/protocols/compare-simulation", CompareSimulationHandler)
+	http.HandleFunc("/protocols/compressor-check", CompressorCheckHandler)
+	http.HandleFunc("/protocols/replay", ReplayProtocolHandler)
+	http.HandleFunc("/protocols/import-log", ImportMachineLogHandler)
 	http.HandleFunc("/bulk-upload", BulkUploadHandler)
 	http.HandleFunc("/debug-pdf", DebugPDFHandler)
+	http.HandleFunc("/simulate", SimulateHandler)
+	http.HandleFunc("/simulate/monte-carlo", MonteCarloHandler)
+	http.HandleFunc("/sweep", SweepHandler)
+	http.HandleFunc("/training", TrainingHandler)
+	http.HandleFunc("/training/sessions", TrainingSessionsHandler)
+	http.HandleFunc("/training/sessions/action", TrainingActionHandler)
+	http.HandleFunc("/training/sessions/finish", TrainingFinishHandler)
+	http.HandleFunc("/catalog/cables", CatalogCablesHandler)
+	http.HandleFunc("/catalog/ducts", CatalogDuctsHandler)
+	http.HandleFunc("/catalog/relink", CatalogRelinkHandler)
+	http.HandleFunc("/compressors", CompressorsHandler)
+	http.HandleFunc("/routes", RoutesHandler)
+	http.HandleFunc("/routes/view", ViewRouteHandler)
+	http.HandleFunc("/routes/import", ImportRouteHandler)
 	http.HandleFunc("/health", HealthCheckHandler)
 	log.Println("Server started at http://0.0.0.0:8080/")
 	log.Println("Available routes:")
@@ -611,6 +409,27 @@ func main() {
 	log.Println("  GET /protocols")
 	log.Println("  GET /protocols/view?id=X")
 	log.Println("  GET /protocols/measurements?id=X")
+	log.Println("  GET /protocols/measurements/export?id=X[&format=json]")
+	log.Println("  POST /protocols/fit-friction?id=X")
+	log.Println("  GET /protocols/friction-calibration")
+	log.Println("  GET /protocols/compare-simulation?id=X")
+	log.Println("  GET /protocols/compressor-check[?id=X]")
+	log.Println("  GET /protocols/replay?id=X[&speed=N] (SSE)")
+	log.Println("  POST /protocols/import-log")
+	log.Println("  POST /simulate")
+	log.Println("  POST /simulate/monte-carlo")
+	log.Println("  GET/POST /sweep")
+	log.Println("  GET /training")
+	log.Println("  GET/POST/DELETE /training/sessions")
+	log.Println("  POST /training/sessions/action?id=X")
+	log.Println("  POST /training/sessions/finish?id=X[&save=true]")
+	log.Println("  GET/POST/PUT/DELETE /catalog/cables")
+	log.Println("  GET/POST/PUT/DELETE /catalog/ducts")
+	log.Println("  POST /catalog/relink")
+	log.Println("  GET /compressors")
+	log.Println("  GET /routes")
+	log.Println("  GET /routes/view?id=X")
+	log.Println("  POST /routes/import")
 	log.Println("  GET /health")
 	http.ListenAndServe(":8080", nil)
 }
@@ -627,6 +446,8 @@ type Protocol struct {
 	ServiceProvider sql.NullString `db:"service_provider"`
 	Operator        sql.NullString `db:"operator"`
 	SourceFilename  sql.NullString `db:"source_filename"`
+	SectionNVT      sql.NullString `db:"section_nvt"`
+	LogFilename     sql.NullString `db:"log_filename"`
 	CreatedAt       string         `db:"created_at"`
 }
 
@@ -802,7 +623,7 @@ func ViewProtocolHandler(w http.ResponseWriter, r *http.Request) {
 	err = db.Get(&protocol, `
 		SELECT id, protocol_type, system_name, protocol_date, start_time, 
 		       project_number, company, service_provider, operator, 
-		       source_filename, created_at::text 
+		       source_filename, section_nvt, log_filename, created_at::text 
 		FROM protocols WHERE id = $1`, id)
 	
 	if err != nil {
@@ -814,17 +635,17 @@ func ViewProtocolHandler(w http.ResponseWriter, r *http.Request) {
 	var equipment map[string]interface{}
 	equipmentRows, err := db.Query(`
 		SELECT device_model, controller_sn, compressor_model, pipe_manufacturer, 
-		       cable_manufacturer, cable_fiber_count, cable_diameter 
+		       cable_manufacturer, cable_fiber_count, cable_diameter, cable_drum_number 
 		FROM protocol_equipment WHERE protocol_id = $1`, id)
 	if err == nil {
 		defer equipmentRows.Close()
 		if equipmentRows.Next() {
-			var deviceModel, controllerSN, compressorModel, pipeManuf, cableManuf sql.NullString
+			var deviceModel, controllerSN, compressorModel, pipeManuf, cableManuf, drumNumber sql.NullString
 			var fiberCount sql.NullInt64
 			var cableDiam sql.NullFloat64
 			
 			equipmentRows.Scan(&deviceModel, &controllerSN, &compressorModel, 
-				&pipeManuf, &cableManuf, &fiberCount, &cableDiam)
+				&pipeManuf, &cableManuf, &fiberCount, &cableDiam, &drumNumber)
 			
 			equipment = map[string]interface{}{
 				"DeviceModel":      deviceModel.String,
@@ -834,6 +655,7 @@ func ViewProtocolHandler(w http.ResponseWriter, r *http.Request) {
 				"CableManufacturer": cableManuf.String,
 				"FiberCount":       fiberCount.Int64,
 				"CableDiameter":    cableDiam.Float64,
+				"DrumNumber":       drumNumber.String,
 			}
 		}
 	}
@@ -842,17 +664,17 @@ func ViewProtocolHandler(w http.ResponseWriter, r *http.Request) {
 	var summary map[string]interface{}
 	summaryRows, err := db.Query(`
 		SELECT total_distance, blowing_time, weather_temperature, 
-		       weather_humidity, gps_latitude, gps_longitude 
+		       weather_humidity, gps_latitude, gps_longitude, gps_altitude 
 		FROM protocol_summary WHERE protocol_id = $1`, id)
 	if err == nil {
 		defer summaryRows.Close()
 		if summaryRows.Next() {
 			var totalDist sql.NullInt64
 			var blowingTime sql.NullString
-			var weatherTemp, weatherHum, gpsLat, gpsLon sql.NullFloat64
+			var weatherTemp, weatherHum, gpsLat, gpsLon, gpsAlt sql.NullFloat64
 			
 			summaryRows.Scan(&totalDist, &blowingTime, &weatherTemp, 
-				&weatherHum, &gpsLat, &gpsLon)
+				&weatherHum, &gpsLat, &gpsLon, &gpsAlt)
 			
 			summary = map[string]interface{}{
 				"TotalDistance":      totalDist.Int64,
@@ -861,6 +683,7 @@ func ViewProtocolHandler(w http.ResponseWriter, r *http.Request) {
 				"WeatherHumidity":    weatherHum.Float64,
 				"GPSLatitude":        gpsLat.Float64,
 				"GPSLongitude":       gpsLon.Float64,
+				"GPSAltitude":        gpsAlt.Float64,
 			}
 		}
 	}
@@ -869,13 +692,54 @@ func ViewProtocolHandler(w http.ResponseWriter, r *http.Request) {
 	var measurementCount int
 	db.Get(&measurementCount, "SELECT COUNT(*) FROM protocol_measurements WHERE protocol_id = $1", id)
 	
+	// Get fitted friction parameters (fitted when the protocol is saved or refitted)
+	frictionFit, frictionFitError, err := loadFrictionFit(id)
+	if err != nil {
+		log.Printf("ViewProtocolHandler: %v", err)
+		frictionFitError = err.Error()
+	}
+	
+	// Check the recorded cable/duct combination
+	var fillCheck *simulator.FillCheck
+	if eq, err := loadProtocolEquipment(id); err == nil {
+		check := fillCheckFromEquipment(eq)
+		fillCheck = &check
+	}
+	
+	// Check whether the compressor could supply the duct
+	compressorCheck, err := checkProtocolCompressor(id)
+	if err != nil {
+		log.Printf("ViewProtocolHandler: Compressor check for protocol %d unavailable: %v", id, err)
+	}
+	
+	// Get planned route of the section
+	route, err := findRouteForSection(protocol.SectionNVT.String)
+	if err != nil {
+		log.Printf("ViewProtocolHandler: Route lookup for protocol %d failed: %v", id, err)
+	}
+	
+	// Get the values the parser could not read
+	diagnostics, err := loadParseDiagnostics(id)
+	if err != nil {
+		log.Printf("ViewProtocolHandler: Parse diagnostics for protocol %d unavailable: %v", id, err)
+	}
+	
 	// Render template
-	tmpl := template.Must(template.ParseFiles("web/templates/protocol-detail.html"))
+	funcMap := template.FuncMap{
+		"mulf": func(a, b float64) float64 { return a * b },
+	}
+	tmpl := template.Must(template.New("protocol-detail.html").Funcs(funcMap).ParseFiles("web/templates/protocol-detail.html"))
 	data := map[string]interface{}{
 		"Protocol":         protocol,
 		"Equipment":        equipment,
 		"Summary":          summary,
 		"MeasurementCount": measurementCount,
+		"FrictionFit":      frictionFit,
+		"FrictionFitError": frictionFitError,
+		"Route":            route,
+		"FillCheck":        fillCheck,
+		"CompressorCheck":  compressorCheck,
+		"Diagnostics":      diagnostics,
 	}
 	
 	err = tmpl.Execute(w, data)
@@ -927,16 +791,7 @@ func ProtocolMeasurementsHandler(w http.ResponseWriter, r *http.Request) {
 	offset := (page - 1) * limit
 	
 	// Get measurements with pagination
-	var measurements []ProtocolMeasurement
-	err = db.Select(&measurements, `
-		SELECT id, sequence_number, length_m, timestamp_value, speed_m_min, 
-		       pressure_bar, torque_percent, temperature_c, force_n, 
-		       time_duration, created_at::text 
-		FROM protocol_measurements 
-		WHERE protocol_id = $1 
-		ORDER BY COALESCE(sequence_number, id) ASC 
-		LIMIT $2 OFFSET $3`, id, limit, offset)
-	
+	measurements, err := loadProtocolMeasurementsPage(id, limit, offset)
 	if err != nil {
 		http.Error(w, "Error fetching measurements: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -1004,11 +859,13 @@ func LengthReportHandler(w http.ResponseWriter, r *http.Request) {
 	endDate := r.URL.Query().Get("end_date")
 	includeFremco := r.URL.Query().Get("fremco") == "on" || r.URL.Query().Get("fremco") == "true"
 	includeJetting := r.URL.Query().Get("jetting") == "on" || r.URL.Query().Get("jetting") == "true"
+	includePlumettaz := r.URL.Query().Get("plumettaz") == "on" || r.URL.Query().Get("plumettaz") == "true"
 	
-	// Default to both if none selected
-	if !includeFremco && !includeJetting {
+	// Default to all if none selected
+	if !includeFremco && !includeJetting && !includePlumettaz {
 		includeFremco = true
 		includeJetting = true
+		includePlumettaz = true
 	}
 	
 	// Build query
@@ -1029,7 +886,7 @@ func LengthReportHandler(w http.ResponseWriter, r *http.Request) {
 			p.created_at::text
 		FROM protocols p
 		LEFT JOIN protocol_measurements m ON p.id = m.protocol_id
-		WHERE 1=1`
+		WHERE NOT p.is_training`
 	
 	args := []interface{}{}
 	argCount := 0
@@ -1055,6 +912,9 @@ func LengthReportHandler(w http.ResponseWriter, r *http.Request) {
 	if includeJetting {
 		formatConditions = append(formatConditions, "p.protocol_type = 'jetting'")
 	}
+	if includePlumettaz {
+		formatConditions = append(formatConditions, "p.protocol_type = 'plumettaz'")
+	}
 	
 	if len(formatConditions) > 0 {
 		query += " AND (" + strings.Join(formatConditions, " OR ") + ")"
@@ -1165,6 +1025,7 @@ func LengthReportHandler(w http.ResponseWriter, r *http.Request) {
 		"EndDate":           endDate,
 		"IncludeFremco":     includeFremco,
 		"IncludeJetting":    includeJetting,
+		"IncludePlumettaz":  includePlumettaz,
 		"TotalProtocols":    totalProtocols,
 		"TotalMeasurements": totalMaxLengthSum,     // Now shows sum of max lengths
 		"MaxLengthOverall":  maxLengthOverall,
@@ -1186,102 +1047,6 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
-// isJettingFormatWithFilename detects format using both content and filename
-func isJettingFormatWithFilename(text string, filename string) bool {
-	// Check filename patterns first - strong indicator
-	filenameHints := 0
-	
-	// German Jetting filename pattern: "DD.MM.YYYY, HH MM, Location NVT XXXXX.pdf"
-	if strings.Contains(filename, "NVT") && strings.Contains(filename, ",") {
-		filenameHints += 3 // Strong Jetting indicator
-	}
-	
-	// Date pattern: DD.MM.YYYY
-	if matched, _ := regexp.MatchString(`\d{2}\.\d{2}\.\d{4}`, filename); matched {
-		filenameHints++
-	}
-	
-	// Time pattern: HH MM
-	if matched, _ := regexp.MatchString(`\d{2} \d{2}`, filename); matched {
-		filenameHints++
-	}
-	
-	return isJettingFormat(text) || filenameHints >= 3
-}
-
-// isJettingFormat detects if the text is from a Jetting protocol
-func isJettingFormat(text string) bool {
-	// Check for Jetting-specific indicators (more comprehensive)
-	jettingIndicators := 0
-	
-	// German language indicators (primary)
-	if strings.Contains(text, "Streckenlänge") { jettingIndicators += 2 }
-	if strings.Contains(text, "Geschwindigkeit") { jettingIndicators += 2 }
-	if strings.Contains(text, "Drehmoment") { jettingIndicators += 2 }
-	if strings.Contains(text, "Schubkraft") { jettingIndicators += 2 }
-	if strings.Contains(text, "Uhrzeit") { jettingIndicators += 1 }
-	
-	// Common patterns
-	if strings.Contains(text, "Länge") && strings.Contains(text, "m/min") { jettingIndicators += 2 }
-	if strings.Contains(text, "[m]") && strings.Contains(text, "[bar]") { jettingIndicators += 1 }
-	
-	// Additional German indicators
-	if strings.Contains(text, "Rohr-Druck") { jettingIndicators += 2 }
-	if strings.Contains(text, "Lufttemperatur") { jettingIndicators += 1 }
-	if strings.Contains(text, "Einblasdruck") { jettingIndicators += 1 }
-	
-	// Time format patterns (German)
-	if strings.Contains(text, "[hh:mm:ss]") { jettingIndicators++ }
-	if strings.Contains(text, "Zeit - Dauer") { jettingIndicators++ }
-	
-	// Unit patterns
-	if strings.Contains(text, "[°C]") { jettingIndicators++ }
-	if strings.Contains(text, "[N]") { jettingIndicators++ }
-	if strings.Contains(text, "[%]") { jettingIndicators++ }
-	
-	// Additional fallback patterns for difficult-to-detect files
-	if strings.Contains(text, "Protokoll") && (strings.Contains(text, "Meter") || strings.Contains(text, "Zeit")) {
-		jettingIndicators++
-	}
-	
-	// Very generic patterns as last resort
-	if strings.Contains(text, "bar") && strings.Contains(text, "min") {
-		jettingIndicators++
-	}
-	
-	// If text contains measurement-like patterns
-	if matched, _ := regexp.MatchString(`\d+[.,]\d+\s*(m|bar|°C|N|%)`, text); matched {
-		jettingIndicators++
-	}
-	
-	// Exclude Fremco files (strong exclusion)
-	if strings.Contains(text, "Fremco") { return false }
-	if strings.Contains(text, "SpeedNet") { return false }
-	if strings.Contains(text, "MicroFlow") { return false }
-	
-	// Very low threshold - be more permissive
-	return jettingIndicators >= 1
-}
-
-// isFremcoFormat detects if the text is from a Fremco protocol
-func isFremcoFormat(text string) bool {
-	// Check for Fremco-specific indicators (prioritized detection)
-	fremcoIndicators := 0
-	
-	// Primary indicators
-	if strings.Contains(text, "Fremco") { fremcoIndicators += 3 }
-	if strings.Contains(text, "SpeedNet") { fremcoIndicators += 2 }
-	if strings.Contains(text, "MicroFlow") { fremcoIndicators += 2 }
-	
-	// Secondary indicators
-	if strings.Contains(text, "Blowing distance") { fremcoIndicators++ }
-	if strings.Contains(text, "Blowing time") { fremcoIndicators++ }
-	if strings.Contains(text, "Streckenabschnitt") { fremcoIndicators++ }
-	if strings.Contains(text, "Equipment") && strings.Contains(text, "Serial") { fremcoIndicators++ }
-	
-	return fremcoIndicators >= 2
-}
-
 // truncateString helper function for logging
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -1379,115 +1144,37 @@ func debugPDFProcessing(filename string, content []byte) map[string]interface{}
 	}
 	tempFile.Close()
 
-	// Try pdftotext first
-	cmd := exec.Command("pdftotext", "-layout", tempFile.Name(), "-")
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	extractMethod := "pdftotext"
-	err = cmd.Run()
+	pages, err := pdftext.ExtractFile(tempFile.Name())
 	if err != nil {
-		result["pdftotext_error"] = stderr.String()
-		// Try Go library
-		text, err := extractTextWithGoLib(tempFile.Name())
-		if err != nil {
-			result["go_lib_error"] = err.Error()
-			result["extraction_failed"] = true
-			return result
-		}
-		stdout.WriteString(text)
-		extractMethod = "go-library"
+		result["extraction_error"] = err.Error()
+		result["extraction_failed"] = true
+		return result
 	}
-
-	rawText := stdout.String()
-	result["extraction_method"] = extractMethod
+	rawText := pdftext.Layout(pages)
+	result["pages"] = len(pages)
 	result["text_length"] = len(rawText)
 	result["text_sample"] = truncateString(rawText, 1000)
 
 	// Format detection analysis
-	isJetting := isJettingFormatWithFilename(rawText, filename)
-	isFremco := isFremcoFormat(rawText)
-	isJettingContentOnly := isJettingFormat(rawText)
-	
-	result["format_detection"] = map[string]interface{}{
-		"is_jetting": isJetting,
-		"is_jetting_content_only": isJettingContentOnly,
-		"is_fremco":  isFremco,
-		"jetting_indicators": analyzeJettingIndicators(rawText),
-		"fremco_indicators":  analyzeFremcoIndicators(rawText),
-		"filename_hints": analyzeFilenameHints(filename),
-	}
-
-	// Try parsing if format detected
-	if isJetting {
-		normalized := simulator.NormalizeJettingTxt(rawText)
-		result["normalized_sample"] = truncateString(normalized, 500)
-		protocol := simulator.ParseJettingProtocol(normalized)
-		if protocol != nil {
-			result["parsing_success"] = true
-			result["measurements"] = len(protocol.Measurements.DataPoints)
-		} else {
-			result["parsing_success"] = false
-		}
-	} else if isFremco {
-		normalized := simulator.NormalizeFremcoTxt(rawText)
+	detection := simulator.DetectFormat(rawText, filename)
+	result["format_detection"] = detection
+
+	// Try parsing with the best candidate, also when it is held for review
+	if detection.Parser != nil {
+		parsed, normalized, err := simulator.ParseWith(detection.Parser, rawText, simulator.ParseOptions{})
 		result["normalized_sample"] = truncateString(normalized, 500)
-		protocol := simulator.ParseFremcoProtocol(normalized)
-		if protocol != nil {
-			result["parsing_success"] = true
-			result["measurements"] = len(protocol.Measurements.DataPoints)
+		result["parsing_success"] = err == nil
+		if err != nil {
+			result["parsing_error"] = err.Error()
 		} else {
-			result["parsing_success"] = false
+			result["measurements"] = parsed.Protocol.DataPointCount()
+			result["diagnostics"] = parsed
 		}
 	}
 
 	return result
 }
 
-// analyzeJettingIndicators provides detailed analysis of Jetting format indicators
-func analyzeJettingIndicators(text string) map[string]bool {
-	return map[string]bool{
-		"has_streckenlänge": strings.Contains(text, "Streckenlänge"),
-		"has_geschwindigkeit": strings.Contains(text, "Geschwindigkeit"),
-		"has_drehmoment": strings.Contains(text, "Drehmoment"),
-		"has_schubkraft": strings.Contains(text, "Schubkraft"),
-		"has_uhrzeit": strings.Contains(text, "Uhrzeit"),
-		"has_länge": strings.Contains(text, "Länge"),
-		"has_m_min": strings.Contains(text, "m/min"),
-		"has_m_bracket": strings.Contains(text, "[m]"),
-		"has_bar_bracket": strings.Contains(text, "[bar]"),
-		"has_fremco": strings.Contains(text, "Fremco"),
-	}
-}
-
-// analyzeFremcoIndicators provides detailed analysis of Fremco format indicators  
-func analyzeFremcoIndicators(text string) map[string]bool {
-	return map[string]bool{
-		"has_fremco": strings.Contains(text, "Fremco"),
-		"has_speednet": strings.Contains(text, "SpeedNet"),
-		"has_microflow": strings.Contains(text, "MicroFlow"),
-		"has_blowing_distance": strings.Contains(text, "Blowing distance"),
-		"has_blowing_time": strings.Contains(text, "Blowing time"),
-		"has_streckenabschnitt": strings.Contains(text, "Streckenabschnitt"),
-		"has_equipment_serial": strings.Contains(text, "Equipment") && strings.Contains(text, "Serial"),
-	}
-}
-
-// analyzeFilenameHints provides detailed analysis of filename-based format indicators
-func analyzeFilenameHints(filename string) map[string]bool {
-	datePattern, _ := regexp.MatchString(`\d{2}\.\d{2}\.\d{4}`, filename)
-	timePattern, _ := regexp.MatchString(`\d{2} \d{2}`, filename)
-	
-	return map[string]bool{
-		"has_nvt": strings.Contains(filename, "NVT"),
-		"has_comma_separation": strings.Contains(filename, ","),
-		"has_date_pattern": datePattern,
-		"has_time_pattern": timePattern,
-		"probable_jetting_filename": strings.Contains(filename, "NVT") && strings.Contains(filename, ",") && datePattern,
-	}
-}
-
 // BulkUploadHandler serves the bulk upload page and handles bulk processing
 func BulkUploadHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "GET" {
@@ -1523,6 +1210,7 @@ func BulkUploadHandler(w http.ResponseWriter, r *http.Request) {
 		// Get options
 		autoSave := r.FormValue("autoSave") == "true"
 		skipExisting := r.FormValue("skipExisting") == "true"
+		opts := simulator.ParseOptions{Strict: r.FormValue("strict") == "true"}
 		
 		results := make([]map[string]interface{}, 0)
 		var mutex sync.Mutex
@@ -1554,7 +1242,7 @@ func BulkUploadHandler(w http.ResponseWriter, r *http.Request) {
 				// Check if file exists in database if skipExisting is enabled
 				if skipExisting && db != nil {
 					var count int
-					err = db.QueryRow("SELECT COUNT(*) FROM protocols WHERE source_filename = $1", fh.Filename).Scan(&count)
+					err = db.QueryRow("SELECT COUNT(*) FROM protocols WHERE source_filename = $1 OR log_filename = $1", fh.Filename).Scan(&count)
 					if err == nil && count > 0 {
 						mutex.Lock()
 						results = append(results, map[string]interface{}{
@@ -1582,7 +1270,7 @@ func BulkUploadHandler(w http.ResponseWriter, r *http.Request) {
 				}
 				
 				// Process the PDF
-				result := processBulkPDF(fh.Filename, content, autoSave)
+				result := processBulkPDF(fh.Filename, content, autoSave, opts)
 				
 				mutex.Lock()
 				results = append(results, result)
@@ -1605,8 +1293,52 @@ func BulkUploadHandler(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 }
 
-// processBulkPDF processes a single PDF file for bulk upload
-func processBulkPDF(filename string, content []byte, autoSave bool) map[string]interface{} {
+// addFillCheckResult adds the cable/duct fill check and its warnings to a bulk upload result
+func addFillCheckResult(result map[string]interface{}, check simulator.FillCheck) {
+	result["fill_check"] = check
+	if check.Status == simulator.FillStatusWarning || check.Status == simulator.FillStatusError {
+		result["warnings"] = check.Warnings
+		log.Printf("Bulk upload - %s: Fill check %s - %s", result["filename"], check.Status, strings.Join(check.Warnings, "; "))
+	}
+}
+
+// extractBulkPDFText extracts the text of an uploaded PDF through a temporary file
+func extractBulkPDFText(filename string, content []byte) (string, error) {
+	// Check PDF header
+	if !strings.HasPrefix(string(content[:4]), "%PDF") {
+		log.Printf("Bulk upload error - %s: Invalid PDF header", filename)
+		return "", fmt.Errorf("Not a valid PDF file (missing PDF header)")
+	}
+
+	// Save to temporary file
+	tempFile, err := os.CreateTemp("", "bulk_*.pdf")
+	if err != nil {
+		log.Printf("Bulk upload error - %s: Temp file creation failed - %v", filename, err)
+		return "", fmt.Errorf("Failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	if _, err := tempFile.Write(content); err != nil {
+		log.Printf("Bulk upload error - %s: Temp file write failed - %v", filename, err)
+		return "", fmt.Errorf("Failed to write temporary file: %v", err)
+	}
+	tempFile.Close()
+
+	rawText, err := pdftext.ExtractText(tempFile.Name())
+	if err != nil {
+		log.Printf("Bulk upload error - %s: %v", filename, err)
+		return "", err
+	}
+	if rawText == "" {
+		log.Printf("Bulk upload error - %s: No text extracted", filename)
+		return "", fmt.Errorf("No text extracted from PDF")
+	}
+	return rawText, nil
+}
+
+// processBulkPDF processes a single PDF report or CSV log for bulk upload
+func processBulkPDF(filename string, content []byte, autoSave bool, opts simulator.ParseOptions) map[string]interface{} {
 	result := map[string]interface{}{
 		"filename": filename,
 		"success":  false,
@@ -1625,57 +1357,17 @@ func processBulkPDF(filename string, content []byte, autoSave bool) map[string]i
 		return result
 	}
 	
-	// Check PDF header
-	if !strings.HasPrefix(string(content[:4]), "%PDF") {
-		result["error"] = "Not a valid PDF file (missing PDF header)"
-		log.Printf("Bulk upload error - %s: Invalid PDF header", filename)
-		return result
-	}
-	
-	// Save to temporary file
-	tempFile, err := os.CreateTemp("", "bulk_*.pdf")
-	if err != nil {
-		result["error"] = "Failed to create temporary file: " + err.Error()
-		log.Printf("Bulk upload error - %s: Temp file creation failed - %v", filename, err)
-		return result
-	}
-	defer os.Remove(tempFile.Name())
-	defer tempFile.Close()
-	
-	_, err = tempFile.Write(content)
-	if err != nil {
-		result["error"] = "Failed to write temporary file: " + err.Error()
-		log.Printf("Bulk upload error - %s: Temp file write failed - %v", filename, err)
-		return result
-	}
-	tempFile.Close()
-	
-	// Extract text using pdftotext first
-	cmd := exec.Command("pdftotext", "-layout", tempFile.Name(), "-")
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err = cmd.Run()
-	extractMethod := "pdftotext"
-	if err != nil {
-		log.Printf("Bulk upload - %s: pdftotext failed, trying Go library - %v", filename, err)
-		// Try Go PDF library as fallback
-		text, err := extractTextWithGoLib(tempFile.Name())
+	// Data logger CSV and LOG exports are text already, everything else must be a PDF
+	var rawText string
+	if ext := strings.ToLower(filepath.Ext(filename)); ext == ".csv" || ext == ".log" {
+		rawText = string(content)
+	} else {
+		text, err := extractBulkPDFText(filename, content)
 		if err != nil {
-			result["error"] = "Both PDF extraction methods failed: pdftotext: " + stderr.String() + ", Go library: " + err.Error()
-			log.Printf("Bulk upload error - %s: All extraction methods failed - pdftotext: %v, Go library: %v", filename, stderr.String(), err)
+			result["error"] = err.Error()
 			return result
 		}
-		stdout.WriteString(text)
-		extractMethod = "go-library"
-	}
-	
-	rawText := stdout.String()
-	if rawText == "" {
-		result["error"] = "No text extracted from PDF"
-		log.Printf("Bulk upload error - %s: No text extracted using %s", filename, extractMethod)
-		return result
+		rawText = text
 	}
 
 	if len(strings.TrimSpace(rawText)) < 50 {
@@ -1684,209 +1376,69 @@ func processBulkPDF(filename string, content []byte, autoSave bool) map[string]i
 		return result
 	}
 
-	log.Printf("Bulk upload - %s: Successfully extracted %d characters using %s", filename, len(rawText), extractMethod)
-	
-	// Detect format and parse (with filename hints)
-	isJetting := isJettingFormatWithFilename(rawText, filename)
-	isFremco := isFremcoFormat(rawText)
-	
-	log.Printf("Bulk upload - %s: Format detection - Jetting: %v, Fremco: %v", filename, isJetting, isFremco)
-	
-	if isJetting {
-		normalized := simulator.NormalizeJettingTxt(rawText)
-		protocol := simulator.ParseJettingProtocol(normalized)
-		if protocol == nil {
-			result["error"] = "Jetting parsing failed - unable to parse normalized text"
-			log.Printf("Bulk upload error - %s: Jetting parsing failed. First 200 chars of normalized text: %q", filename, truncateString(normalized, 200))
-			return result
-		}
-		
-		// Parse filename for metadata (Jetting format: "29.10.2025, 11 09, Eiemarkt 14 NVT 1V2200.pdf")
-		baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
-		var date, time, address, nvt string
-		if strings.Contains(baseName, ",") {
-			parts := strings.Split(baseName, ",")
-			if len(parts) >= 3 {
-				date = strings.TrimSpace(parts[0])
-				timeRaw := strings.TrimSpace(parts[1])
-				// Convert space-separated time to colon-separated (e.g., "11 09" -> "11:09")
-				if strings.Contains(timeRaw, " ") {
-					timeParts := strings.Fields(timeRaw)
-					if len(timeParts) == 2 {
-						time = timeParts[0] + ":" + timeParts[1]
-					}
-				} else {
-					time = timeRaw
-				}
-				rest := strings.TrimSpace(parts[2])
-				nvtIdx := strings.Index(rest, "NVT ")
-				if nvtIdx != -1 {
-					address = strings.TrimSpace(rest[:nvtIdx])
-					nvt = strings.TrimSpace(rest[nvtIdx+4:])
-				} else {
-					address = rest
-				}
-			}
-			log.Printf("Bulk upload - %s [Jetting] Parsed filename: Date: %s | Time: %s | Address: %s | NVT: %s", filename, date, time, address, nvt)
-		}
-		
-		// Fill in filename-based metadata
-		protocol.ExportMetadata.SourceFilename = filename
-		if date != "" {
-			protocol.ProtocolInfo.Date = date
-		}
-		if time != "" {
-			protocol.ProtocolInfo.StartTime = time
-		}
-		if address != "" && nvt != "" {
-			protocol.ProtocolInfo.SectionNVT = address + " / " + nvt
-		} else if address != "" {
-			protocol.ProtocolInfo.SectionNVT = address
-		}
-		
-		result["format"] = "jetting"
-		measurementCount := len(protocol.Measurements.DataPoints)
-		result["measurements"] = measurementCount
-		
-		log.Printf("Bulk upload - %s: Jetting protocol parsed successfully - %d measurements", filename, measurementCount)
-		
-		if autoSave && db != nil {
-			_, err := SaveJettingProtocol(db, protocol)
-			if err != nil {
-				result["error"] = "Database save failed: " + err.Error()
-				log.Printf("Bulk upload error - %s: Jetting database save failed - %v", filename, err)
-				return result
-			}
-			result["saved"] = true
-			log.Printf("Bulk upload - %s: Jetting protocol saved to database successfully", filename)
-		}
-	} else if isFremco {
-		normalized := simulator.NormalizeFremcoTxt(rawText)
-		protocol := simulator.ParseFremcoProtocol(normalized)
-		if protocol == nil {
-			result["error"] = "Fremco parsing failed - unable to parse normalized text"
-			log.Printf("Bulk upload error - %s: Fremco parsing failed. First 200 chars of normalized text: %q", filename, truncateString(normalized, 200))
+	log.Printf("Bulk upload - %s: Successfully extracted %d characters", filename, len(rawText))
+
+	// Raw Fremco and Jetting controller logs are attached to their PDF protocol
+	if format, ok := simulator.DetectMachineLog(rawText); ok {
+		log.Printf("Bulk upload - %s: Detected %s machine log", filename, format)
+		return processMachineLog(filename, []byte(rawText), autoSave, false, opts)
+	}
+
+	// Detect format (with filename hints); weak or ambiguous matches are held for review
+	detection := simulator.DetectFormat(rawText, filename)
+	result["detection"] = detection
+	if detection.Review {
+		result["review"] = true
+		result["error"] = "Format needs review: " + detection.ReviewReason
+		log.Printf("Bulk upload review - %s: %s. First 500 chars: %q", filename, detection.ReviewReason, truncateString(rawText, 500))
+		return result
+	}
+	parser := detection.Parser
+	format := parser.Name()
+	log.Printf("Bulk upload - %s: Detected format %s (confidence %.2f)", filename, format, detection.Confidence)
+
+	parsed, normalized, err := simulator.ParseWith(parser, rawText, opts)
+	if parsed != nil {
+		result["diagnostics"] = parsed
+	}
+	if err != nil {
+		result["error"] = fmt.Sprintf("%s parsing failed: %v", format, err)
+		log.Printf("Bulk upload error - %s: %s parsing failed. First 200 chars of normalized text: %q", filename, format, truncateString(normalized, 200))
+		return result
+	}
+	protocol := parsed.Protocol
+	if len(parsed.Errors) > 0 || len(parsed.Warnings) > 0 {
+		log.Printf("Bulk upload - %s: %d parse errors, %d warnings", filename, len(parsed.Errors), len(parsed.Warnings))
+	}
+
+	// Fill in filename-based metadata
+	info := simulator.ParseProtocolFilename(filename)
+	log.Printf("Bulk upload - %s [%s] Parsed filename: Project: %s | Date: %s | Time: %s | Address: %s | NVT: %s",
+		filename, format, info.Project, info.Date, info.Time, info.Address, info.NVT)
+	protocol.ApplyFilenameInfo(info)
+
+	result["format"] = format
+	measurementCount := protocol.DataPointCount()
+	result["measurements"] = measurementCount
+	addFillCheckResult(result, protocol.FillCheck())
+
+	log.Printf("Bulk upload - %s: %s protocol parsed successfully - %d measurements", filename, format, measurementCount)
+
+	if autoSave && db != nil {
+		protocolID, err := SaveProtocol(db, protocol)
+		if err != nil {
+			result["error"] = "Database save failed: " + err.Error()
+			log.Printf("Bulk upload error - %s: %s database save failed - %v", filename, format, err)
 			return result
 		}
-		
-		// Parse filename for metadata (Fremco format: "SM209214964_2025-10-22 10_51_Oldenburger Koppel_10_NVT1V3400.pdf")
-		baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
-		var date, time, address, nvt, project string
-		if strings.Contains(baseName, "_") {
-			parts := strings.Split(baseName, "_")
-			if len(parts) >= 4 {
-				project = parts[0]
-				
-				// Parse date and time from second part "2025-10-22 10"
-				dateTimePart := parts[1]
-				if strings.Contains(dateTimePart, " ") {
-					dateTimeFields := strings.Fields(dateTimePart)
-					if len(dateTimeFields) >= 2 {
-						// Date part: "2025-10-22"
-						dateParts := strings.Split(dateTimeFields[0], "-")
-						if len(dateParts) == 3 {
-							date = dateParts[2] + "." + dateParts[1] + "." + dateParts[0] // Convert to DD.MM.YYYY
-						}
-						// Time part: combine hour from dateTimePart and minutes from parts[2]
-						hour := dateTimeFields[1]
-						minute := parts[2]
-						time = hour + ":" + minute
-					}
-				}
-				
-				// Find NVT part (starts with "NVT")
-				nvtIndex := -1
-				for i, part := range parts {
-					if strings.HasPrefix(part, "NVT") {
-						nvtIndex = i
-						nvt = part
-						break
-					}
-				}
-				
-				// Address is everything between time part and NVT
-				if nvtIndex > 3 {
-					addressParts := parts[3:nvtIndex]
-					address = strings.TrimSpace(strings.Join(addressParts, " "))
-				}
-			}
-			log.Printf("Bulk upload - %s [Fremco] Parsed filename: Project: %s | Date: %s | Time: %s | Address: %s | NVT: %s", filename, project, date, time, address, nvt)
-		}
-		
-		// Fill in filename-based metadata
-		protocol.ExportMetadata.SourceFilename = filename
-		if date != "" {
-			protocol.ProtocolInfo.Date = date
-		}
-		if time != "" {
-			protocol.ProtocolInfo.StartTime = time
-		}
-		if project != "" {
-			protocol.ProtocolInfo.ProjectNumber = project
-		}
-		if address != "" && nvt != "" {
-			protocol.ProtocolInfo.SectionNVT = address + " / " + nvt
-		} else if address != "" {
-			protocol.ProtocolInfo.SectionNVT = address
-		}
-		
-		result["format"] = "fremco"
-		measurementCount := len(protocol.Measurements.DataPoints)
-		result["measurements"] = measurementCount
-		
-		log.Printf("Bulk upload - %s: Fremco protocol parsed successfully - %d measurements", filename, measurementCount)
-		
-		if autoSave && db != nil {
-			_, err := SaveFremcoProtocol(db, protocol)
-			if err != nil {
-				result["error"] = "Database save failed: " + err.Error()
-				log.Printf("Bulk upload error - %s: Fremco database save failed - %v", filename, err)
-				return result
-			}
-			result["saved"] = true
-			log.Printf("Bulk upload - %s: Fremco protocol saved to database successfully", filename)
-		}
-	} else {
-		// Last resort: if filename looks like Jetting but content detection failed, try Jetting anyway
-		filenameHints := analyzeFilenameHints(filename)
-		if filenameHints["probable_jetting_filename"] {
-			log.Printf("Bulk upload - %s: Content detection failed but filename suggests Jetting, attempting Jetting parse", filename)
-			
-			normalized := simulator.NormalizeJettingTxt(rawText)
-			protocol := simulator.ParseJettingProtocol(normalized)
-			if protocol != nil {
-				// Filename-based detection succeeded
-				protocol.ExportMetadata.SourceFilename = filename
-				
-				result["format"] = "jetting"
-				measurementCount := len(protocol.Measurements.DataPoints)
-				result["measurements"] = measurementCount
-				result["filename_fallback"] = true
-				
-				log.Printf("Bulk upload - %s: Filename-based Jetting fallback successful - %d measurements", filename, measurementCount)
-				
-				if autoSave && db != nil {
-					_, err := SaveJettingProtocol(db, protocol)
-					if err != nil {
-						result["error"] = "Database save failed: " + err.Error()
-						log.Printf("Bulk upload error - %s: Jetting database save failed - %v", filename, err)
-						return result
-					}
-					result["saved"] = true
-					log.Printf("Bulk upload - %s: Filename-fallback Jetting protocol saved to database successfully", filename)
-				}
-			} else {
-				result["error"] = "Unknown PDF format - content does not match Fremco or Jetting patterns, filename fallback also failed"
-				log.Printf("Bulk upload error - %s: Format detection failed completely. First 500 chars: %q", filename, truncateString(rawText, 500))
-				return result
-			}
-		} else {
-			result["error"] = "Unknown PDF format - content does not match Fremco or Jetting patterns"
-			log.Printf("Bulk upload error - %s: Format detection failed. First 500 chars: %q", filename, truncateString(rawText, 500))
-			return result
+		if err := saveParseDiagnostics(protocolID, parsed); err != nil {
+			log.Printf("Bulk upload error - %s: %v", filename, err)
 		}
+		fitSavedProtocol(protocolID)
+		result["saved"] = true
+		log.Printf("Bulk upload - %s: %s protocol saved to database successfully", filename, format)
 	}
-	
+
 	result["success"] = true
 	return result
 }
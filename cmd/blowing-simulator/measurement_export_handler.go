@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// ExportMeasurementsHandler exports the measurements of a protocol (?id=X) in the
+// units shared by all formats: CSV with one column per recorded quantity, or
+// with format=json the measurement series including its source columns.
+func ExportMeasurementsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid protocol ID", http.StatusBadRequest)
+		return
+	}
+	series, err := loadMeasurementSeries(id)
+	if err != nil {
+		http.Error(w, "Export failed: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=protocol_%d_measurements.json", id))
+		json.NewEncoder(w).Encode(series)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=protocol_%d_measurements.csv", id))
+	if err := series.WriteCSV(w); err != nil {
+		log.Printf("ExportMeasurementsHandler: Protocol %d: %v", id, err)
+	}
+}
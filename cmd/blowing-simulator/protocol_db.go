@@ -1,6 +1,7 @@
 package main
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
 	"strings"
@@ -11,233 +12,292 @@ import (
 	"blowing-simulator/internal/simulator"
 )
 
-// SaveFremcoProtocol saves a complete Fremco protocol to the database
-func SaveFremcoProtocol(db *sqlx.DB, protocol *simulator.FremcoProtocol) (int, error) {
-	tx, err := db.Begin()
-	if err != nil {
-		return 0, fmt.Errorf("failed to begin transaction: %v", err)
-	}
-	defer tx.Rollback()
+// protocolColumn is one column value of a stored protocol row
+type protocolColumn struct {
+	name  string
+	value interface{}
+}
 
-	// Insert main protocol record
-	var protocolID int
-	err = tx.QueryRow(`
-		INSERT INTO protocols (
-			protocol_type, system_name, document_type, protocol_date, start_time,
-			project_number, section_nvt, company, service_provider, operator,
-			remarks, source_filename, parser_version, address
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-		RETURNING id`,
-		"fremco",
-		protocol.ProtocolInfo.System,
-		protocol.ProtocolInfo.DocumentType,
-		parseDate(protocol.ProtocolInfo.Date),
-		parseTime(protocol.ProtocolInfo.StartTime),
-		protocol.ProtocolInfo.ProjectNumber,
-		protocol.ProtocolInfo.SectionNVT,
-		protocol.ProtocolInfo.Company,
-		protocol.ProtocolInfo.ServiceProvider,
-		protocol.ProtocolInfo.Operator,
-		protocol.ProtocolInfo.Remarks,
-		protocol.ExportMetadata.SourceFilename,
-		protocol.ExportMetadata.ParserVersion,
-		extractAddressFromSectionNVT(protocol.ProtocolInfo.SectionNVT),
-	).Scan(&protocolID)
+// protocolRecord holds the rows a protocol of any format is stored as in the
+// protocols, protocol_equipment and protocol_summary tables
+type protocolRecord struct {
+	protocol  []protocolColumn
+	equipment []protocolColumn
+	summary   []protocolColumn
+}
 
-	if err != nil {
-		return 0, fmt.Errorf("failed to insert protocol: %v", err)
+// protocolHeader is the part of the protocols row shared by all formats.
+// Jetting leaves project number and operator empty, so they are nullable.
+type protocolHeader struct {
+	System, DocumentType, Date, StartTime  string
+	ProjectNumber                          interface{}
+	SectionNVT, Company, ServiceProvider   string
+	Operator                               interface{}
+	Remarks, SourceFilename, ParserVersion string
+}
+
+// columns returns the protocols row of a header for a protocol type
+func (h protocolHeader) columns(protocolType string) []protocolColumn {
+	return []protocolColumn{
+		{"protocol_type", protocolType},
+		{"system_name", h.System},
+		{"document_type", h.DocumentType},
+		{"protocol_date", parseDate(h.Date)},
+		{"start_time", parseTime(h.StartTime)},
+		{"project_number", h.ProjectNumber},
+		{"section_nvt", h.SectionNVT},
+		{"company", h.Company},
+		{"service_provider", h.ServiceProvider},
+		{"operator", h.Operator},
+		{"remarks", h.Remarks},
+		{"source_filename", h.SourceFilename},
+		{"parser_version", h.ParserVersion},
+		{"address", extractAddressFromSectionNVT(h.SectionNVT)},
 	}
+}
 
-	// Insert equipment specifications
-	_, err = tx.Exec(`
-		INSERT INTO protocol_equipment (
-			protocol_id, device_model, controller_sn, lubricator, crash_test_performed,
-			crash_test_speed, crash_test_moment, pipe_manufacturer, pipe_bundle,
-			pipe_type, pipe_color_coding, pipe_inner_wall, pipe_temperature,
-			cable_manufacturer, cable_designation, cable_fiber_count, cable_diameter,
-			cable_temperature, cable_lubricant, cable_blowing_cap,
-			compressor_model, compressor_oil_separator, compressor_after_cooler
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)`,
-		protocolID,
-		protocol.Equipment.BlowingDevice.Model,
-		protocol.Equipment.BlowingDevice.ControllerSN,
-		protocol.Equipment.BlowingDevice.Lubricator,
-		protocol.Equipment.BlowingDevice.CrashTestPerformed,
-		protocol.Equipment.BlowingDevice.CrashTestSpeed,
-		protocol.Equipment.BlowingDevice.CrashTestMoment,
-		protocol.Equipment.Pipe.Manufacturer,
-		protocol.Equipment.Pipe.PipeBundle,
-		protocol.Equipment.Pipe.PipeType,
-		pq.Array(protocol.Equipment.Pipe.ColorCoding),
-		protocol.Equipment.Pipe.InnerWall,
-		protocol.Equipment.Pipe.Temperature,
-		protocol.Equipment.Cable.Manufacturer,
-		protocol.Equipment.Cable.Designation,
-		protocol.Equipment.Cable.FiberCount,
-		protocol.Equipment.Cable.Diameter,
-		protocol.Equipment.Cable.Temperature,
-		protocol.Equipment.Cable.Lubricant,
-		protocol.Equipment.Cable.BlowingCap,
-		protocol.Equipment.Compressor.Model,
-		protocol.Equipment.Compressor.OilSeparator,
-		protocol.Equipment.Compressor.AfterCooler,
-	)
+// newProtocolRecord maps a parsed protocol to its table rows
+func newProtocolRecord(protocol simulator.Protocol) (protocolRecord, error) {
+	switch p := protocol.(type) {
+	case *simulator.FremcoProtocol:
+		info, eq, m := p.ProtocolInfo, p.Equipment, p.Measurements
+		return protocolRecord{
+			protocol: protocolHeader{
+				info.System, info.DocumentType, info.Date, info.StartTime, info.ProjectNumber,
+				info.SectionNVT, info.Company, info.ServiceProvider, info.Operator,
+				info.Remarks, p.ExportMetadata.SourceFilename, p.ExportMetadata.ParserVersion,
+			}.columns("fremco"),
+			equipment: []protocolColumn{
+				{"device_model", eq.BlowingDevice.Model},
+				{"controller_sn", eq.BlowingDevice.ControllerSN},
+				{"lubricator", eq.BlowingDevice.Lubricator},
+				{"crash_test_performed", eq.BlowingDevice.CrashTestPerformed},
+				{"crash_test_speed", eq.BlowingDevice.CrashTestSpeed},
+				{"crash_test_moment", eq.BlowingDevice.CrashTestMoment},
+				{"pipe_manufacturer", eq.Pipe.Manufacturer},
+				{"pipe_bundle", eq.Pipe.PipeBundle},
+				{"pipe_type", eq.Pipe.PipeType},
+				{"pipe_color_coding", pq.Array(eq.Pipe.ColorCoding)},
+				{"pipe_inner_wall", eq.Pipe.InnerWall},
+				{"pipe_temperature", eq.Pipe.Temperature},
+				{"cable_manufacturer", eq.Cable.Manufacturer},
+				{"cable_designation", eq.Cable.Designation},
+				{"cable_fiber_count", eq.Cable.FiberCount},
+				{"cable_diameter", eq.Cable.Diameter},
+				{"cable_temperature", eq.Cable.Temperature},
+				{"cable_lubricant", eq.Cable.Lubricant},
+				{"cable_blowing_cap", eq.Cable.BlowingCap},
+				{"compressor_model", eq.Compressor.Model},
+				{"compressor_oil_separator", eq.Compressor.OilSeparator},
+				{"compressor_after_cooler", eq.Compressor.AfterCooler},
+			},
+			summary: []protocolColumn{
+				{"meter_start", m.MeterReadings.Start},
+				{"meter_end", m.MeterReadings.End},
+				{"total_distance", m.Summary.Distance},
+				{"blowing_time", parseDuration(m.Summary.BlowingTime)},
+				{"weather_temperature", m.Summary.Weather.Temperature},
+				{"weather_humidity", m.Summary.Weather.Humidity},
+				{"gps_latitude", m.Summary.GPSLocation.Latitude},
+				{"gps_longitude", m.Summary.GPSLocation.Longitude},
+			},
+		}, nil
+
+	case *simulator.JettingProtocol:
+		info, eq, m := p.ProtocolInfo, p.Equipment, p.Measurements
+		return protocolRecord{
+			protocol: protocolHeader{
+				info.System, info.DocumentType, info.Date, info.StartTime, info.ProjectNumber,
+				info.SectionNVT, info.Company, info.ServiceProvider, info.Operator,
+				info.Remarks, p.ExportMetadata.SourceFilename, p.ExportMetadata.ParserVersion,
+			}.columns("jetting"),
+			equipment: []protocolColumn{
+				{"device_model", eq.BlowingDevice.Model},
+				{"controller_sn", eq.BlowingDevice.ControllerSN},
+				{"lubricator", eq.BlowingDevice.Lubricator},
+				{"crash_test_performed", eq.BlowingDevice.CrashTestPerformed},
+				{"crash_test_speed", eq.BlowingDevice.CrashTestSpeed},
+				{"crash_test_moment", eq.BlowingDevice.CrashTestMoment},
+				{"crash_test_force", eq.BlowingDevice.CrashTestForce},
+				{"pipe_manufacturer", eq.Pipe.Manufacturer},
+				{"pipe_bundle", eq.Pipe.PipeBundle},
+				{"pipe_type", eq.Pipe.PipeType},
+				{"pipe_color_coding", pq.Array(eq.Pipe.ColorCoding)},
+				{"pipe_inner_wall", eq.Pipe.InnerWall},
+				{"pipe_temperature", eq.Pipe.Temperature},
+				{"pipe_inner_diameter", eq.Pipe.InnerDiameter},
+				{"pipe_outer_diameter", eq.Pipe.OuterDiameter},
+				{"cable_manufacturer", eq.Cable.Manufacturer},
+				{"cable_designation", eq.Cable.Designation},
+				{"cable_fiber_count", eq.Cable.FiberCount},
+				{"cable_diameter", eq.Cable.Diameter},
+				{"cable_temperature", eq.Cable.Temperature},
+				{"cable_lubricant", eq.Cable.Lubricant},
+				{"cable_blowing_cap", eq.Cable.BlowingCap},
+				{"cable_drum_number", eq.Cable.DrumNumber},
+				{"compressor_model", eq.Compressor.Model},
+				{"compressor_oil_separator", eq.Compressor.OilSeparator},
+				{"compressor_after_cooler", eq.Compressor.AfterCooler},
+			},
+			summary: []protocolColumn{
+				{"meter_start", m.MeterReadings.Start},
+				{"meter_end", m.MeterReadings.End},
+				{"total_distance", m.Summary.Distance},
+				{"blowing_time", parseDurationPtr(m.Summary.BlowingTime)},
+				{"weather_temperature", m.Summary.Weather.Temperature},
+				{"weather_humidity", m.Summary.Weather.Humidity},
+				{"gps_latitude", m.Summary.GPSLocation.Latitude},
+				{"gps_longitude", m.Summary.GPSLocation.Longitude},
+				{"gps_altitude", m.Summary.GPSLocation.Altitude},
+			},
+		}, nil
+
+	case *simulator.PlumettazProtocol:
+		// The machine serial number is stored as controller_sn, the push force as force_n
+		info, eq, m := p.ProtocolInfo, p.Equipment, p.Measurements
+		return protocolRecord{
+			protocol: protocolHeader{
+				info.System, info.DocumentType, info.Date, info.StartTime, info.ProjectNumber,
+				info.SectionNVT, info.Company, info.ServiceProvider, info.Operator,
+				info.Remarks, p.ExportMetadata.SourceFilename, p.ExportMetadata.ParserVersion,
+			}.columns("plumettaz"),
+			equipment: []protocolColumn{
+				{"device_model", eq.Machine.Model},
+				{"controller_sn", eq.Machine.SerialNumber},
+				{"pipe_manufacturer", eq.Duct.Manufacturer},
+				{"pipe_type", eq.Duct.Type},
+				{"pipe_inner_diameter", eq.Duct.InnerDiameter},
+				{"pipe_outer_diameter", eq.Duct.OuterDiameter},
+				{"cable_manufacturer", eq.Cable.Manufacturer},
+				{"cable_designation", eq.Cable.Designation},
+				{"cable_fiber_count", eq.Cable.FiberCount},
+				{"cable_diameter", eq.Cable.Diameter},
+				{"cable_lubricant", eq.Cable.Lubricant},
+				{"compressor_model", eq.Compressor.Model},
+			},
+			summary: []protocolColumn{
+				{"total_distance", m.Summary.Distance},
+				{"blowing_time", parseDurationPtr(m.Summary.BlowingTime)},
+				{"weather_temperature", m.Summary.AmbientTemperature},
+			},
+		}, nil
+	}
+	return protocolRecord{}, fmt.Errorf("unsupported format %s", protocol.Format())
+}
 
-	if err != nil {
-		return 0, fmt.Errorf("failed to insert equipment: %v", err)
-	}
-
-	// Insert protocol summary
-	_, err = tx.Exec(`
-		INSERT INTO protocol_summary (
-			protocol_id, meter_start, meter_end, total_distance, blowing_time,
-			weather_temperature, weather_humidity, gps_latitude, gps_longitude
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
-		protocolID,
-		protocol.Measurements.MeterReadings.Start,
-		protocol.Measurements.MeterReadings.End,
-		protocol.Measurements.Summary.Distance,
-		parseDuration(protocol.Measurements.Summary.BlowingTime),
-		protocol.Measurements.Summary.Weather.Temperature,
-		protocol.Measurements.Summary.Weather.Humidity,
-		protocol.Measurements.Summary.GPSLocation.Latitude,
-		protocol.Measurements.Summary.GPSLocation.Longitude,
-	)
+// insertStatement builds the INSERT statement and arguments of a protocol table row
+func insertStatement(table string, columns []protocolColumn) (string, []interface{}) {
+	names := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		names[i], placeholders[i], values[i] = c.name, fmt.Sprintf("$%d", i+1), c.value
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(names, ", "), strings.Join(placeholders, ", ")), values
+}
 
+// saveProtocolTx inserts a protocol with its equipment, summary and the given
+// measurements within the caller's transaction and returns the new protocol ID
+func saveProtocolTx(tx *sql.Tx, protocol simulator.Protocol, series simulator.MeasurementSeries) (int, error) {
+	record, err := newProtocolRecord(protocol)
 	if err != nil {
-		return 0, fmt.Errorf("failed to insert summary: %v", err)
+		return 0, fmt.Errorf("failed to save protocol: %v", err)
 	}
 
-	// Insert measurement data points
-	log.Printf("SaveFremcoProtocol: Starting to insert %d measurements for protocol ID %d", len(protocol.Measurements.DataPoints), protocolID)
-	for i, dataPoint := range protocol.Measurements.DataPoints {
-		parsedTimestamp := parseTimestamp(dataPoint.Timestamp)
-		log.Printf("SaveFremcoProtocol: Inserting measurement %d - Length: %f, Speed: %f, Timestamp: %s -> Parsed: %v", 
-			i, dataPoint.LengthM, dataPoint.SpeedMMin, dataPoint.Timestamp, parsedTimestamp)
-		
-		_, err = tx.Exec(`
-			INSERT INTO protocol_measurements (
-				protocol_id, length_m, speed_m_min, pressure_bar, torque_percent,
-				timestamp_value, sequence_number
-			) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-			protocolID,
-			dataPoint.LengthM,
-			dataPoint.SpeedMMin,
-			dataPoint.PressureBar,
-			dataPoint.TorquePercent,
-			parsedTimestamp,
-			i+1,
-		)
+	query, values := insertStatement("protocols", record.protocol)
+	var protocolID int
+	if err := tx.QueryRow(query+" RETURNING id", values...).Scan(&protocolID); err != nil {
+		return 0, fmt.Errorf("failed to insert protocol: %v", err)
+	}
 
-		if err != nil {
-			log.Printf("SaveFremcoProtocol: Failed to insert measurement %d: %v", i, err)
-			return 0, fmt.Errorf("failed to insert measurement %d: %v", i, err)
-		}
-		
-		if i < 3 {  // Log first few measurements
-			log.Printf("SaveFremcoProtocol: Successfully inserted measurement %d", i)
+	rows := []struct {
+		table   string
+		columns []protocolColumn
+	}{
+		{"protocol_equipment", record.equipment},
+		{"protocol_summary", record.summary},
+	}
+	for _, row := range rows {
+		columns := append([]protocolColumn{{"protocol_id", protocolID}}, row.columns...)
+		query, values := insertStatement(row.table, columns)
+		if _, err := tx.Exec(query, values...); err != nil {
+			return 0, fmt.Errorf("failed to insert %s: %v", strings.TrimPrefix(row.table, "protocol_"), err)
 		}
 	}
-	log.Printf("SaveFremcoProtocol: Completed inserting all %d measurements", len(protocol.Measurements.DataPoints))
-
-	log.Printf("SaveFremcoProtocol: Attempting to commit transaction for protocol ID %d", protocolID)
-	if err = tx.Commit(); err != nil {
-		log.Printf("SaveFremcoProtocol: Transaction commit failed: %v", err)
-		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	if err := saveMeasurementSeries(tx, protocolID, series); err != nil {
+		return 0, err
 	}
-
-	log.Printf("SaveFremcoProtocol: Transaction committed successfully for protocol ID %d", protocolID)
 	return protocolID, nil
 }
 
-// SaveJettingProtocol saves a complete Jetting protocol to the database
-func SaveJettingProtocol(db *sqlx.DB, protocol *simulator.JettingProtocol) (int, error) {
+// SaveProtocol saves a parsed protocol of any supported format with its
+// equipment, summary and measurements, and links it to the catalog
+func SaveProtocol(db *sqlx.DB, protocol simulator.Protocol) (int, error) {
 	tx, err := db.Begin()
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %v", err)
 	}
 	defer tx.Rollback()
 
-	// Insert main protocol record
-	var protocolID int
-	err = tx.QueryRow(`
-		INSERT INTO protocols (
-			protocol_type, system_name, document_type, protocol_date, start_time,
-			project_number, section_nvt, company, service_provider, operator,
-			remarks, source_filename, parser_version, address
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-		RETURNING id`,
-		"jetting",
-		protocol.ProtocolInfo.System,
-		protocol.ProtocolInfo.DocumentType,
-		parseDate(protocol.ProtocolInfo.Date),
-		parseTime(protocol.ProtocolInfo.StartTime),
-		protocol.ProtocolInfo.ProjectNumber,
-		protocol.ProtocolInfo.SectionNVT,
-		protocol.ProtocolInfo.Company,
-		protocol.ProtocolInfo.ServiceProvider,
-		protocol.ProtocolInfo.Operator,
-		protocol.ProtocolInfo.Remarks,
-		protocol.ExportMetadata.SourceFilename,
-		protocol.ExportMetadata.ParserVersion,
-		extractAddressFromSectionNVT(protocol.ProtocolInfo.SectionNVT),
-	).Scan(&protocolID)
-
+	protocolID, err := saveProtocolTx(tx, protocol, protocol.MeasurementSeries())
 	if err != nil {
-		return 0, fmt.Errorf("failed to insert protocol: %v", err)
+		log.Printf("SaveProtocol: %v", err)
+		return 0, err
 	}
-
-	// Insert minimal equipment specifications for Jetting
-	_, err = tx.Exec(`
-		INSERT INTO protocol_equipment (protocol_id, pipe_color_coding) VALUES ($1, $2)`,
-		protocolID,
-		pq.Array(protocol.Equipment.Pipe.ColorCoding),
-	)
-
-	if err != nil {
-		return 0, fmt.Errorf("failed to insert equipment: %v", err)
+	if err = tx.Commit(); err != nil {
+		log.Printf("SaveProtocol: Transaction commit failed: %v", err)
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
 	}
+	log.Printf("SaveProtocol: Saved %s protocol ID %d with %d measurements", protocol.Format(), protocolID, protocol.DataPointCount())
 
-	// Insert minimal summary for Jetting
-	_, err = tx.Exec(`
-		INSERT INTO protocol_summary (protocol_id) VALUES ($1)`,
-		protocolID,
-	)
-
-	if err != nil {
-		return 0, fmt.Errorf("failed to insert summary: %v", err)
+	if err := linkProtocolToCatalog(db, protocolID); err != nil {
+		log.Printf("SaveProtocol: Catalog matching failed for protocol ID %d: %v", protocolID, err)
 	}
+	return protocolID, nil
+}
 
-	// Insert measurement data points
-	for i, dataPoint := range protocol.Measurements.DataPoints {
-		_, err = tx.Exec(`
+// saveMeasurementSeries inserts the measurements of a protocol and the table columns they were read from
+func saveMeasurementSeries(tx *sql.Tx, protocolID int, series simulator.MeasurementSeries) error {
+	for i, m := range series.Measurements {
+		var elapsed *string
+		if m.Elapsed != nil {
+			interval := simulator.FormatElapsed(*m.Elapsed)
+			elapsed = &interval
+		}
+		_, err := tx.Exec(`
 			INSERT INTO protocol_measurements (
-				protocol_id, length_m, temperature_c, force_n, pressure_bar,
-				speed_m_min, time_duration, sequence_number
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				protocol_id, length_m, speed_m_min, pressure_bar, torque_percent,
+				force_n, temperature_c, time_duration, timestamp_value, sequence_number
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
 			protocolID,
-			dataPoint.LengthM,
-			dataPoint.TemperatureC,
-			dataPoint.ForceN,
-			dataPoint.PressureBar,
-			dataPoint.SpeedMMin,
-			parseDuration(dataPoint.TimeDuration),
-			i+1,
+			m.LengthM,
+			m.SpeedMMin,
+			m.PressureBar,
+			m.TorquePercent,
+			m.ForceN,
+			m.TemperatureC,
+			elapsed,
+			m.Timestamp,
+			m.Sequence,
 		)
-
 		if err != nil {
-			return 0, fmt.Errorf("failed to insert measurement %d: %v", i, err)
+			return fmt.Errorf("failed to insert measurement %d: %v", i, err)
 		}
 	}
-
-	if err = tx.Commit(); err != nil {
-		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	for i, c := range series.SourceColumns {
+		_, err := tx.Exec(`
+			INSERT INTO protocol_source_columns (protocol_id, position, name, unit, field)
+			VALUES ($1, $2, $3, $4, $5)`,
+			protocolID, i+1, c.Name, c.Unit, c.Field)
+		if err != nil {
+			return fmt.Errorf("failed to insert source column %q: %v", c.Name, err)
+		}
 	}
-
-	return protocolID, nil
+	return nil
 }
 
-// LoadProtocol loads a protocol by ID (works for both Fremco and Jetting)
+// LoadProtocol loads a protocol by ID (Fremco, Jetting or Plumettaz)
 func LoadProtocol(db *sqlx.DB, protocolID int) (interface{}, error) {
 	var protocolType string
 	err := db.QueryRow("SELECT protocol_type FROM protocols WHERE id = $1", protocolID).Scan(&protocolType)
@@ -249,6 +309,8 @@ func LoadProtocol(db *sqlx.DB, protocolID int) (interface{}, error) {
 		return LoadFremcoProtocol(db, protocolID)
 	} else if protocolType == "jetting" {
 		return LoadJettingProtocol(db, protocolID)
+	} else if protocolType == "plumettaz" {
+		return LoadPlumettazProtocol(db, protocolID)
 	}
 
 	return nil, fmt.Errorf("unknown protocol type: %s", protocolType)
@@ -298,6 +360,132 @@ func LoadJettingProtocol(db *sqlx.DB, protocolID int) (*simulator.JettingProtoco
 	return protocol, nil
 }
 
+// LoadPlumettazProtocol loads a Plumettaz protocol with its equipment, summary and data points
+func LoadPlumettazProtocol(db *sqlx.DB, protocolID int) (*simulator.PlumettazProtocol, error) {
+	var row struct {
+		System          string          `db:"system_name"`
+		DocumentType    string          `db:"document_type"`
+		Date            string          `db:"protocol_date"`
+		StartTime       string          `db:"start_time"`
+		ProjectNumber   string          `db:"project_number"`
+		SectionNVT      string          `db:"section_nvt"`
+		Company         string          `db:"company"`
+		ServiceProvider string          `db:"service_provider"`
+		Operator        string          `db:"operator"`
+		Remarks         string          `db:"remarks"`
+		SourceFilename  string          `db:"source_filename"`
+		ParserVersion   string          `db:"parser_version"`
+		ParsedAt        time.Time       `db:"parsed_at"`
+		DeviceModel     string          `db:"device_model"`
+		SerialNumber    string          `db:"controller_sn"`
+		PipeMaker       string          `db:"pipe_manufacturer"`
+		PipeType        string          `db:"pipe_type"`
+		InnerDiameter   sql.NullFloat64 `db:"pipe_inner_diameter"`
+		OuterDiameter   sql.NullFloat64 `db:"pipe_outer_diameter"`
+		CableMaker      string          `db:"cable_manufacturer"`
+		Designation     string          `db:"cable_designation"`
+		FiberCount      sql.NullInt64   `db:"cable_fiber_count"`
+		CableDiameter   sql.NullFloat64 `db:"cable_diameter"`
+		Lubricant       string          `db:"cable_lubricant"`
+		Compressor      string          `db:"compressor_model"`
+		Distance        sql.NullInt64   `db:"total_distance"`
+		BlowingTime     sql.NullString  `db:"blowing_time"`
+		Temperature     sql.NullFloat64 `db:"weather_temperature"`
+	}
+	err := db.Get(&row, `
+		SELECT COALESCE(p.system_name, '') AS system_name, COALESCE(p.document_type, '') AS document_type,
+		       COALESCE(TO_CHAR(p.protocol_date, 'DD.MM.YYYY'), '') AS protocol_date,
+		       COALESCE(TO_CHAR(p.start_time, 'HH24:MI'), '') AS start_time,
+		       COALESCE(p.project_number, '') AS project_number, COALESCE(p.section_nvt, '') AS section_nvt,
+		       COALESCE(p.company, '') AS company, COALESCE(p.service_provider, '') AS service_provider,
+		       COALESCE(p.operator, '') AS operator, COALESCE(p.remarks, '') AS remarks,
+		       COALESCE(p.source_filename, '') AS source_filename, COALESCE(p.parser_version, '') AS parser_version,
+		       p.parsed_at,
+		       COALESCE(pe.device_model, '') AS device_model, COALESCE(pe.controller_sn, '') AS controller_sn,
+		       COALESCE(pe.pipe_manufacturer, '') AS pipe_manufacturer, COALESCE(pe.pipe_type, '') AS pipe_type,
+		       pe.pipe_inner_diameter, pe.pipe_outer_diameter,
+		       COALESCE(pe.cable_manufacturer, '') AS cable_manufacturer,
+		       COALESCE(pe.cable_designation, '') AS cable_designation,
+		       pe.cable_fiber_count, pe.cable_diameter,
+		       COALESCE(pe.cable_lubricant, '') AS cable_lubricant,
+		       COALESCE(pe.compressor_model, '') AS compressor_model,
+		       ps.total_distance, ps.blowing_time::text AS blowing_time, ps.weather_temperature
+		FROM protocols p
+		LEFT JOIN protocol_equipment pe ON pe.protocol_id = p.id
+		LEFT JOIN protocol_summary ps ON ps.protocol_id = p.id
+		WHERE p.id = $1 AND p.protocol_type = 'plumettaz'`, protocolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load protocol info: %v", err)
+	}
+
+	float := func(v sql.NullFloat64) *float64 {
+		if !v.Valid {
+			return nil
+		}
+		return &v.Float64
+	}
+	protocol := &simulator.PlumettazProtocol{
+		ProtocolInfo: simulator.PlumettazProtocolInfo{
+			System: row.System, DocumentType: row.DocumentType, Date: row.Date, StartTime: row.StartTime,
+			ProjectNumber: row.ProjectNumber, SectionNVT: row.SectionNVT, Company: row.Company,
+			ServiceProvider: row.ServiceProvider, Operator: row.Operator, Remarks: row.Remarks,
+		},
+		Equipment: simulator.PlumettazEquipment{
+			Machine: simulator.PlumettazMachine{Model: row.DeviceModel, SerialNumber: row.SerialNumber},
+			Duct: simulator.PlumettazDuct{
+				Manufacturer: row.PipeMaker, Type: row.PipeType,
+				InnerDiameter: float(row.InnerDiameter), OuterDiameter: float(row.OuterDiameter),
+			},
+			Cable: simulator.PlumettazCable{
+				Manufacturer: row.CableMaker, Designation: row.Designation,
+				Diameter: float(row.CableDiameter), Lubricant: row.Lubricant,
+			},
+			Compressor: simulator.PlumettazCompressor{Model: row.Compressor},
+		},
+		Measurements: simulator.PlumettazMeasurements{
+			Summary:    simulator.PlumettazSummary{AmbientTemperature: float(row.Temperature)},
+			DataPoints: []simulator.PlumettazDataPoint{},
+		},
+		ExportMetadata: simulator.PlumettazExportMetadata{
+			ParsedAt: row.ParsedAt, ParserVersion: row.ParserVersion, SourceFilename: row.SourceFilename,
+		},
+	}
+	if row.FiberCount.Valid {
+		fibers := int(row.FiberCount.Int64)
+		protocol.Equipment.Cable.FiberCount = &fibers
+	}
+	if row.Distance.Valid {
+		distance := int(row.Distance.Int64)
+		protocol.Measurements.Summary.Distance = &distance
+	}
+	if row.BlowingTime.Valid {
+		if d, err := simulator.ParseElapsed(row.BlowingTime.String); err == nil {
+			blowingTime := simulator.FormatElapsed(d)
+			protocol.Measurements.Summary.BlowingTime = &blowingTime
+		}
+	}
+
+	series, err := loadMeasurementSeries(protocolID)
+	if err != nil {
+		return nil, err
+	}
+	protocol.ExportMetadata.Columns = series.SourceColumns
+	for _, m := range series.Measurements {
+		dp := simulator.PlumettazDataPoint{
+			LengthM:      m.LengthM,
+			SpeedMMin:    m.SpeedMMin,
+			PressureBar:  m.PressureBar,
+			ForceN:       m.ForceN,
+			TemperatureC: m.TemperatureC,
+		}
+		if m.Elapsed != nil {
+			dp.TimeDuration = simulator.FormatElapsed(*m.Elapsed)
+		}
+		protocol.Measurements.DataPoints = append(protocol.Measurements.DataPoints, dp)
+	}
+	return protocol, nil
+}
+
 // Helper functions for parsing dates, times, and durations
 func parseDate(dateStr string) *time.Time {
 	if dateStr == "" {
@@ -329,47 +517,27 @@ func parseTime(timeStr string) *time.Time {
 	return nil
 }
 
-func parseDuration(durationStr string) *time.Duration {
+// parseDuration normalizes a duration "hh:mm:ss" for an INTERVAL column (nil = NULL)
+func parseDuration(durationStr string) *string {
 	if durationStr == "" {
 		return nil
 	}
 	
-	// Parse duration in format "hh:mm:ss"
-	if d, err := time.ParseDuration(durationStr); err == nil {
-		return &d
+	d, err := simulator.ParseElapsed(durationStr)
+	if err != nil {
+		log.Printf("parseDuration: Invalid duration '%s': %v", durationStr, err)
+		return nil
 	}
-	return nil
+	interval := simulator.FormatElapsed(d)
+	return &interval
 }
 
-func parseTimestamp(timestampStr string) *time.Time {
-	if timestampStr == "" {
-		log.Printf("parseTimestamp: Empty timestamp string")
+// parseDurationPtr is parseDuration for an optional duration
+func parseDurationPtr(durationStr *string) *string {
+	if durationStr == nil {
 		return nil
 	}
-	
-	log.Printf("parseTimestamp: Parsing timestamp: '%s'", timestampStr)
-	
-	// Try multiple timestamp formats
-	formats := []string{
-		"2006-01-02 15:04:05",
-		"2006-01-02 15:04",      // Added this format for timestamps without seconds
-		"2006-01-02T15:04:05",
-		"2006-01-02T15:04",      // Added this format too
-		"02.01.2006 15:04:05",
-		"02.01.2006 15:04",      // And this one
-	}
-	
-	for i, format := range formats {
-		if t, err := time.Parse(format, timestampStr); err == nil {
-			log.Printf("parseTimestamp: Successfully parsed '%s' using format %d (%s) -> %v", timestampStr, i, format, t)
-			return &t
-		} else {
-			log.Printf("parseTimestamp: Failed format %d (%s): %v", i, format, err)
-		}
-	}
-	
-	log.Printf("parseTimestamp: All formats failed for '%s'", timestampStr)
-	return nil
+	return parseDuration(*durationStr)
 }
 
 // Helper to extract address from SectionNVT string
@@ -380,4 +548,83 @@ func extractAddressFromSectionNVT(sectionNVT string) string {
         return strings.TrimSpace(parts[0])
     }
     return sectionNVT
-}
\ No newline at end of file
+}
+
+// loadProtocolMeasurements loads all measurements of a protocol in recording order
+func loadProtocolMeasurements(protocolID int) ([]simulator.Measurement, error) {
+	rows, err := loadProtocolMeasurementsPage(protocolID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	measurements := make([]simulator.Measurement, len(rows))
+	for i, row := range rows {
+		measurements[i] = row.Measurement(i + 1)
+	}
+	return measurements, nil
+}
+
+// loadMeasurementSeries loads the measurements of a protocol with the table columns they were read from
+func loadMeasurementSeries(protocolID int) (simulator.MeasurementSeries, error) {
+	var series simulator.MeasurementSeries
+	if err := db.Get(&series.Format, "SELECT protocol_type FROM protocols WHERE id = $1", protocolID); err != nil {
+		return series, fmt.Errorf("failed to get protocol type: %v", err)
+	}
+	err := db.Select(&series.SourceColumns, `
+		SELECT name, unit, field FROM protocol_source_columns
+		WHERE protocol_id = $1 ORDER BY position`, protocolID)
+	if err != nil {
+		return series, fmt.Errorf("failed to load source columns: %v", err)
+	}
+	series.Measurements, err = loadProtocolMeasurements(protocolID)
+	return series, err
+}
+
+// Measurement converts a stored measurement; sequence is used for rows stored without sequence number
+func (m ProtocolMeasurement) Measurement(sequence int) simulator.Measurement {
+	value := func(v sql.NullFloat64) *float64 {
+		if !v.Valid {
+			return nil
+		}
+		return &v.Float64
+	}
+	measurement := simulator.Measurement{
+		Sequence:      sequence,
+		LengthM:       m.LengthM.Float64,
+		SpeedMMin:     value(m.SpeedMMin),
+		PressureBar:   value(m.PressureBar),
+		TorquePercent: value(m.TorquePercent),
+		ForceN:        value(m.ForceN),
+		TemperatureC:  value(m.TemperatureC),
+	}
+	if m.SequenceNumber.Valid {
+		measurement.Sequence = int(m.SequenceNumber.Int64)
+	}
+	if m.TimeDuration.Valid {
+		if d, err := simulator.ParseElapsed(m.TimeDuration.String); err == nil {
+			measurement.Elapsed = &d
+		}
+	}
+	if m.TimestampValue.Valid {
+		t := m.TimestampValue.Time
+		measurement.Timestamp = &t
+	}
+	return measurement
+}
+
+// loadProtocolMeasurementsPage loads one page of measurements in recording order (limit 0 = all)
+func loadProtocolMeasurementsPage(protocolID, limit, offset int) ([]ProtocolMeasurement, error) {
+	pageLimit := sql.NullInt64{Int64: int64(limit), Valid: limit > 0}
+	var measurements []ProtocolMeasurement
+	err := db.Select(&measurements, `
+		SELECT id, sequence_number, length_m, timestamp_value, speed_m_min,
+		       pressure_bar, torque_percent, temperature_c, force_n,
+		       time_duration, created_at::text
+		FROM protocol_measurements
+		WHERE protocol_id = $1
+		ORDER BY COALESCE(sequence_number, id) ASC
+		LIMIT $2 OFFSET $3`, protocolID, pageLimit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load measurements: %v", err)
+	}
+	return measurements, nil
+}
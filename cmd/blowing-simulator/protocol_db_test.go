@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+
+	"blowing-simulator/internal/simulator"
+)
+
+func TestInsertStatement(t *testing.T) {
+	query, values := insertStatement("protocol_summary", []protocolColumn{
+		{"protocol_id", 7}, {"total_distance", 150}, {"blowing_time", nil},
+	})
+	want := "INSERT INTO protocol_summary (protocol_id, total_distance, blowing_time) VALUES ($1, $2, $3)"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if len(values) != 3 || values[0] != 7 || values[1] != 150 || values[2] != nil {
+		t.Errorf("values = %v", values)
+	}
+}
+
+func TestNewProtocolRecord(t *testing.T) {
+	sn := "PJ-1"
+	tests := []struct {
+		protocol     simulator.Protocol
+		protocolType string
+		serial       interface{}
+	}{
+		{&simulator.FremcoProtocol{Equipment: simulator.FremcoEquipment{BlowingDevice: simulator.FremcoBlowingDevice{ControllerSN: "9911.0027"}}}, "fremco", "9911.0027"},
+		{&simulator.JettingProtocol{Equipment: simulator.JettingEquipment{BlowingDevice: simulator.JettingBlowingDevice{ControllerSN: &sn}}}, "jetting", &sn},
+		{&simulator.PlumettazProtocol{Equipment: simulator.PlumettazEquipment{Machine: simulator.PlumettazMachine{SerialNumber: "MJ400-17"}}}, "plumettaz", "MJ400-17"},
+	}
+	for _, tt := range tests {
+		record, err := newProtocolRecord(tt.protocol)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.protocolType, err)
+		}
+		if record.protocol[0].name != "protocol_type" || record.protocol[0].value != tt.protocolType {
+			t.Errorf("%s: first protocols column = %+v", tt.protocolType, record.protocol[0])
+		}
+		seen := map[string]bool{}
+		for _, columns := range [][]protocolColumn{record.protocol, record.equipment, record.summary} {
+			for _, c := range columns {
+				if seen[c.name] && c.name != "protocol_id" {
+					t.Errorf("%s: column %s set twice", tt.protocolType, c.name)
+				}
+				seen[c.name] = true
+			}
+		}
+		var serial interface{}
+		for _, c := range record.equipment {
+			if c.name == "controller_sn" {
+				serial = c.value
+			}
+		}
+		if serial != tt.serial {
+			t.Errorf("%s: controller_sn = %v, want %v", tt.protocolType, serial, tt.serial)
+		}
+		if len(record.summary) == 0 {
+			t.Errorf("%s: no summary columns", tt.protocolType)
+		}
+	}
+}
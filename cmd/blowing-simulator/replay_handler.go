@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blowing-simulator/internal/simulator"
+)
+
+// Replay playback limits
+const (
+	maxReplaySpeed       = 1000.0
+	defaultReplayMaxGapS = 30.0 // long stalls are shortened to this many seconds of playback
+)
+
+// ReplayStart is sent as the first "start" event of a replay stream
+type ReplayStart struct {
+	ProtocolID   int     `json:"protocol_id"`
+	ProtocolType string  `json:"protocol_type"`
+	SystemName   string  `json:"system_name"`
+	SectionNVT   string  `json:"section_nvt"`
+	Points       int     `json:"points"`
+	Timing       string  `json:"timing"` // timestamp, duration or estimated
+	DurationS    float64 `json:"duration_s"`
+	Speed        float64 `json:"speed"`
+	MaxGapS      float64 `json:"max_gap_s"`
+}
+
+// ReplayFrame is one measurement sent as a "measurement" event
+type ReplayFrame struct {
+	Index         int      `json:"index"`
+	Sequence      int      `json:"sequence"`
+	ElapsedS      float64  `json:"elapsed_s"`
+	Elapsed       string   `json:"elapsed"` // hh:mm:ss since the start of the protocol
+	Timestamp     string   `json:"timestamp,omitempty"`
+	TimeDuration  string   `json:"time_duration,omitempty"`
+	LengthM       *float64 `json:"length_m"`
+	SpeedMMin     *float64 `json:"speed_m_min"`
+	PressureBar   *float64 `json:"pressure_bar"`
+	TorquePercent *float64 `json:"torque_percent"`
+	TemperatureC  *float64 `json:"temperature_c"`
+	ForceN        *float64 `json:"force_n"`
+}
+
+// replayFrame converts a stored measurement to a replay frame
+func replayFrame(index int, offset time.Duration, m simulator.Measurement) ReplayFrame {
+	frame := ReplayFrame{
+		Index:         index,
+		Sequence:      m.Sequence,
+		ElapsedS:      offset.Seconds(),
+		Elapsed:       simulator.FormatElapsed(offset),
+		LengthM:       &m.LengthM,
+		SpeedMMin:     m.SpeedMMin,
+		PressureBar:   m.PressureBar,
+		TorquePercent: m.TorquePercent,
+		TemperatureC:  m.TemperatureC,
+		ForceN:        m.ForceN,
+	}
+	if m.Timestamp != nil {
+		frame.Timestamp = m.Timestamp.Format(simulator.MeasurementTimestampLayout)
+	}
+	if m.Elapsed != nil {
+		frame.TimeDuration = simulator.FormatElapsed(*m.Elapsed)
+	}
+	return frame
+}
+
+// writeReplayEvent writes one server-sent event and flushes it to the client
+func writeReplayEvent(w http.ResponseWriter, flusher http.Flusher, event string, id int, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %v", event, err)
+	}
+	if id >= 0 {
+		fmt.Fprintf(w, "id: %d\n", id)
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// ReplayProtocolHandler streams the stored measurements of a protocol (?id=X) as server-sent
+// events in recording order, paced by the recorded Fremco timestamps or Jetting durations.
+// ?speed=N plays back N times faster than real time (default 1), ?max_gap=S shortens pauses
+// longer than S seconds of playback (0 = never). Reconnecting clients resume after Last-Event-ID.
+func ReplayProtocolHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid protocol ID", http.StatusBadRequest)
+		return
+	}
+	speed := 1.0
+	if speedStr := r.URL.Query().Get("speed"); speedStr != "" {
+		speed, err = strconv.ParseFloat(speedStr, 64)
+		if err != nil || speed <= 0 || speed > maxReplaySpeed {
+			http.Error(w, fmt.Sprintf("Invalid speed (must be > 0 and <= %.0f)", maxReplaySpeed), http.StatusBadRequest)
+			return
+		}
+	}
+	maxGapS := defaultReplayMaxGapS
+	if gapStr := r.URL.Query().Get("max_gap"); gapStr != "" {
+		maxGapS, err = strconv.ParseFloat(gapStr, 64)
+		if err != nil || maxGapS < 0 {
+			http.Error(w, "Invalid max_gap", http.StatusBadRequest)
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var protocol struct {
+		ProtocolType string `db:"protocol_type"`
+		SystemName   string `db:"system_name"`
+		SectionNVT   string `db:"section_nvt"`
+	}
+	err = db.Get(&protocol, `
+		SELECT protocol_type, COALESCE(system_name, '') AS system_name, COALESCE(section_nvt, '') AS section_nvt
+		FROM protocols WHERE id = $1`, id)
+	if err != nil {
+		http.Error(w, "Protocol not found", http.StatusNotFound)
+		return
+	}
+	measurements, err := loadProtocolMeasurements(id)
+	if err != nil {
+		http.Error(w, "Replay failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	offsets, timing := simulator.ReplaySchedule(simulator.ReplaySamples(measurements))
+
+	// EventSource sends the id of the last received frame when it reconnects
+	resume := 0
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		if n, err := strconv.Atoi(lastID); err == nil && n >= 0 {
+			resume = n + 1
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	start := ReplayStart{
+		ProtocolID:   id,
+		ProtocolType: protocol.ProtocolType,
+		SystemName:   protocol.SystemName,
+		SectionNVT:   protocol.SectionNVT,
+		Points:       len(measurements),
+		Timing:       timing,
+		Speed:        speed,
+		MaxGapS:      maxGapS,
+	}
+	if len(offsets) > 0 {
+		start.DurationS = offsets[len(offsets)-1].Seconds()
+	}
+	if err := writeReplayEvent(w, flusher, "start", -1, start); err != nil {
+		log.Printf("ReplayProtocolHandler: Protocol %d: %v", id, err)
+		return
+	}
+	log.Printf("ReplayProtocolHandler: Replaying protocol %d (%d points from %d, %s timing, speed %.1fx)",
+		id, len(measurements), resume, timing, speed)
+
+	maxGap := time.Duration(maxGapS * float64(time.Second))
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for i := resume; i < len(measurements); i++ {
+		if i > resume {
+			delay := time.Duration(float64(offsets[i]-offsets[i-1]) / speed)
+			if maxGap > 0 && delay > maxGap {
+				delay = maxGap
+			}
+			timer.Reset(delay)
+			select {
+			case <-r.Context().Done():
+				log.Printf("ReplayProtocolHandler: Client left replay of protocol %d at point %d", id, i)
+				return
+			case <-timer.C:
+			}
+		}
+		if err := writeReplayEvent(w, flusher, "measurement", i, replayFrame(i, offsets[i], measurements[i])); err != nil {
+			log.Printf("ReplayProtocolHandler: Protocol %d: %v", id, err)
+			return
+		}
+	}
+	writeReplayEvent(w, flusher, "end", -1, map[string]int{"points": len(measurements)})
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"blowing-simulator/internal/simulator"
+)
+
+// RouteListItem represents a stored route in the route list
+type RouteListItem struct {
+	ID           int     `db:"id" json:"id"`
+	Name         string  `db:"name" json:"name"`
+	SectionNVT   string  `db:"section_nvt" json:"section_nvt"`
+	Source       string  `db:"source" json:"source"`
+	TotalLengthM float64 `db:"total_length_m" json:"total_length_m"`
+	SegmentCount int     `db:"segment_count" json:"segment_count"`
+	CreatedAt    string  `db:"created_at" json:"created_at"`
+}
+
+// SaveRoute stores a route with its segments and returns the new route ID
+func SaveRoute(route *simulator.Route) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var routeID int
+	err = tx.QueryRow(`
+		INSERT INTO routes (name, section_nvt, nvt_key, address, source, total_length_m)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		route.Name,
+		route.SectionNVT,
+		simulator.SectionNVTKey(route.SectionNVT),
+		route.Address,
+		route.Source,
+		route.TotalLength(),
+	).Scan(&routeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert route: %v", err)
+	}
+
+	for i, segment := range route.Segments {
+		_, err = tx.Exec(`
+			INSERT INTO route_segments (
+				route_id, sequence_number, length_m, bend_angle_deg, bend_radius_m, elevation_m
+			) VALUES ($1, $2, $3, $4, $5, $6)`,
+			routeID,
+			i+1,
+			segment.LengthM,
+			segment.BendAngleDeg,
+			segment.BendRadiusM,
+			segment.ElevationM,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert route segment %d: %v", i+1, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	log.Printf("SaveRoute: Saved route %d with %d segments (%.1fm)", routeID, len(route.Segments), route.TotalLength())
+	return routeID, nil
+}
+
+// LoadRoute loads a route with its segments
+func LoadRoute(routeID int) (*simulator.Route, error) {
+	var row struct {
+		ID         int            `db:"id"`
+		Name       sql.NullString `db:"name"`
+		SectionNVT sql.NullString `db:"section_nvt"`
+		Address    sql.NullString `db:"address"`
+		Source     sql.NullString `db:"source"`
+	}
+	err := db.Get(&row, `
+		SELECT id, name, section_nvt, address, source
+		FROM routes WHERE id = $1`, routeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load route: %v", err)
+	}
+
+	route := &simulator.Route{
+		ID:         row.ID,
+		Name:       row.Name.String,
+		SectionNVT: row.SectionNVT.String,
+		Address:    row.Address.String,
+		Source:     row.Source.String,
+	}
+	err = db.Select(&route.Segments, `
+		SELECT length_m, COALESCE(bend_angle_deg, 0) AS bend_angle_deg,
+		       COALESCE(bend_radius_m, 0) AS bend_radius_m, COALESCE(elevation_m, 0) AS elevation_m
+		FROM route_segments
+		WHERE route_id = $1
+		ORDER BY sequence_number`, routeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load route segments: %v", err)
+	}
+	return route, nil
+}
+
+// findRouteForSection returns the newest route of a protocol section, or nil if none is stored.
+// Routes match on the exact section string first and on the NVT identifier second.
+func findRouteForSection(sectionNVT string) (*simulator.Route, error) {
+	if strings.TrimSpace(sectionNVT) == "" {
+		return nil, nil
+	}
+	var routeID int
+	err := db.Get(&routeID, `
+		SELECT id FROM routes
+		WHERE section_nvt = $1 OR nvt_key = $2
+		ORDER BY (section_nvt = $1) DESC, created_at DESC
+		LIMIT 1`, sectionNVT, simulator.SectionNVTKey(sectionNVT))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find route: %v", err)
+	}
+	return LoadRoute(routeID)
+}
+
+// RoutesHandler lists all stored routes as JSON
+func RoutesHandler(w http.ResponseWriter, r *http.Request) {
+	var routes []RouteListItem
+	err := db.Select(&routes, `
+		SELECT r.id, COALESCE(r.name, '') AS name, COALESCE(r.section_nvt, '') AS section_nvt,
+		       COALESCE(r.source, '') AS source,
+		       COALESCE(r.total_length_m, 0) AS total_length_m,
+		       COUNT(s.id) AS segment_count, r.created_at::text
+		FROM routes r
+		LEFT JOIN route_segments s ON s.route_id = r.id
+		GROUP BY r.id
+		ORDER BY r.created_at DESC`)
+	if err != nil {
+		http.Error(w, "Error fetching routes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if routes == nil {
+		routes = []RouteListItem{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(routes)
+}
+
+// ViewRouteHandler returns a single route with its segments as JSON
+func ViewRouteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid route ID", http.StatusBadRequest)
+		return
+	}
+	route, err := LoadRoute(id)
+	if err != nil {
+		http.Error(w, "Route not found: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(route)
+}
+
+// ImportRouteHandler imports a route from an uploaded CSV file or GPS polyline.
+// Form fields: routeFile (or data), format (csv|gps), section_nvt, name, address.
+func ImportRouteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseMultipartForm(10 << 20); err != nil && err != http.ErrNotMultipart {
+		http.Error(w, "Error parsing form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Route data comes from an uploaded file or a pasted text field
+	data := r.FormValue("data")
+	format := strings.ToLower(r.FormValue("format"))
+	if file, header, err := r.FormFile("routeFile"); err == nil {
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			http.Error(w, "Error reading file: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		data = string(content)
+		if format == "" {
+			switch strings.ToLower(filepath.Ext(header.Filename)) {
+			case ".txt", ".gps":
+				format = "gps"
+			default:
+				format = "csv"
+			}
+		}
+	}
+	if strings.TrimSpace(data) == "" {
+		http.Error(w, "Route data required (routeFile or data)", http.StatusBadRequest)
+		return
+	}
+	if format == "" {
+		format = "csv"
+	}
+
+	route := &simulator.Route{
+		Name:       r.FormValue("name"),
+		SectionNVT: r.FormValue("section_nvt"),
+		Address:    r.FormValue("address"),
+		Source:     format,
+	}
+	switch format {
+	case "csv":
+		segments, err := simulator.ParseRouteCSV(strings.NewReader(data))
+		if err != nil {
+			http.Error(w, "Invalid route CSV: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		route.Segments = segments
+	case "gps":
+		points, err := simulator.ParseGPSPolyline(data)
+		if err != nil {
+			http.Error(w, "Invalid GPS polyline: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		route.Segments = simulator.RouteSegmentsFromPolyline(points)
+	default:
+		http.Error(w, "Unknown route format: "+format, http.StatusBadRequest)
+		return
+	}
+	if route.Address == "" {
+		route.Address = extractAddressFromSectionNVT(route.SectionNVT)
+	}
+
+	routeID, err := SaveRoute(route)
+	if err != nil {
+		http.Error(w, "Error saving route: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	route.ID = routeID
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(route)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"blowing-simulator/internal/simulator"
+)
+
+// SimulateHandler runs a blowing simulation for the posted parameters and returns the predicted curve.
+// With ?route_id=X the duct geometry is taken from a stored route.
+func SimulateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var input simulator.SimulationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid simulation input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if routeID := r.URL.Query().Get("route_id"); routeID != "" {
+		id, err := strconv.Atoi(routeID)
+		if err != nil {
+			http.Error(w, "Invalid route ID", http.StatusBadRequest)
+			return
+		}
+		route, err := LoadRoute(id)
+		if err != nil {
+			http.Error(w, "Route not found: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		input.Duct.Segments = route.Segments
+	}
+
+	result, err := simulator.Simulate(input)
+	if err != nil {
+		http.Error(w, "Simulation failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Printf("Simulation: duct %.1fmm, cable %.1fmm, %.1f bar, %.0f N -> max distance %.0fm (reached: %v)",
+		input.Duct.InnerDiameterMM, input.Cable.DiameterMM, input.PressureBar, input.PushForceN, result.MaxDistanceM, result.Reached)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// sweepRequest is the body of POST /sweep, optionally running the sweep on a stored route
+type sweepRequest struct {
+	simulator.SweepRequest
+	RouteID int `json:"route_id"`
+}
+
+// SweepHandler serves the what-if sweep page (GET) and runs a parameter sweep (POST)
+func SweepHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		http.ServeFile(w, r, "web/templates/sweep.html")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req sweepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid sweep request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.RouteID > 0 {
+		route, err := LoadRoute(req.RouteID)
+		if err != nil {
+			http.Error(w, "Route not found: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		req.Base.Duct.Segments = route.Segments
+	}
+
+	result, err := simulator.Sweep(req.SweepRequest)
+	if err != nil {
+		http.Error(w, "Sweep failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Printf("SweepHandler: %s x %s -> %d combinations", result.XParameter, result.YParameter, len(result.Cells))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// monteCarloRequest is the body of POST /simulate/monte-carlo. With pipe_type the friction
+// distribution is seeded from the fitted protocols of that pipe type.
+type monteCarloRequest struct {
+	simulator.MonteCarloRequest
+	RouteID  int    `json:"route_id"`
+	PipeType string `json:"pipe_type"`
+}
+
+// monteCarloResponse adds the origin of the friction distribution to the result
+type monteCarloResponse struct {
+	*simulator.MonteCarloResult
+	FrictionSource string `json:"friction_source"`
+}
+
+// MonteCarloHandler runs a simulation with uncertain inputs and returns P10/P50/P90 distances and envelopes
+func MonteCarloHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req monteCarloRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid Monte Carlo request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.RouteID > 0 {
+		route, err := LoadRoute(req.RouteID)
+		if err != nil {
+			http.Error(w, "Route not found: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		req.Base.Duct.Segments = route.Segments
+	}
+
+	frictionSource := "request"
+	if req.Uncertainty.Friction == (simulator.Distribution{}) {
+		frictionSource = "default"
+		if req.PipeType != "" {
+			distribution, count, err := loadFrictionDistribution(req.PipeType)
+			if err != nil {
+				log.Printf("MonteCarloHandler: %v", err)
+			} else if distribution != nil {
+				req.Uncertainty.Friction = *distribution
+				frictionSource = fmt.Sprintf("calibration (%d protocols with pipe type %s)", count, req.PipeType)
+			}
+		}
+	}
+
+	result, err := simulator.MonteCarlo(r.Context(), req.MonteCarloRequest)
+	if r.Context().Err() != nil {
+		log.Printf("MonteCarloHandler: Request cancelled: %v", r.Context().Err())
+		return
+	}
+	if err != nil {
+		http.Error(w, "Monte Carlo simulation failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Printf("MonteCarloHandler: %d runs -> P10 %.0fm, P50 %.0fm, P90 %.0fm (friction: %s)",
+		result.Runs, result.P10DistanceM, result.P50DistanceM, result.P90DistanceM, frictionSource)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(monteCarloResponse{result, frictionSource})
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"blowing-simulator/internal/simulator"
+)
+
+const (
+	trainingSessionTTL  = 12 * time.Hour // how long an idle training session is kept in memory
+	maxTrainingSessions = 100            // live sessions; each keeps its rows in memory
+)
+
+// trainingEntry is a training session with the time of its last action
+type trainingEntry struct {
+	session *simulator.TrainingSession
+	updated time.Time
+}
+
+var trainingStore = struct {
+	sync.RWMutex
+	sessions map[string]*trainingEntry
+}{sessions: make(map[string]*trainingEntry)}
+
+// trainingCreateRequest is the body of POST /training/sessions
+type trainingCreateRequest struct {
+	simulator.TrainingSetup
+	RouteID int `json:"route_id"` // stored route used as duct geometry, 0 = route of the section if any
+}
+
+// trainingSessionResponse is a session state with measurement rows (all rows or those of the last action)
+type trainingSessionResponse struct {
+	*simulator.TrainingSession
+	Rows []simulator.FremcoDataPoint `json:"rows"`
+}
+
+// trainingFinishResponse is returned when a session is finished
+type trainingFinishResponse struct {
+	Protocol   *simulator.FremcoProtocol `json:"protocol"`
+	ProtocolID int                       `json:"protocol_id,omitempty"` // set when the protocol was saved
+}
+
+// newTrainingSessionID returns a random session identifier
+func newTrainingSessionID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create session ID: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// pruneTrainingSessions drops sessions idle for longer than trainingSessionTTL (caller holds the lock)
+func pruneTrainingSessions(now time.Time) {
+	for id, entry := range trainingStore.sessions {
+		if now.Sub(entry.updated) > trainingSessionTTL {
+			delete(trainingStore.sessions, id)
+		}
+	}
+}
+
+// TrainingHandler serves the training page
+func TrainingHandler(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, "web/templates/training.html")
+}
+
+// TrainingSessionsHandler manages training sessions: GET lists them (?id=X returns one with
+// all rows), POST creates one from the posted setup, DELETE ?id=X discards one.
+func TrainingSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		id := r.URL.Query().Get("id")
+		trainingStore.RLock()
+		defer trainingStore.RUnlock()
+		if id == "" {
+			sessions := []*simulator.TrainingSession{}
+			for _, entry := range trainingStore.sessions {
+				sessions = append(sessions, entry.session)
+			}
+			sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartedAt.Before(sessions[j].StartedAt) })
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(sessions)
+			return
+		}
+		entry, ok := trainingStore.sessions[id]
+		if !ok {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(trainingSessionResponse{entry.session, entry.session.Rows})
+
+	case http.MethodPost:
+		var req trainingCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid training setup: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.RouteID > 0 {
+			route, err := LoadRoute(req.RouteID)
+			if err != nil {
+				http.Error(w, "Route not found: "+err.Error(), http.StatusNotFound)
+				return
+			}
+			req.Input.Duct.Segments = route.Segments
+		} else if len(req.Input.Duct.Segments) == 0 && req.Input.Duct.LengthM <= 0 {
+			route, err := findRouteForSection(req.Section)
+			if err != nil {
+				log.Printf("TrainingSessionsHandler: Route lookup for section %q failed: %v", req.Section, err)
+			} else if route != nil {
+				req.Input.Duct.Segments = route.Segments
+			}
+		}
+
+		id, err := newTrainingSessionID()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		now := time.Now()
+		session, err := simulator.NewTrainingSession(id, req.TrainingSetup, now)
+		if err != nil {
+			http.Error(w, "Invalid training setup: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		trainingStore.Lock()
+		pruneTrainingSessions(now)
+		if len(trainingStore.sessions) >= maxTrainingSessions {
+			trainingStore.Unlock()
+			http.Error(w, fmt.Sprintf("Too many training sessions (max %d), finish or discard one first", maxTrainingSessions), http.StatusServiceUnavailable)
+			return
+		}
+		trainingStore.sessions[session.ID] = &trainingEntry{session: session, updated: now}
+		trainingStore.Unlock()
+		log.Printf("TrainingSessionsHandler: Started session %s (%s, %.0fm section)", session.ID, session.Setup.Section, session.SectionLengthM)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(trainingSessionResponse{session, session.Rows})
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		trainingStore.Lock()
+		_, ok := trainingStore.sessions[id]
+		delete(trainingStore.sessions, id)
+		trainingStore.Unlock()
+		if !ok {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// TrainingActionHandler applies a control action to a session (?id=X) and returns the new
+// session state with the measurement rows logged while the action ran
+func TrainingActionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var action simulator.TrainingAction
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+		http.Error(w, "Invalid action: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trainingStore.Lock()
+	defer trainingStore.Unlock()
+	entry, ok := trainingStore.sessions[r.URL.Query().Get("id")]
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	rows, err := entry.session.Apply(action)
+	if err != nil {
+		http.Error(w, "Action failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	entry.updated = time.Now()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trainingSessionResponse{entry.session, rows})
+}
+
+// TrainingFinishHandler ends a session (?id=X) and returns its Fremco protocol.
+// With ?save=true the protocol is stored like an imported one (marked as training
+// run by its parser version); the session is put back if saving fails so the
+// trainee can retry.
+func TrainingFinishHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.URL.Query().Get("id")
+
+	// Take the session out so that no action runs while it is saved
+	trainingStore.Lock()
+	entry, ok := trainingStore.sessions[id]
+	delete(trainingStore.sessions, id)
+	trainingStore.Unlock()
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	restore := func() {
+		trainingStore.Lock()
+		trainingStore.sessions[id] = entry
+		trainingStore.Unlock()
+	}
+
+	protocol, err := entry.session.Protocol()
+	if err != nil {
+		restore()
+		http.Error(w, "Finish failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := trainingFinishResponse{Protocol: protocol}
+	if r.URL.Query().Get("save") == "true" {
+		protocolID, err := SaveProtocol(db, protocol)
+		if err != nil {
+			restore()
+			http.Error(w, "Save failed: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response.ProtocolID = protocolID
+		log.Printf("TrainingFinishHandler: Session %s saved as protocol %d", id, protocolID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
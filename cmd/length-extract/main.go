@@ -0,0 +1,131 @@
+// Command length-extract lists the installed length of every protocol PDF in a
+// directory tree as CSV, optionally limited to a date range taken from the file names:
+//
+//	length-extract [dir] [DD.MM.YYYY [DD.MM.YYYY]]
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blowing-simulator/internal/pdftext"
+	"blowing-simulator/internal/simulator"
+)
+
+// lengthEntry is the installed length of one protocol file
+type lengthEntry struct {
+	date    string
+	address string
+	lengthM string // empty if the file could not be parsed
+}
+
+func main() {
+	args := os.Args[1:]
+	dir := "."
+	if len(args) >= 1 {
+		dir = args[0]
+	}
+	var from, to time.Time
+	var err error
+	if len(args) >= 2 {
+		if from, err = time.Parse("02.01.2006", args[1]); err != nil {
+			log.Fatalf("Invalid date %q, use DD.MM.YYYY", args[1])
+		}
+		to = from
+	}
+	if len(args) >= 3 {
+		if to, err = time.Parse("02.01.2006", args[2]); err != nil {
+			log.Fatalf("Invalid date %q, use DD.MM.YYYY", args[2])
+		}
+	}
+
+	var entries []lengthEntry
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".pdf") {
+			return err
+		}
+		info := simulator.ParseProtocolFilename(d.Name())
+		if !from.IsZero() {
+			date, err := time.Parse("02.01.2006", info.Date)
+			if err != nil || date.Before(from) || date.After(to) {
+				return nil
+			}
+		}
+		entry := lengthEntry{date: info.Date, address: info.SectionNVT()}
+		if lengthM, err := installedLength(path, d.Name()); err != nil {
+			log.Printf("%s: %v", path, err)
+		} else {
+			entry.lengthM = strconv.FormatFloat(lengthM, 'f', -1, 64)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to scan %s: %v", dir, err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		di, dj := sortableDate(entries[i].date), sortableDate(entries[j].date)
+		if di != dj {
+			return di < dj
+		}
+		return entries[i].address < entries[j].address
+	})
+
+	w := csv.NewWriter(os.Stdout)
+	w.Comma = ';'
+	w.Write([]string{"date", "address", "length_m"})
+	total := 0.0
+	addresses := map[string]bool{}
+	for _, e := range entries {
+		w.Write([]string{e.date, e.address, e.lengthM})
+		addresses[e.address] = true
+		if v, err := strconv.ParseFloat(e.lengthM, 64); err == nil {
+			total += v
+		}
+	}
+	w.Write([]string{"SUM", "", strconv.FormatFloat(total, 'f', -1, 64)})
+	w.Write([]string{"UNIQUE_ADDRESSES", "", strconv.Itoa(len(addresses))})
+	w.Flush()
+
+	fmt.Fprintf(os.Stderr, "Total distance: %.1f m, unique addresses: %d, entries: %d\n", total, len(addresses), len(entries))
+}
+
+// sortableDate turns DD.MM.YYYY into YYYYMMDD
+func sortableDate(date string) string {
+	if parts := strings.Split(date, "."); len(parts) == 3 {
+		return parts[2] + parts[1] + parts[0]
+	}
+	return date
+}
+
+// installedLength extracts and parses a protocol PDF and returns its longest installed length
+func installedLength(path, filename string) (float64, error) {
+	text, err := pdftext.ExtractText(path)
+	if err != nil {
+		return 0, err
+	}
+	detection := simulator.DetectFormat(text, filename)
+	if detection.Review {
+		return 0, fmt.Errorf("format needs review: %s", detection.ReviewReason)
+	}
+	result, _, err := simulator.ParseWith(detection.Parser, text, simulator.ParseOptions{})
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range result.Errors {
+		log.Printf("%s: %s", filename, d)
+	}
+	if result.Protocol.DataPointCount() == 0 {
+		return 0, fmt.Errorf("no measurements found")
+	}
+	return result.Protocol.MaxLengthM(), nil
+}
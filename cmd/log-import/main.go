@@ -0,0 +1,125 @@
+// Command log-import reads raw Fremco and Jetting controller logs (CSV exports
+// from the machine's USB stick). Without -server it prints each parsed log as
+// JSON; with -server it uploads the logs to /protocols/import-log, which
+// attaches them to the matching PDF protocol or saves them as new protocols:
+//
+//	log-import [-server http://localhost:8080] [-new] [-strict] file...
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"blowing-simulator/internal/simulator"
+)
+
+func main() {
+	server := flag.String("server", "", "Blowing simulator URL to import the logs into (empty = print parsed logs as JSON)")
+	newProtocol := flag.Bool("new", false, "Save every log as a new protocol instead of attaching it to its PDF protocol")
+	strict := flag.Bool("strict", false, "Reject logs with unreadable values")
+	flag.Parse()
+	files := flag.Args()
+	if len(files) == 0 {
+		log.Fatalf("Usage: log-import [-server URL] [-new] [-strict] file...")
+	}
+
+	if *server != "" {
+		if err := upload(*server, files, *newProtocol, *strict); err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+		return
+	}
+
+	failed := false
+	opts := simulator.ParseOptions{Strict: *strict}
+	for _, path := range files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("%s: %v", path, err)
+			failed = true
+			continue
+		}
+		l, result, err := simulator.ParseMachineLog(string(content), filepath.Base(path), opts)
+		if result != nil {
+			for _, d := range result.Diagnostics() {
+				log.Printf("%s: line %d, %s: %s (%q)", path, d.Line, d.Field, d.Reason, d.Raw)
+			}
+		}
+		if err != nil {
+			log.Printf("%s: %v", path, err)
+			failed = true
+			continue
+		}
+		out, err := json.MarshalIndent(l, "", "  ")
+		if err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+		fmt.Println(string(out))
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// upload posts the log files to the import endpoint and prints its JSON results
+func upload(server string, files []string, newProtocol, strict bool) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for _, path := range files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		part, err := form.CreateFormFile("logFiles", filepath.Base(path))
+		if err != nil {
+			return err
+		}
+		part.Write(content)
+	}
+	if newProtocol {
+		form.WriteField("mode", "new")
+	}
+	if strict {
+		form.WriteField("strict", "true")
+	}
+	form.Close()
+
+	url := strings.TrimSuffix(server, "/") + "/protocols/import-log"
+	resp, err := http.Post(url, form.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(response)))
+	}
+
+	var results []map[string]interface{}
+	if err := json.Unmarshal(response, &results); err != nil {
+		return fmt.Errorf("unexpected response: %v", err)
+	}
+	for _, r := range results {
+		switch {
+		case r["success"] != true:
+			fmt.Printf("%s: failed: %v\n", r["filename"], r["error"])
+		case r["attached"] == true:
+			fmt.Printf("%s: %v measurements attached to protocol %v\n", r["filename"], r["measurements"], r["protocol_id"])
+		default:
+			fmt.Printf("%s: %v measurements saved as new protocol %v\n", r["filename"], r["measurements"], r["protocol_id"])
+		}
+	}
+	return nil
+}
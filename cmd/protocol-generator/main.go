@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"blowing-simulator/internal/simulator"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// generatedFile is one generated protocol with its rendered text pages
+type generatedFile struct {
+	name     string // base file name without extension
+	protocol simulator.Protocol
+	pages    []string
+}
+
+func main() {
+	format := flag.String("format", "fremco", "Protocol format: 'fremco' or 'jetting'")
+	count := flag.Int("count", 1, "Number of protocols to generate")
+	outDir := flag.String("out", ".", "Output directory")
+	seed := flag.Int64("seed", 0, "Random seed of the first protocol (0 = random); protocol i uses seed+i")
+	length := flag.Float64("length", 0, "Section length in m (0 = random)")
+	stalls := flag.Int("stalls", -1, "Cable stalls per protocol (-1 = random)")
+	restarts := flag.Int("restarts", -1, "Restarts with the air turned off per protocol (-1 = random)")
+	rowsPerPage := flag.Int("rows-per-page", 0, "Table rows per page (0 = format default)")
+	writeJSON := flag.Bool("json", true, "Also write the generated protocol as JSON (expected parser output)")
+	writePDF := flag.Bool("pdf", false, "Also write the rendered text as PDF (for bulk upload load tests)")
+	fontPath := flag.String("font", "web/static/fonts/DejaVuSans.ttf", "TrueType font used for PDF output")
+	check := flag.Bool("check", true, "Parse the rendered text again and fail if it does not match the JSON")
+	flag.Parse()
+
+	if *format != "fremco" && *format != "jetting" {
+		fmt.Fprintln(os.Stderr, "Usage: protocol-generator [-format fremco|jetting] [-count N] [-out dir] [-seed N] [-pdf]")
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	for i := 0; i < *count; i++ {
+		opts := simulator.GeneratorOptions{
+			LengthM:  *length,
+			Stalls:   *stalls,
+			Restarts: *restarts,
+		}
+		if *seed != 0 {
+			opts.Seed = *seed + int64(i)
+		}
+
+		file, err := generate(*format, opts, *rowsPerPage)
+		if err != nil {
+			log.Fatalf("Failed to generate protocol %d: %v", i+1, err)
+		}
+		if *check {
+			diffs, err := simulator.RoundTripDiff(file.protocol, *rowsPerPage)
+			if err != nil {
+				log.Fatalf("Failed to parse protocol %d: %v", i+1, err)
+			}
+			if len(diffs) > 0 {
+				log.Fatalf("Protocol %d does not round-trip:\n  %s", i+1, strings.Join(diffs, "\n  "))
+			}
+		}
+		base := filepath.Join(*outDir, file.name)
+
+		if err := os.WriteFile(base+".txt", []byte(strings.Join(file.pages, "\n")+"\n"), 0644); err != nil {
+			log.Fatalf("Failed to write text file: %v", err)
+		}
+		if *writeJSON {
+			data, err := json.MarshalIndent(file.protocol, "", "  ")
+			if err != nil {
+				log.Fatalf("Failed to encode JSON: %v", err)
+			}
+			if err := os.WriteFile(base+".json", data, 0644); err != nil {
+				log.Fatalf("Failed to write JSON file: %v", err)
+			}
+		}
+		if *writePDF {
+			if err := writePages(base+".pdf", file.pages, *fontPath); err != nil {
+				log.Fatalf("Failed to write PDF file: %v", err)
+			}
+		}
+		fmt.Printf("Generated %s (%d pages)\n", base, len(file.pages))
+	}
+}
+
+// generate creates one protocol of the given format and renders it
+func generate(format string, opts simulator.GeneratorOptions, rowsPerPage int) (*generatedFile, error) {
+	if format == "jetting" {
+		p, err := simulator.GenerateJettingProtocol(opts)
+		if err != nil {
+			return nil, err
+		}
+		return &generatedFile{
+			name:     strings.TrimSuffix(p.ExportMetadata.SourceFilename, ".pdf"),
+			protocol: p,
+			pages:    simulator.RenderJettingPages(p, rowsPerPage),
+		}, nil
+	}
+	p, err := simulator.GenerateFremcoProtocol(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &generatedFile{
+		name:     strings.TrimSuffix(p.ExportMetadata.SourceFilename, ".pdf"),
+		protocol: p,
+		pages:    simulator.RenderFremcoPages(p, rowsPerPage),
+	}, nil
+}
+
+// writePages writes every text page as a PDF page, one text line per line
+func writePages(path string, pages []string, fontPath string) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("DejaVu", "", fontPath)
+	pdf.SetFont("DejaVu", "", 8)
+	pdf.SetAutoPageBreak(true, 10)
+	for _, page := range pages {
+		pdf.AddPage()
+		for _, line := range strings.Split(page, "\n") {
+			pdf.CellFormat(0, 3.5, line, "", 1, "L", false, 0, "")
+		}
+	}
+	return pdf.OutputFileAndClose(path)
+}
@@ -0,0 +1,209 @@
+package pdftext
+
+import (
+	"math"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// maxFormDepth limits nested form XObjects
+const maxFormDepth = 8
+
+// matrix is a PDF transformation matrix [a b c d e f]
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// mul returns m × n (apply m, then n)
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// matrixOf reads a 6-number array operand
+func matrixOf(args []pdf.Value) matrix {
+	var m matrix
+	for i := 0; i < 6 && i < len(args); i++ {
+		m[i] = args[i].Float64()
+	}
+	return m
+}
+
+// graphicsState holds the parts of the PDF graphics and text state that position text
+type graphicsState struct {
+	ctm   matrix
+	tm    matrix
+	tlm   matrix
+	tc    float64 // character spacing
+	tw    float64 // word spacing
+	th    float64 // horizontal scaling
+	tl    float64 // leading
+	tfs   float64 // font size
+	trise float64
+	font  *font
+}
+
+// contentReader interprets content streams and collects the shown glyphs
+type contentReader struct {
+	glyphs []Glyph
+}
+
+// pageGlyphs returns the glyphs shown on a page
+func pageGlyphs(p pdf.Page) []Glyph {
+	var c contentReader
+	c.run(p.V.Key("Contents"), p.Resources(), identity, 0)
+	return c.glyphs
+}
+
+// run interprets one content stream (or array of streams) with its resources
+func (c *contentReader) run(strm, resources pdf.Value, ctm matrix, depth int) {
+	if strm.IsNull() {
+		return
+	}
+	fonts := map[string]*font{}
+	g := graphicsState{ctm: ctm, tm: identity, tlm: identity, th: 1}
+	var stack []graphicsState
+
+	pdf.Interpret(strm, func(stk *pdf.Stack, op string) {
+		n := stk.Len()
+		args := make([]pdf.Value, n)
+		for i := n - 1; i >= 0; i-- {
+			args[i] = stk.Pop()
+		}
+		arg := func(i int) float64 {
+			if i < len(args) {
+				return args[i].Float64()
+			}
+			return 0
+		}
+		nextLine := func(tx, ty float64) {
+			g.tlm = matrix{1, 0, 0, 1, tx, ty}.mul(g.tlm)
+			g.tm = g.tlm
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, g)
+		case "Q":
+			if len(stack) > 0 {
+				g = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "cm":
+			g.ctm = matrixOf(args).mul(g.ctm)
+		case "BT":
+			g.tm, g.tlm = identity, identity
+		case "Tc":
+			g.tc = arg(0)
+		case "Tw":
+			g.tw = arg(0)
+		case "Tz":
+			g.th = arg(0) / 100
+		case "TL":
+			g.tl = arg(0)
+		case "Ts":
+			g.trise = arg(0)
+		case "Tf":
+			if len(args) == 2 {
+				name := args[0].Name()
+				if _, ok := fonts[name]; !ok {
+					fonts[name] = loadFont(resources.Key("Font").Key(name))
+				}
+				g.font = fonts[name]
+				g.tfs = args[1].Float64()
+			}
+		case "Td":
+			nextLine(arg(0), arg(1))
+		case "TD":
+			g.tl = -arg(1)
+			nextLine(arg(0), arg(1))
+		case "Tm":
+			g.tm = matrixOf(args)
+			g.tlm = g.tm
+		case "T*":
+			nextLine(0, -g.tl)
+		case "Tj":
+			if len(args) == 1 {
+				c.show(&g, args[0].RawString())
+			}
+		case "'":
+			if len(args) == 1 {
+				nextLine(0, -g.tl)
+				c.show(&g, args[0].RawString())
+			}
+		case "\"":
+			if len(args) == 3 {
+				g.tw, g.tc = args[0].Float64(), args[1].Float64()
+				nextLine(0, -g.tl)
+				c.show(&g, args[2].RawString())
+			}
+		case "TJ":
+			if len(args) == 1 {
+				a := args[0]
+				for i := 0; i < a.Len(); i++ {
+					if v := a.Index(i); v.Kind() == pdf.String {
+						c.show(&g, v.RawString())
+					} else {
+						tx := -v.Float64() / 1000 * g.tfs * g.th
+						g.tm = matrix{1, 0, 0, 1, tx, 0}.mul(g.tm)
+					}
+				}
+			}
+		case "Do":
+			if len(args) == 1 && depth < maxFormDepth {
+				xobj := resources.Key("XObject").Key(args[0].Name())
+				if xobj.Key("Subtype").Name() == "Form" {
+					formMatrix := identity
+					if m := xobj.Key("Matrix"); m.Len() == 6 {
+						formMatrix = matrix{m.Index(0).Float64(), m.Index(1).Float64(), m.Index(2).Float64(),
+							m.Index(3).Float64(), m.Index(4).Float64(), m.Index(5).Float64()}
+					}
+					formResources := xobj.Key("Resources")
+					if formResources.IsNull() {
+						formResources = resources
+					}
+					c.run(xobj, formResources, formMatrix.mul(g.ctm), depth+1)
+				}
+			}
+		}
+	})
+}
+
+// show adds the glyphs of a shown string and advances the text matrix
+func (c *contentReader) show(g *graphicsState, s string) {
+	f := g.font
+	if f == nil {
+		f = defaultFont
+	}
+	for i := 0; i+f.codeLen <= len(s); i += f.codeLen {
+		raw := s[i : i+f.codeLen]
+		code := 0
+		for j := 0; j < len(raw); j++ {
+			code = code<<8 | int(raw[j])
+		}
+
+		tx := f.width(code) / 1000 * g.tfs
+		tx += g.tc
+		if f.codeLen == 1 && code == ' ' {
+			tx += g.tw
+		}
+		tx *= g.th
+
+		m := g.tm.mul(g.ctm)
+		trm := matrix{g.tfs * g.th, 0, 0, g.tfs, 0, g.trise}.mul(m)
+		c.glyphs = append(c.glyphs, Glyph{
+			X:        trm[4],
+			Y:        trm[5],
+			W:        tx * math.Hypot(m[0], m[1]),
+			FontSize: math.Hypot(trm[2], trm[3]),
+			Text:     f.enc.Decode(raw),
+		})
+		g.tm = matrix{1, 0, 0, 1, tx, 0}.mul(g.tm)
+	}
+}
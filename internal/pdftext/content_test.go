@@ -0,0 +1,175 @@
+package pdftext
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strings"
+	"testing"
+)
+
+// buildPDF returns a one-page PDF showing the content stream. The page has
+// the font /F1 (Courier, every glyph 600/1000 em wide) and the form XObject
+// /X1 that shows "X" with /F1 at 10 pt, moved 100 pt to the right.
+func buildPDF(content string) []byte {
+	form := "BT /F1 10 Tf (X) Tj ET"
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] " +
+			"/Resources << /Font << /F1 4 0 R >> /XObject << /X1 6 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content)+1, content),
+		fmt.Sprintf("<< /Type /XObject /Subtype /Form /BBox [0 0 595 842] /Matrix [1 0 0 1 100 0] /Length %d >>\nstream\n%s\nendstream", len(form)+1, form),
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+// extractContent extracts the glyphs of a PDF built from the content stream
+func extractContent(t *testing.T, content string) []Glyph {
+	t.Helper()
+	data := buildPDF(content)
+	pages, err := Extract(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Extract(%q): %v", content, err)
+	}
+	if len(pages) != 1 {
+		t.Fatalf("Extract(%q): got %d pages, want 1", content, len(pages))
+	}
+	return pages[0].Glyphs
+}
+
+// glyphAt is the expected position and size of a shown glyph
+type glyphAt struct {
+	text     string
+	x, y, w  float64
+	fontSize float64
+}
+
+func TestContentOperators(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []glyphAt
+	}{
+		{
+			name:    "Tj advances by the glyph width",
+			content: "BT /F1 10 Tf 100 700 Td (AB) Tj ET",
+			want:    []glyphAt{{"A", 100, 700, 6, 10}, {"B", 106, 700, 6, 10}},
+		},
+		{
+			name:    "TJ kerning moves left for positive and right for negative numbers",
+			content: "BT /F1 10 Tf 100 700 Td [(A) -1000 (B) 500 (C)] TJ ET",
+			want:    []glyphAt{{"A", 100, 700, 6, 10}, {"B", 116, 700, 6, 10}, {"C", 117, 700, 6, 10}},
+		},
+		{
+			name:    "Td is relative to the start of the previous line",
+			content: "BT /F1 10 Tf 50 700 Td (AB) Tj 10 -20 Td (C) Tj ET",
+			want:    []glyphAt{{"A", 50, 700, 6, 10}, {"B", 56, 700, 6, 10}, {"C", 60, 680, 6, 10}},
+		},
+		{
+			name:    "T* and ' use the leading, TD sets it",
+			content: "BT /F1 10 Tf 14 TL 50 700 Td (A) Tj T* (B) Tj (C) ' 0 -20 TD (D) Tj T* (E) Tj ET",
+			want: []glyphAt{
+				{"A", 50, 700, 6, 10}, {"B", 50, 686, 6, 10}, {"C", 50, 672, 6, 10},
+				{"D", 50, 652, 6, 10}, {"E", 50, 632, 6, 10},
+			},
+		},
+		{
+			name:    "\" sets word and character spacing",
+			content: "BT /F1 10 Tf 14 TL 50 700 Td 3 1 (A A) \" ET",
+			want:    []glyphAt{{"A", 50, 686, 7, 10}, {" ", 57, 686, 10, 10}, {"A", 67, 686, 7, 10}},
+		},
+		{
+			name:    "Tm scales the font size",
+			content: "BT /F1 1 Tf 12 0 0 12 200 500 Tm (AB) Tj ET",
+			want:    []glyphAt{{"A", 200, 500, 7.2, 12}, {"B", 207.2, 500, 7.2, 12}},
+		},
+		{
+			name:    "Tc, Tw and Tz change the advance",
+			content: "BT /F1 10 Tf 2 Tc 5 Tw (A B) Tj 50 Tz (C) Tj ET",
+			want:    []glyphAt{{"A", 0, 0, 8, 10}, {" ", 8, 0, 13, 10}, {"B", 21, 0, 8, 10}, {"C", 29, 0, 4, 10}},
+		},
+		{
+			name:    "Ts raises the baseline",
+			content: "BT /F1 10 Tf 3 Ts (A) Tj ET",
+			want:    []glyphAt{{"A", 0, 3, 6, 10}},
+		},
+		{
+			name:    "BT resets the text matrix",
+			content: "BT /F1 10 Tf 50 700 Td (A) Tj ET BT /F1 10 Tf (B) Tj ET",
+			want:    []glyphAt{{"A", 50, 700, 6, 10}, {"B", 0, 0, 6, 10}},
+		},
+		{
+			name:    "cm applies until Q restores the state",
+			content: "q 1 0 0 1 10 20 cm BT /F1 10 Tf (A) Tj ET Q BT /F1 10 Tf (B) Tj ET",
+			want:    []glyphAt{{"A", 10, 20, 6, 10}, {"B", 0, 0, 6, 10}},
+		},
+		{
+			name:    "cm scales glyph width and font size",
+			content: "2 0 0 2 0 0 cm BT /F1 10 Tf 5 5 Td (A) Tj ET",
+			want:    []glyphAt{{"A", 10, 10, 12, 20}},
+		},
+		{
+			name:    "form XObjects are shown with their matrix",
+			content: "q 1 0 0 1 0 50 cm /X1 Do Q",
+			want:    []glyphAt{{"X", 100, 50, 6, 10}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			glyphs := extractContent(t, tt.content)
+			if len(glyphs) != len(tt.want) {
+				t.Fatalf("got %d glyphs %+v, want %d", len(glyphs), glyphs, len(tt.want))
+			}
+			for i, w := range tt.want {
+				g := glyphs[i]
+				if g.Text != w.text || !near(g.X, w.x) || !near(g.Y, w.y) || !near(g.W, w.w) || !near(g.FontSize, w.fontSize) {
+					t.Errorf("glyph %d = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractMalformedContent(t *testing.T) {
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+
+	data := buildPDF("BT /F1 10 Tf <zz> Tj ET")
+	pages, err := Extract(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Fatalf("Extract returned %d pages and no error for a malformed content stream", len(pages))
+	}
+	if !strings.Contains(err.Error(), "page 1") {
+		t.Errorf("error %q does not name the page", err)
+	}
+}
+
+func TestExtractNotAPDF(t *testing.T) {
+	data := []byte("Länge[m]  Schubkraft[N]\n")
+	if _, err := Extract(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error("Extract accepted a text file")
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
@@ -0,0 +1,96 @@
+package pdftext
+
+import (
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Glyph widths (in 1/1000 em) used when a font does not list its widths
+const (
+	defaultGlyphWidth   = 500
+	monospaceGlyphWidth = 600
+	defaultCIDWidth     = 1000
+)
+
+// font decodes character codes of one PDF font and knows their widths
+type font struct {
+	enc      pdf.TextEncoding
+	codeLen  int // bytes per character code: 1 for simple fonts, 2 for Type0 (CID) fonts
+	widths   map[int]float64
+	missingW float64
+}
+
+// defaultFont is used when text is shown before a font is selected
+var defaultFont = &font{enc: nopEncoding{}, codeLen: 1, missingW: defaultGlyphWidth}
+
+// nopEncoding passes character codes through unchanged
+type nopEncoding struct{}
+
+func (nopEncoding) Decode(raw string) string { return raw }
+
+// loadFont reads the encoding and glyph widths of a font dictionary
+func loadFont(v pdf.Value) *font {
+	if v.IsNull() {
+		return defaultFont
+	}
+	f := &font{codeLen: 1, widths: map[int]float64{}}
+	f.enc = pdf.Font{V: v}.Encoder()
+	if f.enc == nil {
+		f.enc = nopEncoding{}
+	}
+
+	if v.Key("Subtype").Name() == "Type0" {
+		f.codeLen = 2
+		desc := v.Key("DescendantFonts").Index(0)
+		f.missingW = defaultCIDWidth
+		if dw := desc.Key("DW"); !dw.IsNull() {
+			f.missingW = dw.Float64()
+		}
+		// W lists "c [w1 w2 ...]" or "cFirst cLast w"
+		w := desc.Key("W")
+		for i := 0; i < w.Len(); {
+			first := int(w.Index(i).Int64())
+			if i+1 < w.Len() && w.Index(i+1).Kind() == pdf.Array {
+				list := w.Index(i + 1)
+				for j := 0; j < list.Len(); j++ {
+					f.widths[first+j] = list.Index(j).Float64()
+				}
+				i += 2
+				continue
+			}
+			if i+2 >= w.Len() {
+				break
+			}
+			last := int(w.Index(i + 1).Int64())
+			for code := first; code <= last && code-first < 65536; code++ {
+				f.widths[code] = w.Index(i + 2).Float64()
+			}
+			i += 3
+		}
+		return f
+	}
+
+	first := int(v.Key("FirstChar").Int64())
+	widths := v.Key("Widths")
+	for i := 0; i < widths.Len(); i++ {
+		f.widths[first+i] = widths.Index(i).Float64()
+	}
+	f.missingW = v.Key("FontDescriptor").Key("MissingWidth").Float64()
+	if f.missingW == 0 {
+		// Standard 14 fonts come without widths
+		f.missingW = defaultGlyphWidth
+		if strings.Contains(v.Key("BaseFont").Name(), "Courier") {
+			f.missingW = monospaceGlyphWidth
+		}
+	}
+	return f
+}
+
+// width returns the glyph width of a character code in 1/1000 em
+func (f *font) width(code int) float64 {
+	if w, ok := f.widths[code]; ok {
+		return w
+	}
+	return f.missingW
+}
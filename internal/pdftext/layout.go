@@ -0,0 +1,178 @@
+package pdftext
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Layout thresholds, relative to the font size
+const (
+	lineTolerance = 0.4  // baselines closer than this belong to the same row
+	wordGap       = 0.15 // a wider gap between glyphs separates words
+	cellGap       = 0.8  // a wider gap separates table cells
+	minCellSpaces = 2    // cells of a row are separated by at least this many spaces
+)
+
+// Cell is a run of words on one row, separated from its neighbours by a column gap
+type Cell struct {
+	X0   float64
+	X1   float64
+	Text string
+}
+
+// Line is one row of text: all glyphs sharing a baseline, split into cells
+type Line struct {
+	Y     float64
+	Cells []Cell
+}
+
+// Text returns the cells of the line separated by two spaces
+func (l Line) Text() string {
+	texts := make([]string, len(l.Cells))
+	for i, c := range l.Cells {
+		texts[i] = c.Text
+	}
+	return strings.Join(texts, "  ")
+}
+
+// isSpace reports whether a glyph shows only white space
+func isSpace(g Glyph) bool {
+	return strings.TrimSpace(g.Text) == ""
+}
+
+// Lines groups the glyphs of the page into rows from top to bottom and each
+// row into cells from left to right
+func (p Page) Lines() []Line {
+	// Glyphs in content stream order, then by baseline
+	order := make([]int, 0, len(p.Glyphs))
+	for i, g := range p.Glyphs {
+		if g.Text != "" {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return p.Glyphs[order[a]].Y > p.Glyphs[order[b]].Y })
+
+	var lines []Line
+	for start := 0; start < len(order); {
+		top := p.Glyphs[order[start]]
+		tolerance := math.Max(top.FontSize*lineTolerance, 1)
+		end := start + 1
+		for end < len(order) && top.Y-p.Glyphs[order[end]].Y <= tolerance {
+			end++
+		}
+		row := append([]int(nil), order[start:end]...)
+		sort.Ints(row)
+		glyphs := make([]Glyph, len(row))
+		for i, idx := range row {
+			glyphs[i] = p.Glyphs[idx]
+		}
+		if cells := rowCells(glyphs); len(cells) > 0 {
+			lines = append(lines, Line{Y: top.Y, Cells: cells})
+		}
+		start = end
+	}
+	return lines
+}
+
+// rowCells splits the glyphs of one row (in content stream order) into runs of
+// text shown left to right, then orders the runs by position and joins runs
+// that are closer than a column gap into cells. Working on runs keeps text
+// that overflows into the neighbouring cell from being interleaved with it.
+func rowCells(row []Glyph) []Cell {
+	type textRun struct {
+		Cell
+		fontSize float64
+	}
+	var runs []textRun
+	var text strings.Builder
+	var run textRun
+	space := false
+	flush := func() {
+		if text.Len() > 0 {
+			run.Text = text.String()
+			runs = append(runs, run)
+		}
+		text.Reset()
+	}
+
+	for _, g := range row {
+		if isSpace(g) {
+			space = true
+			continue
+		}
+		gap := g.X - run.X1
+		switch {
+		case text.Len() == 0:
+			run = textRun{Cell{X0: g.X}, g.FontSize}
+		case gap < -g.FontSize*wordGap || gap > g.FontSize*cellGap:
+			flush()
+			run = textRun{Cell{X0: g.X}, g.FontSize}
+		case space || gap > g.FontSize*wordGap:
+			text.WriteByte(' ')
+		}
+		text.WriteString(g.Text)
+		space = false
+		run.X1 = math.Max(run.X1, g.X+g.W)
+	}
+	flush()
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].X0 < runs[j].X0 })
+	var cells []Cell
+	for _, r := range runs {
+		if n := len(cells); n > 0 && r.X0-cells[n-1].X1 <= r.fontSize*cellGap {
+			cells[n-1].Text += " " + r.Text
+			cells[n-1].X1 = math.Max(cells[n-1].X1, r.X1)
+			continue
+		}
+		cells = append(cells, r.Cell)
+	}
+	return cells
+}
+
+// charWidth returns the median glyph width of the page, used as the width of one text column
+func (p Page) charWidth() float64 {
+	var widths []float64
+	for _, g := range p.Glyphs {
+		if g.W > 0 && !isSpace(g) {
+			widths = append(widths, g.W)
+		}
+	}
+	if len(widths) == 0 {
+		return 5
+	}
+	sort.Float64s(widths)
+	return widths[len(widths)/2]
+}
+
+// Layout renders the page as plain text, every row on one line with its cells
+// at the character column of their X position, so table columns line up
+func (p Page) Layout() string {
+	lines := p.Lines()
+	if len(lines) == 0 {
+		return ""
+	}
+	charWidth := p.charWidth()
+	left := math.Inf(1)
+	for _, l := range lines {
+		left = math.Min(left, l.Cells[0].X0)
+	}
+
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		var b strings.Builder
+		cursor := 0
+		for j, c := range l.Cells {
+			col := int(math.Round((c.X0 - left) / charWidth))
+			if j > 0 && col < cursor+minCellSpaces {
+				col = cursor + minCellSpaces
+			}
+			b.WriteString(strings.Repeat(" ", col-cursor))
+			b.WriteString(c.Text)
+			cursor = col + utf8.RuneCountInString(c.Text)
+		}
+		out[i] = b.String()
+	}
+	return strings.Join(out, "\n")
+}
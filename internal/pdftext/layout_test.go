@@ -0,0 +1,146 @@
+package pdftext
+
+import (
+	"reflect"
+	"testing"
+)
+
+// word returns the glyphs of a text shown at x on baseline y with a
+// monospace font of the given size (every glyph 0.6 em wide)
+func word(x, y, size float64, text string) []Glyph {
+	var glyphs []Glyph
+	for _, r := range text {
+		glyphs = append(glyphs, Glyph{X: x, Y: y, W: 0.6 * size, FontSize: size, Text: string(r)})
+		x += 0.6 * size
+	}
+	return glyphs
+}
+
+// glyphs concatenates glyph runs in content stream order
+func glyphs(runs ...[]Glyph) []Glyph {
+	var all []Glyph
+	for _, r := range runs {
+		all = append(all, r...)
+	}
+	return all
+}
+
+func cellTexts(cells []Cell) []string {
+	texts := make([]string, len(cells))
+	for i, c := range cells {
+		texts[i] = c.Text
+	}
+	return texts
+}
+
+func TestRowCells(t *testing.T) {
+	tests := []struct {
+		name string
+		row  []Glyph
+		want []string
+	}{
+		{
+			name: "adjacent glyphs form a word",
+			row:  word(0, 0, 10, "Länge"),
+			want: []string{"Länge"},
+		},
+		{
+			name: "space glyphs separate words",
+			row:  word(0, 0, 10, "Zeit - Dauer"),
+			want: []string{"Zeit - Dauer"},
+		},
+		{
+			name: "a small gap without space glyph separates words",
+			row:  glyphs(word(0, 0, 10, "Am"), word(15, 0, 10, "Bahnhof")),
+			want: []string{"Am Bahnhof"},
+		},
+		{
+			name: "a column gap separates cells",
+			row:  glyphs(word(0, 0, 10, "10"), word(50, 0, 10, "17.6"), word(100, 0, 10, "0")),
+			want: []string{"10", "17.6", "0"},
+		},
+		{
+			name: "cells are ordered by position, not content stream order",
+			row:  glyphs(word(100, 0, 10, "c"), word(0, 0, 10, "a"), word(50, 0, 10, "b")),
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "text overflowing into the next cell is not interleaved with it",
+			row:  glyphs(word(0, 0, 10, "Einblasdruck"), word(30, 0, 10, "bar")),
+			want: []string{"Einblasdruck bar"},
+		},
+		{
+			name: "leading and trailing spaces are dropped",
+			row:  word(0, 0, 10, "  17.6  "),
+			want: []string{"17.6"},
+		},
+		{
+			name: "only spaces",
+			row:  word(0, 0, 10, "   "),
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cellTexts(rowCells(tt.row)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rowCells() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageLines(t *testing.T) {
+	page := Page{Glyphs: glyphs(
+		word(0, 680, 10, "0"),
+		word(70, 680, 10, "17.6"),
+		word(0, 700, 10, "Länge[m]"),
+		// Baselines within 0.4 em share a row
+		word(70, 698, 10, "Lufttemperatur[°C]"),
+		word(0, 660, 10, "10"),
+		word(70, 660.5, 10, "17.0"),
+		[]Glyph{{X: 0, Y: 640, W: 6, FontSize: 10, Text: ""}},
+	)}
+
+	want := [][]string{
+		{"Länge[m]", "Lufttemperatur[°C]"},
+		{"0", "17.6"},
+		{"10", "17.0"},
+	}
+	lines := page.Lines()
+	var got [][]string
+	for _, l := range lines {
+		got = append(got, cellTexts(l.Cells))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Lines() = %q, want %q", got, want)
+	}
+	if lines[0].Y != 700 {
+		t.Errorf("first line Y = %v, want 700 (top of the row)", lines[0].Y)
+	}
+	if text := lines[0].Text(); text != "Länge[m]  Lufttemperatur[°C]" {
+		t.Errorf("Text() = %q", text)
+	}
+}
+
+func TestPageLayout(t *testing.T) {
+	page := Page{Glyphs: glyphs(
+		word(20, 700, 10, "Länge[m]"),
+		word(98, 700, 10, "Schubkraft[N]"),
+		word(20, 690, 5, "Datum:"),
+		// Cells less than two columns apart are still separated by two spaces
+		word(50, 690, 10, "16.05.2025"),
+		word(44, 680, 10, "10"),
+		word(140, 680, 10, "3"),
+	)}
+
+	want := "Länge[m]     Schubkraft[N]\n" +
+		"Datum:  16.05.2025\n" +
+		"    10              3"
+	if got := page.Layout(); got != want {
+		t.Errorf("Layout() =\n%s\nwant\n%s", got, want)
+	}
+	if got := (Page{}).Layout(); got != "" {
+		t.Errorf("Layout() of an empty page = %q", got)
+	}
+}
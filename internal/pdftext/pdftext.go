@@ -0,0 +1,105 @@
+// Package pdftext extracts the text of PDF protocols with its position on the
+// page and rebuilds table rows and columns from the coordinates, so protocol
+// parsers get a layout like "pdftotext -layout" without external tools.
+package pdftext
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Glyph is one shown character (or ligature) with its position in points,
+// X increasing to the right and Y increasing to the top of the page
+type Glyph struct {
+	X        float64
+	Y        float64
+	W        float64 // advance width
+	FontSize float64
+	Text     string
+}
+
+// Page is the positioned text of one page
+type Page struct {
+	Number int
+	Glyphs []Glyph
+}
+
+// ExtractFile extracts the positioned text of all pages of a PDF file
+func ExtractFile(path string) ([]Page, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %v", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %v", err)
+	}
+	return Extract(f, info.Size())
+}
+
+// Extract extracts the positioned text of all pages of a PDF
+func Extract(r io.ReaderAt, size int64) (pages []Page, err error) {
+	// The PDF library panics on malformed files
+	defer func() {
+		if p := recover(); p != nil {
+			pages, err = nil, fmt.Errorf("failed to read PDF: %v", p)
+		}
+	}()
+
+	reader, err := pdf.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %v", err)
+	}
+	for i := 1; i <= reader.NumPage(); i++ {
+		p := reader.Page(i)
+		if p.V.IsNull() {
+			continue
+		}
+		glyphs, err := readPage(p, i)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, Page{Number: i, Glyphs: glyphs})
+	}
+	return pages, nil
+}
+
+// readPage returns the glyphs of one page. The PDF library panics on
+// malformed content streams; the panic is logged with its stack, so bugs in
+// the interpreter stay visible, and returned as an error naming the page.
+func readPage(p pdf.Page, number int) (glyphs []Glyph, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("pdftext: Panic reading page %d: %v\n%s", number, r, debug.Stack())
+			glyphs, err = nil, fmt.Errorf("failed to read PDF content of page %d: %v", number, r)
+		}
+	}()
+	return pageGlyphs(p), nil
+}
+
+// ExtractText extracts a PDF file as layout text (see Layout)
+func ExtractText(path string) (string, error) {
+	pages, err := ExtractFile(path)
+	if err != nil {
+		return "", err
+	}
+	return Layout(pages), nil
+}
+
+// Layout renders the pages as plain text with one line per table row and the
+// cells of a row placed at the character column of their page position.
+// Pages are separated by form feeds.
+func Layout(pages []Page) string {
+	texts := make([]string, len(pages))
+	for i, p := range pages {
+		texts[i] = p.Layout()
+	}
+	return strings.Join(texts, "\f")
+}
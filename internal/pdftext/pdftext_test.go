@@ -0,0 +1,59 @@
+package pdftext
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// cellSeparator splits a layout line into table cells
+var cellSeparator = regexp.MustCompile(`\s{2,}`)
+
+// TestExtractTextFixtures extracts table PDFs written by gofpdf with a standard
+// font (Helvetica, WinAnsi) and an embedded TrueType font (DejaVu, Identity-H)
+// and compares them with testdata/table.txt, the table as "pdftotext -layout"
+// lays it out. Column positions differ between the two, so the lines are
+// compared by their words and the table rows by their cells.
+func TestExtractTextFixtures(t *testing.T) {
+	data, err := os.ReadFile("testdata/table.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			want = append(want, line)
+		}
+	}
+
+	for _, name := range []string{"table_helvetica.pdf", "table_dejavu.pdf"} {
+		t.Run(name, func(t *testing.T) {
+			text, err := ExtractText("testdata/" + name)
+			if err != nil {
+				t.Fatalf("ExtractText: %v", err)
+			}
+			got := strings.Split(text, "\n")
+			if len(got) != len(want) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(got), len(want), text)
+			}
+			for i := range want {
+				if g, w := strings.Fields(got[i]), strings.Fields(want[i]); strings.Join(g, " ") != strings.Join(w, " ") {
+					t.Errorf("line %d = %q, want %q", i+1, got[i], want[i])
+				}
+			}
+			// The data rows keep their six columns
+			for i := 4; i < len(got); i++ {
+				if cells := cellSeparator.Split(strings.TrimSpace(got[i]), -1); len(cells) != 6 {
+					t.Errorf("line %d has %d cells, want 6: %q", i+1, len(cells), got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExtractFileMissing(t *testing.T) {
+	if _, err := ExtractFile("testdata/missing.pdf"); err == nil {
+		t.Error("ExtractFile returned no error for a missing file")
+	}
+}
@@ -0,0 +1,176 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// CatalogCable is a cable product with its technical specifications
+type CatalogCable struct {
+	ID            int     `json:"id" db:"id"`
+	Manufacturer  string  `json:"manufacturer" db:"manufacturer"`       // Prysmian
+	Designation   string  `json:"designation" db:"designation"`         // A-D 2Y 1x6
+	DiameterMM    float64 `json:"diameter_mm" db:"diameter_mm"`         // 2.5
+	WeightKgPerM  float64 `json:"weight_kg_per_m" db:"weight_kg_per_m"` // 0.0055
+	FiberCount    int     `json:"fiber_count" db:"fiber_count"`         // 6
+	MaxPushForceN float64 `json:"max_push_force_n" db:"max_push_force_n"`
+}
+
+// CatalogDuct is a duct (micro-duct bundle) product with its dimensions
+type CatalogDuct struct {
+	ID              int     `json:"id" db:"id"`
+	Manufacturer    string  `json:"manufacturer" db:"manufacturer"`           // Gabocom
+	Bundle          string  `json:"bundle" db:"bundle"`                       // SNRVe 22x7x1,5
+	PipeType        string  `json:"pipe_type" db:"pipe_type"`                 // SNR 7x1,5
+	OuterDiameterMM float64 `json:"outer_diameter_mm" db:"outer_diameter_mm"` // 7
+	InnerDiameterMM float64 `json:"inner_diameter_mm" db:"inner_diameter_mm"` // 4
+	InnerWall       string  `json:"inner_wall" db:"inner_wall"`               // Gerieft
+}
+
+// CatalogSeed is the content of the catalog seed file
+type CatalogSeed struct {
+	Cables []CatalogCable `json:"cables"`
+	Ducts  []CatalogDuct  `json:"ducts"`
+}
+
+// ParseCatalogSeed reads and validates a catalog seed file
+func ParseCatalogSeed(r io.Reader) (*CatalogSeed, error) {
+	var seed CatalogSeed
+	if err := json.NewDecoder(r).Decode(&seed); err != nil {
+		return nil, fmt.Errorf("invalid catalog seed: %v", err)
+	}
+	for i, c := range seed.Cables {
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("cable %d: %v", i+1, err)
+		}
+	}
+	for i, d := range seed.Ducts {
+		seed.Ducts[i] = d.WithDimensions()
+		if err := seed.Ducts[i].Validate(); err != nil {
+			return nil, fmt.Errorf("duct %d: %v", i+1, err)
+		}
+	}
+	return &seed, nil
+}
+
+// Validate checks the required fields of a catalog cable
+func (c CatalogCable) Validate() error {
+	if strings.TrimSpace(c.Designation) == "" {
+		return fmt.Errorf("designation is required")
+	}
+	if c.DiameterMM <= 0 {
+		return fmt.Errorf("diameter must be positive")
+	}
+	return nil
+}
+
+// Validate checks the required fields of a catalog duct
+func (d CatalogDuct) Validate() error {
+	if strings.TrimSpace(d.Bundle) == "" && strings.TrimSpace(d.PipeType) == "" {
+		return fmt.Errorf("bundle or pipe type is required")
+	}
+	if d.InnerDiameterMM <= 0 || d.OuterDiameterMM <= d.InnerDiameterMM {
+		return fmt.Errorf("outer diameter must be larger than a positive inner diameter")
+	}
+	return nil
+}
+
+// WithDimensions fills missing diameters from the pipe type or bundle (e.g. "7x1,5")
+func (d CatalogDuct) WithDimensions() CatalogDuct {
+	if d.OuterDiameterMM > 0 && d.InnerDiameterMM > 0 {
+		return d
+	}
+	outer, wall, ok := ParsePipeDimensions(d.PipeType)
+	if !ok {
+		outer, wall, ok = ParsePipeDimensions(d.Bundle)
+	}
+	if ok {
+		d.OuterDiameterMM = outer
+		d.InnerDiameterMM = outer - 2*wall
+	}
+	return d
+}
+
+// normalizeDesignation reduces a product designation to comparable form
+// ("A-D 2Y 1x6" → "ad2y1x6", "SNRVe 22x7x1,5" → "snrve22x7x1.5")
+func normalizeDesignation(s string) string {
+	s = strings.ToLower(strings.ReplaceAll(s, ",", "."))
+	return strings.NewReplacer(" ", "", "-", "", "/", "", "×", "x").Replace(s)
+}
+
+// sameManufacturer compares manufacturer names loosely ("Prysmian" matches "Prysmian Group")
+func sameManufacturer(a, b string) bool {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// MatchCable finds the catalog cable for a parsed designation. Candidates
+// with the same designation are ranked by manufacturer and closest diameter.
+func MatchCable(cables []CatalogCable, manufacturer, designation string, diameterMM float64) *CatalogCable {
+	key := normalizeDesignation(designation)
+	if key == "" {
+		return nil
+	}
+	var best *CatalogCable
+	bestScore := math.Inf(-1)
+	for i := range cables {
+		c := &cables[i]
+		if normalizeDesignation(c.Designation) != key {
+			continue
+		}
+		score := 0.0
+		if sameManufacturer(c.Manufacturer, manufacturer) {
+			score += 10
+		}
+		if diameterMM > 0 {
+			score -= math.Abs(c.DiameterMM - diameterMM)
+		}
+		if score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best
+}
+
+// MatchDuct finds the catalog duct for a parsed pipe bundle and pipe type.
+// The bundle designation is matched first; otherwise a duct with the same
+// tube dimensions as the pipe type (e.g. "SNR 7x1,5") is used, preferring
+// the same manufacturer.
+func MatchDuct(ducts []CatalogDuct, manufacturer, bundle, pipeType string) *CatalogDuct {
+	if key := normalizeDesignation(bundle); key != "" {
+		var match *CatalogDuct
+		for i := range ducts {
+			if normalizeDesignation(ducts[i].Bundle) != key {
+				continue
+			}
+			if match == nil || sameManufacturer(ducts[i].Manufacturer, manufacturer) {
+				match = &ducts[i]
+			}
+		}
+		if match != nil {
+			return match
+		}
+	}
+
+	outer, wall, ok := ParsePipeDimensions(pipeType)
+	if !ok {
+		return nil
+	}
+	var match *CatalogDuct
+	for i := range ducts {
+		d := &ducts[i]
+		if math.Abs(d.OuterDiameterMM-outer) > 0.05 || math.Abs(d.OuterDiameterMM-d.InnerDiameterMM-2*wall) > 0.1 {
+			continue
+		}
+		if match == nil || (sameManufacturer(d.Manufacturer, manufacturer) && !sameManufacturer(match.Manufacturer, manufacturer)) {
+			match = d
+		}
+	}
+	return match
+}
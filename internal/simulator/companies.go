@@ -0,0 +1,143 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Company roles in a protocol
+const (
+	CompanyRoleCompany         = "company"          // client the section is built for ("Firma")
+	CompanyRoleServiceProvider = "service_provider" // subcontractor doing the blowing
+	CompanyRoleManufacturer    = "manufacturer"     // pipe or cable supplier
+)
+
+// KnownCompany is a company name the parsers normalize to, with the spellings
+// found in protocols
+type KnownCompany struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+	Role    string   `json:"role"`
+}
+
+// KnownCompaniesFile is the content of the known companies file
+type KnownCompaniesFile struct {
+	Companies []KnownCompany `json:"companies"`
+}
+
+// defaultKnownCompanies are used until a known companies file is loaded
+var defaultKnownCompanies = []KnownCompany{
+	{Name: "M.A.X. Bauservice", Aliases: []string{"M.A.X. Bauservice GmbH", "MAX Bauservice"}, Role: CompanyRoleServiceProvider},
+	{Name: "Wolken-ASM GmbH", Aliases: []string{"Wolken-ASM"}, Role: CompanyRoleCompany},
+	{Name: "Gabocom", Role: CompanyRoleManufacturer},
+	{Name: "Prysmian", Aliases: []string{"Prysmian Group"}, Role: CompanyRoleManufacturer},
+}
+
+var knownCompanies = struct {
+	sync.RWMutex
+	companies []KnownCompany
+}{companies: defaultKnownCompanies}
+
+// ParseKnownCompanies reads and validates a known companies file
+func ParseKnownCompanies(r io.Reader) ([]KnownCompany, error) {
+	var file KnownCompaniesFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse known companies: %v", err)
+	}
+	for i, c := range file.Companies {
+		if strings.TrimSpace(c.Name) == "" {
+			return nil, fmt.Errorf("company %d: name is required", i+1)
+		}
+		switch c.Role {
+		case CompanyRoleCompany, CompanyRoleServiceProvider, CompanyRoleManufacturer:
+		default:
+			return nil, fmt.Errorf("company %q: unknown role %q", c.Name, c.Role)
+		}
+	}
+	return file.Companies, nil
+}
+
+// LoadKnownCompanies replaces the known companies with the content of a file
+func LoadKnownCompanies(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open known companies: %v", err)
+	}
+	defer f.Close()
+	companies, err := ParseKnownCompanies(f)
+	if err != nil {
+		return err
+	}
+	SetKnownCompanies(companies)
+	return nil
+}
+
+// SetKnownCompanies replaces the known companies
+func SetKnownCompanies(companies []KnownCompany) {
+	knownCompanies.Lock()
+	defer knownCompanies.Unlock()
+	knownCompanies.companies = append([]KnownCompany(nil), companies...)
+}
+
+// KnownCompanies returns the known companies
+func KnownCompanies() []KnownCompany {
+	knownCompanies.RLock()
+	defer knownCompanies.RUnlock()
+	return append([]KnownCompany(nil), knownCompanies.companies...)
+}
+
+// companyKey reduces a company name to comparable form ("Wolken-ASM  GMBH" → "wolken-asm gmbh")
+func companyKey(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// spellings returns the comparable name and aliases of a known company
+func (c KnownCompany) spellings() []string {
+	keys := []string{companyKey(c.Name)}
+	for _, a := range c.Aliases {
+		if key := companyKey(a); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// NormalizeCompany returns the known name of a company read from a protocol,
+// or the trimmed name itself if it is not known. A known spelling matches
+// when the name contains it ("Wolken-ASM GMBH" → "Wolken-ASM GmbH").
+func NormalizeCompany(name string) string {
+	name = strings.TrimSpace(name)
+	key := companyKey(name)
+	if key == "" {
+		return name
+	}
+	for _, c := range KnownCompanies() {
+		for _, spelling := range c.spellings() {
+			if strings.Contains(key, spelling) {
+				return c.Name
+			}
+		}
+	}
+	return name
+}
+
+// FindKnownCompany returns the known company of the given role mentioned in
+// a line of unlabeled text, for headers that print company names without a label
+func FindKnownCompany(line, role string) (string, bool) {
+	key := companyKey(line)
+	for _, c := range KnownCompanies() {
+		if c.Role != role {
+			continue
+		}
+		for _, spelling := range c.spellings() {
+			if strings.Contains(key, spelling) {
+				return c.Name, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,221 @@
+package simulator
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// withKnownCompanies replaces the known companies for the duration of a test
+func withKnownCompanies(t *testing.T, companies []KnownCompany) {
+	t.Helper()
+	previous := KnownCompanies()
+	SetKnownCompanies(companies)
+	t.Cleanup(func() { SetKnownCompanies(previous) })
+}
+
+func TestNormalizeCompany(t *testing.T) {
+	withKnownCompanies(t, defaultKnownCompanies)
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Wolken-ASM", "Wolken-ASM GmbH"},
+		{"Wolken-ASM  GMBH", "Wolken-ASM GmbH"},
+		{"MAX Bauservice", "M.A.X. Bauservice"},
+		{"M.A.X. Bauservice GmbH", "M.A.X. Bauservice"},
+		{"Prysmian Group", "Prysmian"},
+		{"  gabocom GmbH & Co. KG ", "Gabocom"},
+		// Unknown companies are kept as printed, only trimmed
+		{"  Tiefbau Nord GmbH ", "Tiefbau Nord GmbH"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeCompany(tt.name); got != tt.want {
+			t.Errorf("NormalizeCompany(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFindKnownCompany(t *testing.T) {
+	withKnownCompanies(t, defaultKnownCompanies)
+	tests := []struct {
+		line string
+		role string
+		want string
+		ok   bool
+	}{
+		{"M.A.X. Bauservice", CompanyRoleServiceProvider, "M.A.X. Bauservice", true},
+		{"Auftraggeber: Wolken-ASM", CompanyRoleCompany, "Wolken-ASM GmbH", true},
+		// A known name of another role does not count
+		{"Wolken-ASM", CompanyRoleServiceProvider, "", false},
+		{"Tiefbau Nord GmbH", CompanyRoleCompany, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := FindKnownCompany(tt.line, tt.role)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FindKnownCompany(%q, %s) = %q, %v, want %q, %v", tt.line, tt.role, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseKnownCompanies(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		count   int
+		wantErr string
+	}{
+		{"valid", `{"companies": [{"name": "Tiefbau Nord", "aliases": ["TBN"], "role": "service_provider"}]}`, 1, ""},
+		{"empty", `{"companies": []}`, 0, ""},
+		{"no name", `{"companies": [{"name": " ", "role": "company"}]}`, 0, "name is required"},
+		{"unknown role", `{"companies": [{"name": "Tiefbau Nord", "role": "client"}]}`, 0, "unknown role"},
+		{"not JSON", `companies:`, 0, "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			companies, err := ParseKnownCompanies(strings.NewReader(tt.json))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || len(companies) != tt.count {
+				t.Errorf("ParseKnownCompanies() = %d companies, %v, want %d", len(companies), err, tt.count)
+			}
+		})
+	}
+
+	// The shipped file is valid and matches the built-in defaults
+	f, err := os.Open("../../schema/known_companies.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	companies, err := ParseKnownCompanies(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(companies) != len(defaultKnownCompanies) {
+		t.Errorf("schema/known_companies.json has %d companies, defaults %d", len(companies), len(defaultKnownCompanies))
+	}
+}
+
+func TestFremcoCompanies(t *testing.T) {
+	withKnownCompanies(t, append([]KnownCompany{
+		{Name: "Tiefbau Nord GmbH", Aliases: []string{"TBN"}, Role: CompanyRoleServiceProvider},
+	}, defaultKnownCompanies...))
+
+	tests := []struct {
+		name     string
+		lines    []string
+		company  string
+		provider string
+	}{
+		{
+			name:     "provider printed under the company",
+			lines:    []string{"Firma Wolken-ASM GMBH", "Tiefbau Nord GmbH", "Bauvorhaben: Am Bahnhof"},
+			company:  "Wolken-ASM GmbH",
+			provider: "Tiefbau Nord GmbH",
+		},
+		{
+			name:     "labeled provider",
+			lines:    []string{"Firma Glasfaser Süd", "Nachunternehmer: TBN"},
+			company:  "Glasfaser Süd",
+			provider: "Tiefbau Nord GmbH",
+		},
+		{
+			name:    "no provider, next line is a label",
+			lines:   []string{"Firma Glasfaser Süd", "Bauvorhaben: Am Bahnhof"},
+			company: "Glasfaser Süd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietLog(t)
+			info := extractFremcoProtocolInfo(tt.lines)
+			if info.Company != tt.company || info.ServiceProvider != tt.provider {
+				t.Errorf("company %q, provider %q, want %q, %q", info.Company, info.ServiceProvider, tt.company, tt.provider)
+			}
+		})
+	}
+}
+
+func TestFremcoManufacturers(t *testing.T) {
+	withKnownCompanies(t, defaultKnownCompanies)
+	tests := []struct {
+		name        string
+		lines       []string
+		pipe, cable string
+	}{
+		{
+			name:  "sections",
+			lines: []string{"Rohr", "Hersteller: gabocom", "Kabel", "Hersteller: Prysmian Group"},
+			pipe:  "Gabocom", cable: "Prysmian",
+		},
+		{
+			name:  "two columns on one line",
+			lines: []string{"Hersteller: Rehau  Hersteller: Corning"},
+			pipe:  "Rehau", cable: "Corning",
+		},
+		{
+			name:  "cable section only",
+			lines: []string{"Kabel", "Hersteller: Corning"},
+			cable: "Corning",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eq := extractFremcoEquipment(tt.lines, nil)
+			if eq.Pipe.Manufacturer != tt.pipe || eq.Cable.Manufacturer != tt.cable {
+				t.Errorf("pipe %q, cable %q, want %q, %q", eq.Pipe.Manufacturer, eq.Cable.Manufacturer, tt.pipe, tt.cable)
+			}
+		})
+	}
+}
+
+func TestJettingHeaderCompanies(t *testing.T) {
+	withKnownCompanies(t, defaultKnownCompanies)
+	tests := []struct {
+		name     string
+		lines    []string
+		company  string
+		provider string
+	}{
+		{
+			name:     "provider and company above the title",
+			lines:    []string{"MAX Bauservice", "Glasfaser Nord GmbH", "Jetting Protokoll", "Datum: 16.05.2025"},
+			company:  "Glasfaser Nord GmbH",
+			provider: "M.A.X. Bauservice",
+		},
+		{
+			name:    "a single unknown name is the company",
+			lines:   []string{"Glasfaser Nord GmbH", "Jetting Protokoll"},
+			company: "Glasfaser Nord GmbH",
+		},
+		{
+			name:     "a single known service provider",
+			lines:    []string{"M.A.X. Bauservice GmbH", "Jetting Protokoll"},
+			provider: "M.A.X. Bauservice",
+		},
+		{
+			name:    "labeled form values win",
+			lines:   []string{"MAX Bauservice", "Glasfaser Nord GmbH", "Jetting Protokoll", "Firma  Wolken-ASM  Dienstleister  Tiefbau Nord"},
+			company: "Wolken-ASM GmbH", provider: "Tiefbau Nord",
+		},
+		{
+			name:  "no title, no header names",
+			lines: []string{"Glasfaser Nord GmbH", "Datum: 16.05.2025"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietLog(t)
+			info := extractJettingProtocolInfo(tt.lines, jettingFormValues(tt.lines))
+			if info.Company != tt.company || info.ServiceProvider != tt.provider {
+				t.Errorf("company %q, provider %q, want %q, %q", info.Company, info.ServiceProvider, tt.company, tt.provider)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Thresholds for detecting where a recorded run left the predicted curve
+const (
+	divergenceRelTolerance = 0.25 // relative speed deviation
+	divergenceMinDeltaMMin = 5.0  // minimum absolute speed deviation in m/min
+	divergenceMinSamples   = 5    // consecutive samples that must deviate
+)
+
+// Assessments of a recorded run compared to its prediction
+const (
+	AssessmentAsPredicted = "as_predicted" // the run followed the prediction
+	AssessmentDuct        = "duct"         // slower and more force than predicted: duct/route harder than planned
+	AssessmentCrew        = "crew"         // slower with less force than predicted: device not driven at capacity
+	AssessmentFaster      = "faster"       // faster than predicted
+)
+
+// ComparisonPoint pairs a measured sample with the prediction at the same length
+type ComparisonPoint struct {
+	LengthM            float64 `json:"length_m"`
+	ActualSpeedMMin    float64 `json:"actual_speed_m_min"`
+	PredictedSpeedMMin float64 `json:"predicted_speed_m_min"`
+	ActualForceN       float64 `json:"actual_force_n"`
+	PredictedForceN    float64 `json:"predicted_force_n"`
+}
+
+// SimulationComparison contains the predicted and the recorded curve of a run plus deviation metrics
+type SimulationComparison struct {
+	Predicted         *SimulationResult `json:"predicted"`
+	Points            []ComparisonPoint `json:"points"`
+	RMSSpeedErrorMMin float64           `json:"rms_speed_error_m_min"`
+	RMSForceErrorN    float64           `json:"rms_force_error_n"`
+	DivergenceLengthM *float64          `json:"divergence_length_m"` // first length where the run left the prediction (nil = never)
+	Assessment        string            `json:"assessment"`
+}
+
+// CompareSimulation simulates the recorded setup and compares the prediction
+// with the measured samples. Unset pressure, section length and maximum speed
+// are taken from the samples (median pressure, longest length, fastest speed),
+// so the prediction covers the same meter range at the crew's speed setting.
+func CompareSimulation(in SimulationInput, samples []FitSample) (*SimulationComparison, error) {
+	var measured []FitSample
+	for _, s := range samples {
+		if s.LengthM > 0 {
+			measured = append(measured, s)
+		}
+	}
+	if len(measured) == 0 {
+		return nil, fmt.Errorf("protocol has no measured lengths to compare")
+	}
+	sort.SliceStable(measured, func(i, j int) bool { return measured[i].LengthM < measured[j].LengthM })
+
+	if in.PressureBar <= 0 {
+		in.PressureBar = MedianPressure(measured)
+	}
+	if len(in.Duct.Segments) == 0 && in.Duct.LengthM <= 0 {
+		in.Duct.LengthM = measured[len(measured)-1].LengthM
+	}
+	if in.MaxSpeedMMin <= 0 {
+		for _, s := range measured {
+			in.MaxSpeedMMin = math.Max(in.MaxSpeedMMin, s.SpeedMMin)
+		}
+	}
+	predicted, err := Simulate(in)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &SimulationComparison{
+		Predicted:  predicted,
+		Points:     make([]ComparisonPoint, 0, len(measured)),
+		Assessment: AssessmentAsPredicted,
+	}
+	var speedSum, forceSum float64
+	divergeStart, divergeCount := -1, 0
+	for i, s := range measured {
+		speed, force := predictedAt(predicted, s.LengthM, in.PushForceN)
+		comparison.Points = append(comparison.Points, ComparisonPoint{
+			LengthM:            s.LengthM,
+			ActualSpeedMMin:    s.SpeedMMin,
+			PredictedSpeedMMin: round(speed, 1),
+			ActualForceN:       round(s.ForceN, 1),
+			PredictedForceN:    round(force, 1),
+		})
+		speedSum += (s.SpeedMMin - speed) * (s.SpeedMMin - speed)
+		forceSum += (s.ForceN - force) * (s.ForceN - force)
+
+		// The run diverged once enough consecutive samples leave the tolerance band
+		if comparison.DivergenceLengthM != nil {
+			continue
+		}
+		tolerance := math.Max(divergenceRelTolerance*speed, divergenceMinDeltaMMin)
+		if math.Abs(s.SpeedMMin-speed) > tolerance {
+			if divergeCount == 0 {
+				divergeStart = i
+			}
+			divergeCount++
+			if divergeCount >= divergenceMinSamples {
+				length := measured[divergeStart].LengthM
+				comparison.DivergenceLengthM = &length
+				comparison.Assessment = assessDivergence(comparison.Points[divergeStart:])
+			}
+		} else {
+			divergeCount = 0
+		}
+	}
+	comparison.RMSSpeedErrorMMin = round(math.Sqrt(speedSum/float64(len(measured))), 2)
+	comparison.RMSForceErrorN = round(math.Sqrt(forceSum/float64(len(measured))), 2)
+	return comparison, nil
+}
+
+// predictedAt interpolates the predicted speed and force at an installed length.
+// Beyond the predicted stall point the cable stands still at full push force.
+func predictedAt(result *SimulationResult, length, pushForceN float64) (speed, force float64) {
+	points := result.JettingDataPoints
+	if len(points) == 0 || length > result.MaxDistanceM {
+		return 0, pushForceN
+	}
+	i := sort.Search(len(points), func(i int) bool { return points[i].LengthM >= length })
+	if i == 0 {
+		return points[0].SpeedMMin, points[0].ForceN
+	}
+	if i == len(points) {
+		last := points[len(points)-1]
+		return last.SpeedMMin, last.ForceN
+	}
+	a, b := points[i-1], points[i]
+	t := (length - a.LengthM) / (b.LengthM - a.LengthM)
+	return a.SpeedMMin + t*(b.SpeedMMin-a.SpeedMMin), a.ForceN + t*(b.ForceN-a.ForceN)
+}
+
+// assessDivergence attributes a deviation from the prediction to the duct or the crew
+func assessDivergence(points []ComparisonPoint) string {
+	var speedDelta, forceDelta float64
+	for _, p := range points {
+		speedDelta += p.ActualSpeedMMin - p.PredictedSpeedMMin
+		forceDelta += p.ActualForceN - p.PredictedForceN
+	}
+	switch {
+	case speedDelta > 0:
+		return AssessmentFaster
+	case forceDelta >= 0:
+		return AssessmentDuct
+	default:
+		return AssessmentCrew
+	}
+}
@@ -0,0 +1,290 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Constants of the airflow model
+const (
+	gasConstantAir      = 287.05  // J/(kg·K)
+	airViscosity        = 1.81e-5 // Pa·s at 20°C
+	airTemperatureK     = 293.15  // isothermal flow at 20°C
+	laminarReynolds     = 2300.0
+	sagThreshold        = 0.10 // pressure drop relative to the reference pressure that counts as sag
+	minSagLengthM       = 20.0 // shorter dips are treated as noise
+	earlySagShare       = 0.15 // sag starting within this share of the run counts as a launch sag
+	sustainedSagShare   = 0.25 // sag covering this share of the run is suspicious on its own
+	referencePercentile = 90.0
+)
+
+// Airflow check results
+const (
+	AirflowOK           = "ok"
+	AirflowMarginal     = "marginal"     // enough air once the cable is in, pressure sags at launch
+	AirflowInsufficient = "insufficient" // the pressure cannot be held at all
+	AirflowUnknown      = "unknown"
+)
+
+// CompressorSpec contains the data sheet values of a compressor model
+type CompressorSpec struct {
+	Name                 string  `json:"name"`
+	Manufacturer         string  `json:"manufacturer"`
+	FreeAirDeliveryM3Min float64 `json:"free_air_delivery_m3_min"` // at max pressure
+	MaxPressureBar       float64 `json:"max_pressure_bar"`         // gauge
+}
+
+// compressorModels maps normalized compressor names, as they appear in the
+// protocols (e.g. "Kaiser m17a", "jetair141000"), to their data sheet values
+var compressorModels = map[string]CompressorSpec{
+	"m17":          {Name: "Kaiser Mobilair M17", Manufacturer: "Kaiser", FreeAirDeliveryM3Min: 1.5, MaxPressureBar: 7},
+	"jetair141000": {Name: "JetAir 14/1000", Manufacturer: "JetAir", FreeAirDeliveryM3Min: 1.0, MaxPressureBar: 14},
+}
+
+// normalizeCompressorName reduces a compressor name to comparable form ("Kaiser M 17a" → "kaiserm17a")
+func normalizeCompressorName(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", "/", "", ".", "").Replace(strings.ToLower(s))
+}
+
+// LookupCompressor returns the data sheet values of a compressor model, or nil if it is unknown
+func LookupCompressor(model string) *CompressorSpec {
+	m := normalizeCompressorName(model)
+	if m == "" {
+		return nil
+	}
+	for key, spec := range compressorModels {
+		if strings.Contains(m, key) {
+			spec := spec
+			return &spec
+		}
+	}
+	return nil
+}
+
+// CompressorModels returns all known compressor models sorted by name
+func CompressorModels() []CompressorSpec {
+	models := make([]CompressorSpec, 0, len(compressorModels))
+	for _, spec := range compressorModels {
+		models = append(models, spec)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return models
+}
+
+// PressureSag is a stretch of the run where the pressure dropped below the reference pressure
+type PressureSag struct {
+	StartLengthM   float64 `json:"start_length_m"`
+	EndLengthM     float64 `json:"end_length_m"`
+	MinPressureBar float64 `json:"min_pressure_bar"`
+	DropPercent    float64 `json:"drop_percent"` // largest drop relative to the reference pressure
+}
+
+// PressureSagAnalysis describes the pressure sags of a recorded run
+type PressureSagAnalysis struct {
+	ReferencePressureBar float64       `json:"reference_pressure_bar"` // pressure the crew was able to hold (P90)
+	Sags                 []PressureSag `json:"sags"`
+	SagShare             float64       `json:"sag_share"` // share of the blown length spent in sags
+	Suspected            bool          `json:"suspected"` // sag pattern consistent with an undersized compressor
+	Reason               string        `json:"reason,omitempty"`
+}
+
+// CompressorCheck contains the airflow adequacy of a compressor for a section
+type CompressorCheck struct {
+	Model                  string               `json:"model"`
+	Compressor             *CompressorSpec      `json:"compressor"` // nil = unknown model
+	DuctInnerDiameterMM    float64              `json:"duct_inner_diameter_mm"`
+	CableDiameterMM        float64              `json:"cable_diameter_mm"`
+	SectionLengthM         float64              `json:"section_length_m"`
+	PressureBar            float64              `json:"pressure_bar"`
+	PeakAirflowM3Min       float64              `json:"peak_airflow_m3_min"`      // empty duct, at launch
+	EndAirflowM3Min        float64              `json:"end_airflow_m3_min"`       // cable over the whole section
+	SustainablePressureBar float64              `json:"sustainable_pressure_bar"` // highest pressure the compressor holds at launch
+	Status                 string               `json:"status"`
+	Warnings               []string             `json:"warnings"`
+	PressureSag            *PressureSagAnalysis `json:"pressure_sag"`
+}
+
+// DuctAirflow returns the free air consumption in m³/min of a duct (with a
+// cable of cableMM over its whole length, 0 = empty) at the given pressure.
+// Isothermal flow with negligible acceleration gives
+// p0² - pa² = f · L/Dh · G² · R·T for the mass flux G, with the Darcy friction
+// factor f from Hagen-Poiseuille (laminar) or Blasius (turbulent).
+func DuctAirflow(ductMM, cableMM, pressureBar, lengthM float64) float64 {
+	if pressureBar <= 0 || lengthM <= 0 || cableMM >= ductMM {
+		return 0
+	}
+	D, d := ductMM/1000, cableMM/1000
+	dh := D - d
+	area := math.Pi / 4 * (D*D - d*d)
+	p0 := (pressureBar + atmosphericPressure) * pascalPerBar
+	pa := atmosphericPressure * pascalPerBar
+	k := (p0*p0 - pa*pa) * dh / (lengthM * gasConstantAir * airTemperatureK)
+
+	// Laminar: f = 64/Re with Re = G·Dh/μ
+	g := k * dh / (64 * airViscosity)
+	if g*dh/airViscosity > laminarReynolds {
+		// Turbulent: f = 0.316·Re^-0.25
+		g = math.Pow(k/(0.316*math.Pow(dh/airViscosity, -0.25)), 1/1.75)
+	}
+	densityFreeAir := pa / (gasConstantAir * airTemperatureK)
+	return g * area / densityFreeAir * 60
+}
+
+// CheckCompressor evaluates whether a compressor can sustain the airflow of
+// a section. The air consumption is highest at launch, when the duct is still
+// empty, and lowest when the cable fills the whole section. The section
+// length defaults to the longest measured length and the pressure to the
+// median measured pressure; measured samples are also checked for sags.
+func CheckCompressor(model string, in SimulationInput, samples []FitSample) CompressorCheck {
+	check := CompressorCheck{
+		Model:               model,
+		Compressor:          LookupCompressor(model),
+		DuctInnerDiameterMM: in.Duct.InnerDiameterMM,
+		CableDiameterMM:     in.Cable.DiameterMM,
+		PressureBar:         in.PressureBar,
+		Status:              AirflowUnknown,
+		Warnings:            []string{},
+	}
+	if len(in.Duct.Segments) > 0 || in.Duct.LengthM > 0 {
+		check.SectionLengthM = in.SectionLength()
+	}
+	for _, s := range samples {
+		check.SectionLengthM = math.Max(check.SectionLengthM, s.LengthM)
+	}
+	if check.PressureBar <= 0 {
+		check.PressureBar = MedianPressure(samples)
+	}
+	if len(samples) > 0 {
+		check.PressureSag = AnalyzePressureSag(samples)
+	}
+
+	if check.Compressor == nil {
+		check.Warnings = append(check.Warnings, fmt.Sprintf("Compressor model %q unknown, airflow not checked", model))
+		return check.withSagWarning()
+	}
+	if check.DuctInnerDiameterMM <= 0 || check.SectionLengthM <= 0 || check.PressureBar <= 0 {
+		check.Warnings = append(check.Warnings, "Duct diameter, section length or pressure unknown, airflow not checked")
+		return check.withSagWarning()
+	}
+
+	check.PeakAirflowM3Min = round(DuctAirflow(check.DuctInnerDiameterMM, 0, check.PressureBar, check.SectionLengthM), 3)
+	check.EndAirflowM3Min = round(DuctAirflow(check.DuctInnerDiameterMM, check.CableDiameterMM, check.PressureBar, check.SectionLengthM), 3)
+	check.SustainablePressureBar = round(sustainablePressure(check.Compressor, check.DuctInnerDiameterMM, check.SectionLengthM), 1)
+
+	fad := check.Compressor.FreeAirDeliveryM3Min
+	switch {
+	case fad < check.EndAirflowM3Min:
+		check.Status = AirflowInsufficient
+		check.Warnings = append(check.Warnings, fmt.Sprintf("%s delivers %.2f m³/min, the section needs %.2f m³/min even with the cable installed",
+			check.Compressor.Name, fad, check.EndAirflowM3Min))
+	case fad < check.PeakAirflowM3Min:
+		check.Status = AirflowMarginal
+		check.Warnings = append(check.Warnings, fmt.Sprintf("%s delivers %.2f m³/min, the empty duct needs %.2f m³/min; pressure sags at launch (holds %.1f bar)",
+			check.Compressor.Name, fad, check.PeakAirflowM3Min, check.SustainablePressureBar))
+	default:
+		check.Status = AirflowOK
+	}
+	// A pressure above the rating points to a booster or a wrongly recorded compressor
+	if check.PressureBar > check.Compressor.MaxPressureBar {
+		check.Warnings = append(check.Warnings, fmt.Sprintf("%s is rated for %.1f bar, %.1f bar were recorded",
+			check.Compressor.Name, check.Compressor.MaxPressureBar, check.PressureBar))
+	}
+	return check.withSagWarning()
+}
+
+// withSagWarning adds a warning if the measured pressure sags like an undersized compressor
+func (c CompressorCheck) withSagWarning() CompressorCheck {
+	if c.PressureSag != nil && c.PressureSag.Suspected {
+		c.Warnings = append(c.Warnings, "Measured pressure sags: "+c.PressureSag.Reason)
+	}
+	return c
+}
+
+// sustainablePressure returns the highest pressure up to the rated pressure
+// at which the compressor still supplies the empty duct
+func sustainablePressure(spec *CompressorSpec, ductMM, lengthM float64) float64 {
+	if DuctAirflow(ductMM, 0, spec.MaxPressureBar, lengthM) <= spec.FreeAirDeliveryM3Min {
+		return spec.MaxPressureBar
+	}
+	low, high := 0.0, spec.MaxPressureBar
+	for i := 0; i < 40; i++ {
+		mid := (low + high) / 2
+		if DuctAirflow(ductMM, 0, mid, lengthM) <= spec.FreeAirDeliveryM3Min {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
+
+// AnalyzePressureSag finds stretches of a recorded run where the pressure
+// dropped noticeably below the pressure the crew otherwise held. Stops are
+// ignored since the air is usually throttled there. An undersized compressor
+// shows up as a sag right after launch that recovers once the cable fills
+// the duct, or as a sag over a large part of the run when the receiver drains.
+func AnalyzePressureSag(samples []FitSample) *PressureSagAnalysis {
+	var moving []FitSample
+	var pressures []float64
+	for _, s := range samples {
+		if s.SpeedMMin > 0 && s.PressureBar > 0 {
+			moving = append(moving, s)
+			pressures = append(pressures, s.PressureBar)
+		}
+	}
+	analysis := &PressureSagAnalysis{Sags: []PressureSag{}}
+	if len(moving) < 2 {
+		return analysis
+	}
+	reference := percentile(pressures, referencePercentile)
+	analysis.ReferencePressureBar = round(reference, 2)
+	limit := reference * (1 - sagThreshold)
+
+	var current *PressureSag
+	closeSag := func(end float64) {
+		if current != nil && end-current.StartLengthM >= minSagLengthM {
+			current.EndLengthM = end
+			analysis.Sags = append(analysis.Sags, *current)
+		}
+		current = nil
+	}
+	for _, s := range moving {
+		if s.PressureBar >= limit {
+			if current != nil {
+				closeSag(s.LengthM)
+			}
+			continue
+		}
+		if current == nil {
+			current = &PressureSag{StartLengthM: s.LengthM, MinPressureBar: s.PressureBar}
+		}
+		current.MinPressureBar = math.Min(current.MinPressureBar, s.PressureBar)
+		current.DropPercent = round((1-current.MinPressureBar/reference)*100, 1)
+	}
+	runStart, runEnd := moving[0].LengthM, moving[len(moving)-1].LengthM
+	closeSag(runEnd)
+
+	runLength := runEnd - runStart
+	if runLength <= 0 || len(analysis.Sags) == 0 {
+		return analysis
+	}
+	sagLength := 0.0
+	for _, sag := range analysis.Sags {
+		sagLength += sag.EndLengthM - sag.StartLengthM
+	}
+	analysis.SagShare = round(sagLength/runLength, 3)
+
+	first := analysis.Sags[0]
+	switch {
+	case first.StartLengthM-runStart <= earlySagShare*runLength && first.EndLengthM < runEnd:
+		analysis.Suspected = true
+		analysis.Reason = fmt.Sprintf("%.0f%% below %.1f bar from launch until %.0fm, recovering as the duct filled",
+			first.DropPercent, reference, first.EndLengthM)
+	case analysis.SagShare >= sustainedSagShare:
+		analysis.Suspected = true
+		analysis.Reason = fmt.Sprintf("below %.1f bar over %.0f%% of the run", reference, analysis.SagShare*100)
+	}
+	return analysis
+}
@@ -0,0 +1,112 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Diagnostic severities
+const (
+	SeverityWarning = "warning" // optional value unreadable, the protocol is complete without it
+	SeverityError   = "error"   // required value unreadable, e.g. a cell of a data point
+)
+
+// Diagnostic is a value the parser could not read
+type Diagnostic struct {
+	Severity string `json:"severity" db:"severity"`
+	Line     int    `json:"line" db:"line_number"` // line of the normalized text, 0 if unknown
+	Field    string `json:"field" db:"field"`
+	Raw      string `json:"raw" db:"raw_text"`
+	Reason   string `json:"reason" db:"reason"`
+}
+
+// String formats the diagnostic for logs and error messages
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("line %d, %s %q: %s", d.Line, d.Field, d.Raw, d.Reason)
+}
+
+// ParseResult is a parsed protocol with the problems found while parsing it
+type ParseResult struct {
+	Protocol Protocol     `json:"-"`
+	Warnings []Diagnostic `json:"warnings"`
+	Errors   []Diagnostic `json:"errors"`
+}
+
+// Diagnostics returns errors and warnings in one list, errors first
+func (r *ParseResult) Diagnostics() []Diagnostic {
+	return append(append([]Diagnostic{}, r.Errors...), r.Warnings...)
+}
+
+// ParseOptions control how ParseWith treats diagnostics
+type ParseOptions struct {
+	// Strict rejects a protocol when a required field could not be read
+	Strict bool
+}
+
+// parseLog collects diagnostics while parsing. A nil log discards them, so
+// the parse functions can also be used without diagnostics.
+type parseLog struct {
+	warnings []Diagnostic
+	errors   []Diagnostic
+}
+
+// warn records an unreadable optional value
+func (l *parseLog) warn(line int, field, raw, reason string) {
+	if l != nil {
+		l.warnings = append(l.warnings, Diagnostic{SeverityWarning, line, field, raw, reason})
+	}
+}
+
+// fail records an unreadable required value
+func (l *parseLog) fail(line int, field, raw, reason string) {
+	if l != nil {
+		l.errors = append(l.errors, Diagnostic{SeverityError, line, field, raw, reason})
+	}
+}
+
+// merge adds the diagnostics of another log, e.g. of a layout attempt that was kept
+func (l *parseLog) merge(other *parseLog) {
+	if l != nil {
+		l.warnings = append(l.warnings, other.warnings...)
+		l.errors = append(l.errors, other.errors...)
+	}
+}
+
+// result returns the collected diagnostics ordered by line with the parsed protocol
+func (l *parseLog) result(p Protocol) *ParseResult {
+	r := &ParseResult{
+		Protocol: p,
+		Warnings: append([]Diagnostic{}, l.warnings...),
+		Errors:   append([]Diagnostic{}, l.errors...),
+	}
+	byLine := func(d []Diagnostic) func(i, j int) bool {
+		return func(i, j int) bool { return d[i].Line < d[j].Line }
+	}
+	sort.SliceStable(r.Warnings, byLine(r.Warnings))
+	sort.SliceStable(r.Errors, byLine(r.Errors))
+	return r
+}
+
+// parseCell parses one numeric table cell, recording it as an error if unreadable
+func parseCell(l *parseLog, line int, field, raw string) (float64, bool) {
+	v, err := ParseDecimal(raw)
+	if err != nil {
+		l.fail(line, field, raw, "not a number")
+		return 0, false
+	}
+	return v, true
+}
+
+// parseCells parses the numeric cells of one table row. Every unreadable
+// cell is recorded as an error; ok is false if any cell failed, so the row
+// can be dropped instead of entering the data as 0.
+func parseCells(l *parseLog, line int, fields, raws []string) (values []float64, ok bool) {
+	values = make([]float64, len(raws))
+	ok = true
+	for i, raw := range raws {
+		v, cellOK := parseCell(l, line, fields[i], raw)
+		values[i] = v
+		ok = ok && cellOK
+	}
+	return values, ok
+}
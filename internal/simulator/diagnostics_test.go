@@ -0,0 +1,150 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLogResultOrder(t *testing.T) {
+	var l parseLog
+	l.warn(12, "timestamp", "25:61:00", "not a time")
+	l.fail(30, "row", "1 2", "expected 5 columns, found 2")
+	l.warn(3, "fiber_count", "Faserzahl:", "no fiber count after label")
+	l.fail(0, "data_points", "", "no measurements found")
+	l.fail(30, "speed_m_min", "4x", "not a number")
+
+	r := l.result(nil)
+	lines := func(d []Diagnostic) []int {
+		var n []int
+		for _, x := range d {
+			n = append(n, x.Line)
+		}
+		return n
+	}
+	if got := lines(r.Warnings); len(got) != 2 || got[0] != 3 || got[1] != 12 {
+		t.Errorf("warning lines = %v, want [3 12]", got)
+	}
+	// Diagnostics of the same line keep the order they were found in
+	if len(r.Errors) != 3 || r.Errors[0].Field != "data_points" || r.Errors[1].Field != "row" || r.Errors[2].Field != "speed_m_min" {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	all := r.Diagnostics()
+	if len(all) != 5 || all[0].Severity != SeverityError || all[3].Severity != SeverityWarning {
+		t.Errorf("Diagnostics() = %v, want errors first", all)
+	}
+
+	// A nil log discards diagnostics
+	var discard *parseLog
+	discard.warn(1, "field", "raw", "reason")
+	discard.fail(1, "field", "raw", "reason")
+	discard.merge(&l)
+}
+
+func TestParseCells(t *testing.T) {
+	var l parseLog
+	values, ok := parseCells(&l, 7, fremcoTableFields, []string{"12,5", "4x", "10.2", ""})
+	if ok {
+		t.Errorf("parseCells() ok with unreadable cells: %v", values)
+	}
+	if len(l.errors) != 2 {
+		t.Fatalf("errors = %v, want one per unreadable cell", l.errors)
+	}
+	if d := l.errors[0]; d.Line != 7 || d.Field != "speed_m_min" || d.Raw != "4x" {
+		t.Errorf("first error = %v", d)
+	}
+	if d := l.errors[1]; d.Field != "torque_percent" || d.Raw != "" {
+		t.Errorf("second error = %v", d)
+	}
+	if values[0] != 12.5 || values[2] != 10.2 {
+		t.Errorf("readable cells = %v", values)
+	}
+}
+
+// garbleFremcoRow replaces the speed of the n-th table row of a rendered
+// Fremco protocol and returns the text and the garbled row
+func garbleFremcoRow(t *testing.T, text string, n int) (string, string) {
+	t.Helper()
+	lines := strings.Split(text, "\n")
+	row := -1
+	for i, line := range lines {
+		if isFremcoTableHeader(line) {
+			row = i + n
+			break
+		}
+	}
+	if row < 0 || row >= len(lines) {
+		t.Fatalf("no table row %d in the rendered protocol", n)
+	}
+	fields := strings.Fields(lines[row])
+	fields[1] = "4x,2"
+	lines[row] = strings.Join(fields, " ")
+	return strings.Join(lines, "\n"), lines[row]
+}
+
+func TestParseWithStrict(t *testing.T) {
+	quietLog(t)
+	generated, err := GenerateFremcoProtocol(GeneratorOptions{Seed: 3, Stalls: -1, Restarts: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := LookupParser(FremcoParserName)
+	clean := RenderFremcoText(generated, 0)
+	garbled, row := garbleFremcoRow(t, clean, 3)
+
+	t.Run("clean protocol", func(t *testing.T) {
+		result, _, err := ParseWith(parser, clean, ParseOptions{Strict: true})
+		if err != nil {
+			t.Fatalf("ParseWith() = %v", err)
+		}
+		if len(result.Errors) != 0 || result.Protocol.DataPointCount() != generated.DataPointCount() {
+			t.Errorf("%d data points, errors %v", result.Protocol.DataPointCount(), result.Errors)
+		}
+	})
+
+	t.Run("garbled cell", func(t *testing.T) {
+		result, normalized, err := ParseWith(parser, garbled, ParseOptions{})
+		if err != nil {
+			t.Fatalf("ParseWith() = %v", err)
+		}
+		if len(result.Errors) != 1 {
+			t.Fatalf("errors = %v, want one", result.Errors)
+		}
+		d := result.Errors[0]
+		lines := strings.Split(normalized, "\n")
+		if d.Field != "speed_m_min" || d.Raw != "4x,2" || d.Line < 1 || d.Line > len(lines) || strings.TrimSpace(lines[d.Line-1]) != row {
+			t.Errorf("error = %v, want speed_m_min at the line %q", d, row)
+		}
+		// The row is dropped instead of entering the data with speed 0
+		if got := result.Protocol.DataPointCount(); got != generated.DataPointCount()-1 {
+			t.Errorf("%d data points, want %d", got, generated.DataPointCount()-1)
+		}
+
+		result, _, err = ParseWith(parser, garbled, ParseOptions{Strict: true})
+		if err == nil || !strings.Contains(err.Error(), "strict mode: 1 parse errors") || !strings.Contains(err.Error(), "speed_m_min") {
+			t.Errorf("strict ParseWith() error = %v", err)
+		}
+		if result == nil || len(result.Errors) != 1 {
+			t.Errorf("strict ParseWith() did not return the diagnostics: %+v", result)
+		}
+	})
+
+	t.Run("warnings only", func(t *testing.T) {
+		// An unreadable optional value does not reject the protocol
+		text := strings.Replace(clean, "Faserzahl: ", "Faserzahl: viele ", 1)
+		result, _, err := ParseWith(parser, text, ParseOptions{Strict: true})
+		if err != nil {
+			t.Fatalf("ParseWith() = %v", err)
+		}
+		if len(result.Warnings) == 0 || len(result.Errors) != 0 {
+			t.Errorf("warnings %v, errors %v", result.Warnings, result.Errors)
+		}
+	})
+
+	t.Run("no measurements", func(t *testing.T) {
+		text := clean[:strings.Index(clean, "Streckenlänge")]
+		result, _, err := ParseWith(parser, text, ParseOptions{Strict: true})
+		if err == nil || result == nil || len(result.Errors) != 1 || result.Errors[0].Field != "data_points" {
+			t.Errorf("ParseWith() = %v, %v", result, err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package simulator
+
+import "fmt"
+
+// Recommended cable/duct combinations. Tighter combinations jam in bends and
+// raise friction, looser ones let the cable buckle; values far outside these
+// ranges usually mean a wrong micro-duct size was recorded.
+const (
+	minDiameterRatio = 0.4 // cable diameter / duct inner diameter
+	maxDiameterRatio = 0.85
+	minClearanceMM   = 1.0
+)
+
+// Fill check results
+const (
+	FillStatusOK      = "ok"
+	FillStatusWarning = "warning"
+	FillStatusError   = "error"
+	FillStatusUnknown = "unknown"
+)
+
+// FillCheck contains the fill ratio and clearance of a cable in a duct
+type FillCheck struct {
+	CableDiameterMM     float64  `json:"cable_diameter_mm"`
+	DuctInnerDiameterMM float64  `json:"duct_inner_diameter_mm"`
+	DiameterRatio       float64  `json:"diameter_ratio"`  // cable / duct inner diameter
+	AreaFillRatio       float64  `json:"area_fill_ratio"` // cable / duct cross section
+	ClearanceMM         float64  `json:"clearance_mm"`    // duct inner diameter - cable diameter
+	Status              string   `json:"status"`
+	Warnings            []string `json:"warnings"`
+}
+
+// CheckCableDuctFill checks a cable diameter against a duct inner diameter
+func CheckCableDuctFill(cableDiameterMM, ductInnerDiameterMM float64) FillCheck {
+	check := FillCheck{
+		CableDiameterMM:     cableDiameterMM,
+		DuctInnerDiameterMM: ductInnerDiameterMM,
+		Status:              FillStatusOK,
+		Warnings:            []string{},
+	}
+	if cableDiameterMM <= 0 || ductInnerDiameterMM <= 0 {
+		check.Status = FillStatusUnknown
+		if cableDiameterMM <= 0 {
+			check.Warnings = append(check.Warnings, "Cable diameter unknown, fill ratio not checked")
+		}
+		if ductInnerDiameterMM <= 0 {
+			check.Warnings = append(check.Warnings, "Duct inner diameter unknown, fill ratio not checked")
+		}
+		return check
+	}
+
+	ratio := cableDiameterMM / ductInnerDiameterMM
+	check.DiameterRatio = round(ratio, 3)
+	check.AreaFillRatio = round(ratio*ratio, 3)
+	check.ClearanceMM = round(ductInnerDiameterMM-cableDiameterMM, 2)
+
+	switch {
+	case cableDiameterMM >= ductInnerDiameterMM:
+		check.Status = FillStatusError
+		check.Warnings = append(check.Warnings, fmt.Sprintf("Cable %.1f mm does not fit into duct with %.1f mm inner diameter", cableDiameterMM, ductInnerDiameterMM))
+	case ratio > maxDiameterRatio || check.ClearanceMM < minClearanceMM:
+		check.Status = FillStatusWarning
+		check.Warnings = append(check.Warnings, fmt.Sprintf("Fill ratio %.0f%% / clearance %.1f mm is too tight (recommended up to %.0f%%, at least %.1f mm)",
+			ratio*100, check.ClearanceMM, maxDiameterRatio*100, minClearanceMM))
+	case ratio < minDiameterRatio:
+		check.Status = FillStatusWarning
+		check.Warnings = append(check.Warnings, fmt.Sprintf("Fill ratio %.0f%% is too loose (recommended at least %.0f%%), cable may buckle", ratio*100, minDiameterRatio*100))
+	}
+	return check
+}
+
+// CheckPipeFill checks a cable diameter against the duct recorded as pipe type
+// or pipe bundle (e.g. "7x1,5" → 4 mm inner diameter)
+func CheckPipeFill(cableDiameterMM float64, pipeType, pipeBundle string) FillCheck {
+	inner, ok := InnerDiameterFromPipeType(pipeType)
+	if !ok {
+		inner, _ = InnerDiameterFromPipeType(pipeBundle)
+	}
+	return CheckCableDuctFill(cableDiameterMM, inner)
+}
+
+// FillCheck checks the recorded cable against the recorded duct of a Fremco protocol
+func (p *FremcoProtocol) FillCheck() FillCheck {
+	return CheckPipeFill(p.Equipment.Cable.Diameter, p.Equipment.Pipe.PipeType, p.Equipment.Pipe.PipeBundle)
+}
+
+// FillCheck checks the recorded cable against the recorded duct of a Jetting protocol
+func (p *JettingProtocol) FillCheck() FillCheck {
+	var diameter float64
+	var pipeType, pipeBundle string
+	if p.Equipment.Cable.Diameter != nil {
+		diameter = *p.Equipment.Cable.Diameter
+	}
+	if p.Equipment.Pipe.PipeType != nil {
+		pipeType = *p.Equipment.Pipe.PipeType
+	}
+	if p.Equipment.Pipe.PipeBundle != nil {
+		pipeBundle = *p.Equipment.Pipe.PipeBundle
+	}
+	return CheckPipeFill(diameter, pipeType, pipeBundle)
+}
@@ -0,0 +1,142 @@
+package simulator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Where a label stands in its line
+const (
+	fremcoLabelInline  = iota // may follow other fields, as in the summary line and the table header
+	fremcoLabelPrefix         // starts the line, the value follows ("Company Glasfaser Nord GmbH")
+	fremcoLabelHeading        // the whole line ("Cable", "Summary")
+)
+
+// fremcoLabel is a label of an English Fremco export with the German label
+// the Fremco extractors match
+type fremcoLabel struct {
+	English  string
+	German   string
+	Position int
+}
+
+// fremcoLabels translates English Fremco exports to the German labels, so both
+// languages produce identical FremcoProtocol structures. Where one label starts
+// with another ("Cable diameter:", "Cable"), the longer one comes first.
+var fremcoLabels = []fremcoLabel{
+	// Protocol info
+	{English: "Project No.", German: "Bauvorhaben Nr.", Position: fremcoLabelPrefix},
+	{English: "Project number:", German: "Bauvorhaben Nr.", Position: fremcoLabelPrefix},
+	{English: "Section / NVT", German: "Streckenabschnitt / NVt", Position: fremcoLabelHeading},
+	{English: "Company", German: "Firma", Position: fremcoLabelPrefix},
+	{English: "Service provider:", German: "Dienstleister:"},
+	{English: "Subcontractor:", German: "Nachunternehmer:"},
+	{English: "Operator:", German: "Einbläser:"},
+	{English: "Remarks", German: "Bemerkungen", Position: fremcoLabelHeading},
+
+	// Blowing device
+	{English: "Blowing device:", German: "Einblasgerät:"},
+	{English: "Equipment:", German: "Einblasgerät:"},
+	{English: "Controller serial number:", German: "Controller S/N:"},
+	{English: "Serial number:", German: "Controller S/N:"},
+	{English: "Serial No.:", German: "Controller S/N:"},
+	{English: "+ Crash test performed", German: "+ Crashtest Durchgeführt", Position: fremcoLabelPrefix},
+
+	// Pipe
+	{English: "Duct bundle:", German: "Rohrverband:"},
+	{English: "Pipe bundle:", German: "Rohrverband:"},
+	{English: "Duct inner wall:", German: "Rohrinnenwand:"},
+	{English: "Pipe inner wall:", German: "Rohrinnenwand:"},
+	{English: "Duct:", German: "Rohr:"},
+	{English: "Pipe:", German: "Rohr:"},
+	{English: "Duct", German: "Rohr", Position: fremcoLabelHeading},
+	{English: "Pipe", German: "Rohr", Position: fremcoLabelHeading},
+	{English: "Manufacturer:", German: "Hersteller:"},
+	{English: "Color code:", German: "Farbe-Kennung:"},
+	{English: "Colour code:", German: "Farbe-Kennung:"},
+
+	// Cable
+	{English: "Cable diameter:", German: "Kabel-Durchmesser:"},
+	{English: "Cable temperature:", German: "Kabel-Temperatur:"},
+	{English: "Cable blowing cap:", German: "Kabel-Einblaskappe:"},
+	{English: "Cable", German: "Kabel", Position: fremcoLabelHeading},
+	{English: "Designation:", German: "Bezeichnung:"},
+	{English: "Fiber count:", German: "Faserzahl:"},
+	{English: "Fibre count:", German: "Faserzahl:"},
+	{English: "Lubricant:", German: "Gleitmittel:"},
+
+	// Compressor
+	{English: "Compressor:", German: "Kompressor:"},
+	{English: "+ Oil separator", German: "+ Ölabscheider", Position: fremcoLabelPrefix},
+	{English: "+ Aftercooler", German: "+ Nachkühler", Position: fremcoLabelPrefix},
+	{English: "+ After cooler", German: "+ Nachkühler", Position: fremcoLabelPrefix},
+
+	// Meter readings and summary
+	{English: "Meter marks:", German: "Meterzahlen:", Position: fremcoLabelPrefix},
+	{English: "Meter readings:", German: "Meterzahlen:", Position: fremcoLabelPrefix},
+	{English: "End:", German: "Ende:"},
+	{English: "Summary", German: "Zusammenfassung", Position: fremcoLabelHeading},
+	{English: "Blowing distance:", German: "Strecke:"},
+	{English: "Blowing time:", German: "Einblaszeit:"},
+	{English: "Weather:", German: "Wetter:"},
+	{English: "Location (GPS):", German: "Ort (GPS):"},
+
+	// Table header
+	{English: "Length [m]", German: "Streckenlänge [m]"},
+	{English: "Speed [m/min]", German: "Geschwindigkeit [m/min]"},
+	{English: "Duct pressure [bar]", German: "Rohr-Druck [bar]"},
+	{English: "Pipe pressure [bar]", German: "Rohr-Druck [bar]"},
+	{English: "Pressure [bar]", German: "Rohr-Druck [bar]"},
+	{English: "Torque [%]", German: "Drehmoment [%]"},
+	{English: "Time [hh:mm:ss]", German: "Uhrzeit [hh:mm:ss]"},
+}
+
+// fremcoLabelRes are the compiled fremcoLabels, matched case-insensitively
+var fremcoLabelRes = compileFremcoLabels(fremcoLabels)
+
+// fremcoYesNoRe matches the English checkbox values "[ yes ]" and "[ no ]"
+var fremcoYesNoRe = regexp.MustCompile(`(?i)\[\s*(yes|no)\s*\]`)
+
+// fremcoLabelRe matches one English label; the first group keeps the text before it
+type fremcoLabelRe struct {
+	re     *regexp.Regexp
+	german string
+}
+
+// compileFremcoLabels compiles the label patterns for their position in the line
+func compileFremcoLabels(labels []fremcoLabel) []fremcoLabelRe {
+	res := make([]fremcoLabelRe, len(labels))
+	for i, l := range labels {
+		label := regexp.QuoteMeta(l.English)
+		switch l.Position {
+		case fremcoLabelPrefix:
+			res[i] = fremcoLabelRe{regexp.MustCompile(`(?i)^()` + label + `(?:\s|$)`), l.German + " "}
+		case fremcoLabelHeading:
+			res[i] = fremcoLabelRe{regexp.MustCompile(`(?i)^()` + label + `$`), l.German}
+		default:
+			res[i] = fremcoLabelRe{regexp.MustCompile(`(?i)(^|[\s|])` + label), l.German}
+		}
+	}
+	return res
+}
+
+// translateFremcoLabels replaces the labels and checkbox values of an English
+// Fremco export with the German ones. German text is returned unchanged, and
+// lines are neither added nor removed, so diagnostics keep their line numbers.
+func translateFremcoLabels(normalized string) string {
+	lines := strings.Split(normalized, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		for _, l := range fremcoLabelRes {
+			line = l.re.ReplaceAllString(line, "${1}"+l.german)
+		}
+		line = fremcoYesNoRe.ReplaceAllStringFunc(line, func(box string) string {
+			if strings.Contains(strings.ToLower(box), "yes") {
+				return "[ ja ]"
+			}
+			return "[ nein ]"
+		})
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
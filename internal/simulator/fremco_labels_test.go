@@ -0,0 +1,179 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+)
+
+// englishFremcoLabels are the labels of an English Fremco export for the
+// German labels RenderFremcoPages prints, written out here rather than taken
+// from fremcoLabels. Headings must be the whole line; the other labels start
+// the line or, with inline set, may stand anywhere in it.
+var englishFremcoLabels = []struct {
+	german, english string
+	heading, inline bool
+}{
+	{german: "Bauvorhaben Nr.", english: "Project No."},
+	{german: "Streckenabschnitt / NVt", english: "Section / NVT", heading: true},
+	{german: "Firma", english: "Company"},
+	{german: "Einbläser:", english: "Operator:"},
+	{german: "Einblasgerät:", english: "Blowing device:"},
+	{german: "Controller S/N:", english: "Serial number:"},
+	{german: "+ Crashtest Durchgeführt", english: "+ Crash test performed"},
+	{german: "Rohr", english: "Duct", heading: true},
+	{german: "Hersteller:", english: "Manufacturer:"},
+	{german: "Rohrverband:", english: "Pipe bundle:"},
+	{german: "Rohr:", english: "Duct:"},
+	{german: "Farbe-Kennung:", english: "Colour code:"},
+	{german: "Rohrinnenwand:", english: "Duct inner wall:"},
+	{german: "Kabel", english: "Cable", heading: true},
+	{german: "Bezeichnung:", english: "Designation:"},
+	{german: "Faserzahl:", english: "Fiber count:"},
+	{german: "Kabel-Durchmesser:", english: "Cable diameter:"},
+	{german: "Kabel-Temperatur:", english: "Cable temperature:"},
+	{german: "Gleitmittel:", english: "Lubricant:"},
+	{german: "Kabel-Einblaskappe:", english: "Cable blowing cap:"},
+	{german: "Kompressor:", english: "Compressor:"},
+	{german: "+ Ölabscheider", english: "+ Oil separator"},
+	{german: "+ Nachkühler", english: "+ Aftercooler"},
+	{german: "Meterzahlen:", english: "Meter readings:"},
+	{german: "Zusammenfassung", english: "Summary", heading: true},
+	{german: "Bemerkungen", english: "Remarks", heading: true},
+	{german: "Ende:", english: "End:", inline: true},
+	{german: "Strecke:", english: "Blowing distance:", inline: true},
+	{german: "Einblaszeit:", english: "Blowing time:", inline: true},
+	{german: "Wetter:", english: "Weather:", inline: true},
+	{german: "Ort (GPS):", english: "Location (GPS):", inline: true},
+	{german: "Streckenlänge [m]", english: "Length [m]", inline: true},
+	{german: "Geschwindigkeit [m/min]", english: "Speed [m/min]", inline: true},
+	{german: "Rohr-Druck [bar]", english: "Pressure [bar]", inline: true},
+	{german: "Drehmoment [%]", english: "Torque [%]", inline: true},
+	{german: "Uhrzeit [hh:mm:ss]", english: "Time [hh:mm:ss]", inline: true},
+	{german: "[ ja ]", english: "[ yes ]", inline: true},
+	{german: "[ nein ]", english: "[ no ]", inline: true},
+	{german: "Seite", english: "Page"},
+	{german: " von ", english: " of ", inline: true},
+}
+
+// englishFremcoText translates the labels of a rendered German Fremco protocol
+// to English. Only labels are replaced, values are left as they are.
+func englishFremcoText(german string) string {
+	lines := strings.Split(german, "\n")
+	for i, line := range lines {
+		for _, l := range englishFremcoLabels {
+			switch {
+			case l.heading:
+				if line == l.german {
+					line = l.english
+				}
+			case l.inline:
+				line = strings.ReplaceAll(line, l.german, l.english)
+			case strings.HasPrefix(line, l.german+" ") || line == l.german:
+				line = l.english + strings.TrimPrefix(line, l.german)
+			}
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestEnglishFremcoProtocols(t *testing.T) {
+	quietLog(t)
+	parser := LookupParser(FremcoParserName)
+	tests := []struct {
+		seed        int64
+		rowsPerPage int
+		checked     bool // value of every checkbox
+	}{
+		{1, 0, true},
+		{2, 0, false},
+		{3, 10, true},
+		{4, 10, false},
+	}
+	for _, tt := range tests {
+		generated, err := GenerateFremcoProtocol(GeneratorOptions{Seed: tt.seed, Stalls: -1, Restarts: -1})
+		if err != nil {
+			t.Fatalf("seed %d: %v", tt.seed, err)
+		}
+		device, cable, compressor := &generated.Equipment.BlowingDevice, &generated.Equipment.Cable, &generated.Equipment.Compressor
+		device.Lubricator, device.CrashTestPerformed = tt.checked, tt.checked
+		cable.BlowingCap = tt.checked
+		compressor.OilSeparator, compressor.AfterCooler = !tt.checked, !tt.checked
+
+		german := RenderFremcoText(generated, tt.rowsPerPage)
+		english := englishFremcoText(german)
+		for _, label := range []string{"Faserzahl", "Zusammenfassung", "Streckenlänge", "[ ja ]", "[ nein ]", "Seite"} {
+			if strings.Contains(english, label) {
+				t.Fatalf("seed %d: English text still contains %q", tt.seed, label)
+			}
+		}
+
+		var parsed []map[string]interface{}
+		for _, text := range []string{german, english} {
+			result, _, err := ParseWith(parser, text, ParseOptions{Strict: true})
+			if err != nil {
+				t.Fatalf("seed %d: %v", tt.seed, err)
+			}
+			if len(result.Warnings) != 0 {
+				t.Errorf("seed %d: warnings %v", tt.seed, result.Warnings)
+			}
+			value, err := roundTripValue(result.Protocol)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed = append(parsed, value)
+		}
+
+		var diffs []string
+		diffValues("", parsed[0], parsed[1], &diffs)
+		if len(diffs) > 0 {
+			t.Errorf("seed %d: English protocol differs from German:\n  %s", tt.seed, strings.Join(diffs, "\n  "))
+		}
+		// Both also match the generated protocol
+		diffs, err = RoundTripDiff(generated, tt.rowsPerPage)
+		if err != nil {
+			t.Fatalf("seed %d: %v", tt.seed, err)
+		}
+		if len(diffs) > 0 {
+			t.Errorf("seed %d: German protocol differs from the generated one:\n  %s", tt.seed, strings.Join(diffs, "\n  "))
+		}
+	}
+}
+
+func TestTranslateFremcoLabels(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		// German text is left alone
+		{"Hersteller: Gabocom", "Hersteller: Gabocom"},
+		{"Firma Glasfaser Nord GmbH", "Firma Glasfaser Nord GmbH"},
+		// Labels match case-insensitively, values are kept
+		{"MANUFACTURER: Gabocom", "Hersteller: Gabocom"},
+		{"Equipment: Fremco MicroFlow", "Einblasgerät: Fremco MicroFlow"},
+		{"Blowing distance: 512 Blowing time: 00:41:10", "Strecke: 512 Einblaszeit: 00:41:10"},
+		{"+ Oil separator [ YES ]", "+ Ölabscheider [ ja ]"},
+		{"Cable blowing cap: [no]", "Kabel-Einblaskappe: [ nein ]"},
+		// Headings only match the whole line, prefixes only the start of it
+		{"Cable", "Kabel"},
+		{"Cable diameter:", "Kabel-Durchmesser:"},
+		{"Designation: Cable 96F", "Bezeichnung: Cable 96F"},
+		{"Company", "Firma"},
+		{"Companyname Ltd", "Companyname Ltd"},
+		{"Telecom Company Ltd", "Telecom Company Ltd"},
+		// "Pipe pressure" is not the heading "Pipe"
+		{"Length [m] Speed [m/min] Pipe pressure [bar] Torque [%] Time [hh:mm:ss]",
+			"Streckenlänge [m] Geschwindigkeit [m/min] Rohr-Druck [bar] Drehmoment [%] Uhrzeit [hh:mm:ss]"},
+	}
+	for _, tt := range tests {
+		if got := translateFremcoLabels(tt.line); got != tt.want {
+			t.Errorf("translateFremcoLabels(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	// Lines are neither added nor removed
+	text := "Summary\n\nRemarks\nnone"
+	if got := translateFremcoLabels(text); strings.Count(got, "\n") != strings.Count(text, "\n") {
+		t.Errorf("translateFremcoLabels(%q) = %q, line count changed", text, got)
+	}
+}
@@ -0,0 +1,190 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Search range and tolerance for the friction coefficient fit
+const (
+	minFitFriction   = 0.01
+	maxFitFriction   = 0.8
+	fitTolerance     = 0.0005
+	minFitSamples    = 5
+	defaultPushForce = 100.0 // N, used when the blowing device is unknown
+)
+
+// FitModelVersion is stored with every fit so results can be recomputed when the model changes
+const FitModelVersion = "1.1.0"
+
+// devicePushForces maps blowing device models to their maximum pushing force in N.
+// Fremco protocols only record torque in %, which is converted with this value.
+var devicePushForces = map[string]float64{
+	"microflow": 100,
+	"mini":      150,
+	"mjet":      200,
+	"speednet":  100,
+}
+
+// DevicePushForce returns the maximum pushing force of a blowing device model
+func DevicePushForce(model string) float64 {
+	m := strings.ToLower(model)
+	for name, force := range devicePushForces {
+		if strings.Contains(m, name) {
+			return force
+		}
+	}
+	return defaultPushForce
+}
+
+// FitSample is a measured data point used to back-fit the friction coefficient
+type FitSample struct {
+	LengthM     float64 `json:"length_m"`
+	SpeedMMin   float64 `json:"speed_m_min"`
+	PressureBar float64 `json:"pressure_bar"`
+	ForceN      float64 `json:"force_n"`
+}
+
+// FitSamples converts measurements into fit samples. Measurements without a push
+// force (Fremco) give it from the torque in % of the device maximum pushForceN.
+func FitSamples(measurements []Measurement, pushForceN float64) []FitSample {
+	samples := make([]FitSample, 0, len(measurements))
+	for _, m := range measurements {
+		sample := FitSample{
+			LengthM:     m.LengthM,
+			SpeedMMin:   floatValue(m.SpeedMMin),
+			PressureBar: floatValue(m.PressureBar),
+		}
+		if m.ForceN != nil {
+			sample.ForceN = *m.ForceN
+		} else {
+			sample.ForceN = floatValue(m.TorquePercent) / 100 * pushForceN
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// FrictionFit contains the fitted parameters of a protocol
+type FrictionFit struct {
+	FrictionCoefficient float64  `json:"friction_coefficient"`
+	PressureDominanceM  *float64 `json:"pressure_dominance_m"` // length where air drag starts to dominate (nil = never)
+	RMSErrorN           float64  `json:"rms_error_n"`
+	SampleCount         int      `json:"sample_count"`
+	PressureBar         float64  `json:"pressure_bar"`
+	ModelVersion        string   `json:"model_version"`
+}
+
+// MedianPressure returns the median pressure of the samples with pressure applied
+func MedianPressure(samples []FitSample) float64 {
+	var pressures []float64
+	for _, s := range samples {
+		if s.PressureBar > 0 {
+			pressures = append(pressures, s.PressureBar)
+		}
+	}
+	return median(pressures)
+}
+
+// FitFriction back-fits the effective cable/duct friction coefficient of a
+// recorded run. It simulates the required pushing force along the run for a
+// candidate coefficient and minimizes the RMS error against the measured
+// forces with a golden-section search. The section length defaults to the
+// longest measured length and the pressure to the median measured pressure.
+func FitFriction(in SimulationInput, samples []FitSample) (*FrictionFit, error) {
+	var moving []FitSample
+	maxLength := 0.0
+	for _, s := range samples {
+		if s.SpeedMMin > 0 && s.LengthM > 0 {
+			moving = append(moving, s)
+		}
+		maxLength = math.Max(maxLength, s.LengthM)
+	}
+	if len(moving) < minFitSamples {
+		return nil, fmt.Errorf("not enough moving samples to fit friction (%d, need %d)", len(moving), minFitSamples)
+	}
+
+	if in.PressureBar <= 0 {
+		in.PressureBar = MedianPressure(moving)
+	}
+	if len(in.Duct.Segments) == 0 && in.Duct.LengthM <= 0 {
+		in.Duct.LengthM = maxLength
+	}
+	// Only validate the geometry, the push force is not needed for fitting
+	if in.PushForceN <= 0 {
+		in.PushForceN = defaultPushForce
+	}
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Model forces beyond the stall point grow without bound; cap them so
+	// that the error stays finite and comparable between candidates.
+	forceCap := 0.0
+	for _, s := range moving {
+		forceCap = math.Max(forceCap, s.ForceN)
+	}
+	forceCap = 2*forceCap + defaultPushForce
+
+	m := newBlowingModel(in)
+	rms := func(friction float64) float64 {
+		sum := 0.0
+		for _, s := range moving {
+			steps := int(math.Round(s.LengthM / m.step))
+			if steps > len(m.route) {
+				steps = len(m.route)
+			}
+			model := math.Min(math.Max(m.requiredForce(steps, friction), 0), forceCap)
+			diff := model - s.ForceN
+			sum += diff * diff
+		}
+		return math.Sqrt(sum / float64(len(moving)))
+	}
+
+	// Golden-section search for the coefficient with the smallest RMS error
+	ratio := (math.Sqrt(5) - 1) / 2
+	a, b := minFitFriction, maxFitFriction
+	c := b - ratio*(b-a)
+	d := a + ratio*(b-a)
+	fc, fd := rms(c), rms(d)
+	for b-a > fitTolerance {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - ratio*(b-a)
+			fc = rms(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + ratio*(b-a)
+			fd = rms(d)
+		}
+	}
+	friction := (a + b) / 2
+
+	fit := &FrictionFit{
+		FrictionCoefficient: round(friction, 4),
+		RMSErrorN:           round(rms(friction), 3),
+		SampleCount:         len(moving),
+		PressureBar:         round(in.PressureBar, 3),
+		ModelVersion:        FitModelVersion,
+	}
+	if x, ok := m.pressureDominance(friction); ok {
+		x = round(x, 1)
+		fit.PressureDominanceM = &x
+	}
+	return fit, nil
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
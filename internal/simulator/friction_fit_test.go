@@ -0,0 +1,121 @@
+package simulator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// simulatedSamples simulates the input and returns its data points as fit samples
+func simulatedSamples(t *testing.T, in SimulationInput) []FitSample {
+	t.Helper()
+	result, err := Simulate(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := make([]FitSample, len(result.JettingDataPoints))
+	for i, p := range result.JettingDataPoints {
+		samples[i] = FitSample{LengthM: p.LengthM, SpeedMMin: p.SpeedMMin, PressureBar: p.PressureBar, ForceN: p.ForceN}
+	}
+	return samples
+}
+
+func TestFitFrictionRoundTrip(t *testing.T) {
+	// With air the forces must stay above zero somewhere, or every small
+	// friction fits equally well
+	tests := []struct {
+		mu       float64
+		pressure float64
+	}{
+		{0.05, 0}, {0.12, 0}, {0.2, 0}, {0.35, 0}, {0.5, 0},
+		{0.12, 2}, {0.2, 2}, {0.35, 2}, {0.5, 2},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Duct.LengthM = 300
+		in.PressureBar = tt.pressure
+		in.FrictionCoefficient = tt.mu
+		samples := simulatedSamples(t, in)
+
+		// Fit with friction and pressure unknown
+		in.FrictionCoefficient = 0
+		in.PressureBar = 0
+		fit, err := FitFriction(in, samples)
+		if err != nil {
+			t.Fatalf("μ=%.2f, %.0f bar: %v", tt.mu, tt.pressure, err)
+		}
+		if math.Abs(fit.FrictionCoefficient-tt.mu) > 0.005 {
+			t.Errorf("μ=%.2f, %.0f bar: fitted %.4f (RMS %.3f N)", tt.mu, tt.pressure, fit.FrictionCoefficient, fit.RMSErrorN)
+		}
+		if fit.PressureBar != tt.pressure || fit.SampleCount == 0 || fit.ModelVersion != FitModelVersion {
+			t.Errorf("μ=%.2f, %.0f bar: fit %+v", tt.mu, tt.pressure, fit)
+		}
+	}
+}
+
+func TestFitFrictionBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		mu   float64
+		want float64
+	}{
+		{"below the search range", 0.002, minFitFriction},
+		{"above the search range", 1.2, maxFitFriction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := testInput()
+			in.Duct.LengthM = 300
+			in.FrictionCoefficient = tt.mu
+			samples := simulatedSamples(t, in)
+
+			in.FrictionCoefficient = 0
+			fit, err := FitFriction(in, samples)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fit.FrictionCoefficient < minFitFriction || fit.FrictionCoefficient > maxFitFriction {
+				t.Fatalf("fitted %.4f outside [%v, %v]", fit.FrictionCoefficient, minFitFriction, maxFitFriction)
+			}
+			if math.Abs(fit.FrictionCoefficient-tt.want) > fitTolerance {
+				t.Errorf("fitted %.4f, want the bound %v", fit.FrictionCoefficient, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitFrictionNotEnoughSamples(t *testing.T) {
+	samples := []FitSample{
+		{LengthM: 0, SpeedMMin: 60, ForceN: 0},
+		{LengthM: 10, SpeedMMin: 60, ForceN: 5},
+		{LengthM: 20, SpeedMMin: 0, ForceN: 90},
+		{LengthM: 30, SpeedMMin: 60, ForceN: 15},
+	}
+	_, err := FitFriction(testInput(), samples)
+	if err == nil || !strings.Contains(err.Error(), "not enough moving samples") {
+		t.Errorf("FitFriction() error = %v", err)
+	}
+}
+
+func TestFitSamples(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	measurements := []Measurement{
+		{LengthM: 10, SpeedMMin: f(60), PressureBar: f(10), TorquePercent: f(40)},
+		{LengthM: 20, SpeedMMin: f(50), ForceN: f(75), TorquePercent: f(40)},
+		{LengthM: 30},
+	}
+	want := []FitSample{
+		{LengthM: 10, SpeedMMin: 60, PressureBar: 10, ForceN: 80},
+		{LengthM: 20, SpeedMMin: 50, ForceN: 75},
+		{LengthM: 30},
+	}
+	got := FitSamples(measurements, 200)
+	if len(got) != len(want) {
+		t.Fatalf("FitSamples() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sample %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
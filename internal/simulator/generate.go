@@ -0,0 +1,431 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Defaults of the protocol generator
+const (
+	GeneratorVersion         = "1.0.0"
+	generatorMinLengthM      = 150.0
+	generatorMaxLengthM      = 1500.0
+	generatorFremcoStepM     = 1.0  // Fremco logs every meter
+	generatorJettingStepM    = 10.0 // Jetting logs every 10 m
+	generatorMaxRandomEvent  = 3    // upper bound of randomly chosen stalls/restarts
+	generatorRampRows        = 3    // rows needed to get back to full speed after a stop
+	generatorServiceProvider = "M.A.X. Bauservice"
+)
+
+// GeneratorOptions controls the synthetic protocol generator
+type GeneratorOptions struct {
+	Seed     int64     `json:"seed"`     // 0 = random
+	LengthM  float64   `json:"length_m"` // section length, 0 = random
+	Stalls   int       `json:"stalls"`   // cable stalls at full torque, -1 = random
+	Restarts int       `json:"restarts"` // stops with the air turned off, -1 = random
+	StepM    float64   `json:"step_m"`   // logging interval, 0 = device default
+	Start    time.Time `json:"start"`    // zero = random working day in 2025
+}
+
+// generatorDuct is a duct the generator can choose, matching the catalog seed
+type generatorDuct struct {
+	manufacturer string
+	bundle       string
+	pipeType     string
+	innerWall    string
+	innerMM      float64
+}
+
+// generatorCable is a cable the generator can choose, matching the catalog seed
+type generatorCable struct {
+	manufacturer string
+	designation  string
+	fiberCount   int
+	diameterMM   float64
+}
+
+var generatorDucts = []generatorDuct{
+	{"Gabocom", "SNRVe 22x7x1,5", "SNR 7x1,5", "Gerieft", 4},
+	{"Gabocom", "SNRVe 12x7x1,5", "SNR 7x1,5", "Gerieft", 4},
+	{"Gabocom", "SNRVe 7x10x1,0", "SNR 10x1,0", "Gerieft", 8},
+	{"Gabocom", "SNRVe 4x12x1,1", "SNR 12x1,1", "Gerieft", 9.8},
+	{"Emtelle", "FibreFlow 24x7/4", "7x1,5", "Glatt", 4},
+}
+
+var generatorCables = []generatorCable{
+	{"Prysmian", "A-D 2Y 1x6", 6, 2.5},
+	{"Prysmian", "A-D 2Y 1x12", 12, 2.5},
+	{"Prysmian", "A-D 2Y 2x12", 24, 3.8},
+	{"Prysmian", "A-D 2Y 4x12", 48, 4.9},
+	{"Prysmian", "A-D 2Y 8x12", 96, 6.5},
+	{"Corning", "MiniXtend 12F", 12, 2.0},
+	{"Corning", "MiniXtend 24F", 24, 2.8},
+}
+
+var (
+	generatorDevices     = []string{"Fremco MicroFlow LOG", "Fremco MiniFlow LOG", "Fremco MJet LOG"}
+	generatorCompressors = []string{"Kaiser m17a", "jetair141000"}
+	generatorLubricants  = []string{"Prelube 5000", "Prelube", "Polywater"}
+	generatorCompanies   = []string{"Wolken-ASM GmbH", "Glasfaser Nord GmbH", "Netzbau Weser GmbH"}
+	generatorOperators   = []string{"Marko", "Stefan", "Tobias", "Jan", "Dennis", "Ali"}
+	generatorStreets     = []string{"Haflinger Weg", "Dammstr", "Lindenallee", "Am Bahnhof", "Birkenweg", "Schulstr"}
+	generatorColors      = []string{"Rot", "Grün", "Blau", "Gelb", "Weiß", "Grau", "Braun", "Violett", "Türkis", "Schwarz", "Orange", "Rosa"}
+)
+
+// generatedSetup is the randomly chosen equipment and site of a generated run
+type generatedSetup struct {
+	start        time.Time
+	projectNo    string
+	street       string
+	nvt          string // NVT identifier, e.g. 1V2300
+	company      string
+	operator     string
+	device       string
+	controllerSN string
+	compressor   string
+	duct         generatorDuct
+	cable        generatorCable
+	colors       []string
+	lubricant    string
+	pressureBar  float64
+	pushForceN   float64
+	temperatureC float64
+	humidity     float64
+	latitude     float64
+	longitude    float64
+	stalled      bool // stuck before the end of the section
+	events       int  // stalls and restarts that happened
+}
+
+// generatedRow is one logged sample of a generated run
+type generatedRow struct {
+	lengthM       float64
+	speedMMin     float64
+	pressureBar   float64
+	torquePercent float64
+	forceN        float64
+	temperatureC  float64
+	elapsed       time.Duration
+}
+
+// GenerateFremcoProtocol generates a realistic Fremco protocol. The blowing
+// curve comes from the simulation model with randomly chosen equipment,
+// friction and section length, plus measurement noise, stalls and restarts.
+func GenerateFremcoProtocol(opts GeneratorOptions) (*FremcoProtocol, error) {
+	rng := newGeneratorRand(opts.Seed)
+	setup, rows, err := generateRun(rng, opts, generatorFremcoStepM)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &FremcoProtocol{
+		ProtocolInfo: FremcoProtocolInfo{
+			System:          "SpeedNet-System",
+			DocumentType:    "Einblas - Protokoll",
+			Date:            setup.start.Format("2006-01-02"),
+			StartTime:       setup.start.Format("15:04"),
+			ProjectNumber:   setup.projectNo,
+			SectionNVT:      setup.street + " / NVT" + setup.nvt,
+			Company:         setup.company,
+			ServiceProvider: generatorServiceProvider,
+			Operator:        setup.operator,
+			Remarks:         generatorRemarks(setup),
+		},
+		Equipment: FremcoEquipment{
+			BlowingDevice: FremcoBlowingDevice{
+				Model:        setup.device,
+				ControllerSN: setup.controllerSN,
+				Lubricator:   rng.Intn(2) == 0,
+			},
+			Pipe: FremcoPipe{
+				Manufacturer: setup.duct.manufacturer,
+				PipeBundle:   setup.duct.bundle,
+				PipeType:     setup.duct.pipeType,
+				ColorCoding:  setup.colors,
+				InnerWall:    setup.duct.innerWall,
+			},
+			Cable: FremcoCable{
+				Manufacturer: setup.cable.manufacturer,
+				Designation:  setup.cable.designation,
+				FiberCount:   setup.cable.fiberCount,
+				Diameter:     setup.cable.diameterMM,
+				Temperature:  floatPtr(round(setup.temperatureC-2+rng.Float64()*4, 0)),
+				Lubricant:    setup.lubricant,
+			},
+			Compressor: FremcoCompressor{
+				Model:       setup.compressor,
+				AfterCooler: rng.Intn(2) == 0,
+			},
+		},
+		Measurements: FremcoMeasurements{
+			DataPoints: make([]FremcoDataPoint, 0, len(rows)),
+		},
+		ExportMetadata: FremcoExportMetadata{
+			ParsedAt:      time.Now(),
+			ParserVersion: "generator-" + GeneratorVersion,
+			SourceFilename: fmt.Sprintf("%s_%s_%s_NVT%s.pdf", setup.projectNo, setup.start.Format("2006-01-02 15_04"),
+				setup.street, setup.nvt),
+		},
+	}
+
+	for _, r := range rows {
+		p.Measurements.DataPoints = append(p.Measurements.DataPoints, FremcoDataPoint{
+			LengthM:       round(r.lengthM, 1),
+			SpeedMMin:     round(r.speedMMin, 0),
+			PressureBar:   round(r.pressureBar, 1),
+			TorquePercent: round(r.torquePercent, 0),
+			Timestamp:     setup.start.Add(r.elapsed).Format("2006-01-02 15:04:05"),
+		})
+	}
+	last := rows[len(rows)-1]
+	distance := int(math.Round(last.lengthM))
+	meterStart := 1000 + rng.Intn(4000)
+	p.Measurements.MeterReadings = FremcoMeterReadings{Start: meterStart, End: meterStart - distance - rng.Intn(8)}
+	p.Measurements.Summary = FremcoSummary{
+		Distance:    distance,
+		BlowingTime: formatDuration(last.elapsed),
+		Weather:     FremcoWeather{Temperature: round(setup.temperatureC, 1), Humidity: round(setup.humidity, 1)},
+		GPSLocation: FremcoGPSLocation{Latitude: setup.latitude, Longitude: setup.longitude},
+	}
+	return p, nil
+}
+
+// GenerateJettingProtocol generates a realistic Jetting protocol in the same
+// way as GenerateFremcoProtocol. It only carries the header and the
+// measured data points, the equipment form is left empty.
+func GenerateJettingProtocol(opts GeneratorOptions) (*JettingProtocol, error) {
+	rng := newGeneratorRand(opts.Seed)
+	setup, rows, err := generateRun(rng, opts, generatorJettingStepM)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &JettingProtocol{
+		ProtocolInfo: JettingProtocolInfo{
+			System:          "Jetting System",
+			DocumentType:    "Jetting Protokoll",
+			Date:            setup.start.Format("02.01.2006"),
+			StartTime:       setup.start.Format("15:04"),
+			ProjectNumber:   &setup.nvt,
+			SectionNVT:      setup.street + " / NVT " + setup.nvt,
+			Company:         setup.company,
+			ServiceProvider: generatorServiceProvider,
+		},
+		Equipment: JettingEquipment{
+			Pipe: JettingPipe{ColorCoding: []string{}},
+		},
+		Measurements: JettingMeasurements{
+			DataPoints: make([]JettingDataPoint, 0, len(rows)),
+		},
+		ExportMetadata: JettingExportMetadata{
+			ParsedAt:       time.Now(),
+			ParserVersion:  "generator-" + GeneratorVersion,
+			SourceFilename: fmt.Sprintf("%s, %s NVT %s.pdf", setup.start.Format("02.01.2006, 15 04"), setup.street, setup.nvt),
+		},
+	}
+	for _, r := range rows {
+		p.Measurements.DataPoints = append(p.Measurements.DataPoints, JettingDataPoint{
+			LengthM:      round(r.lengthM, 1),
+			TemperatureC: round(r.temperatureC, 1),
+			ForceN:       round(r.forceN, 0),
+			PressureBar:  round(r.pressureBar, 2),
+			SpeedMMin:    round(r.speedMMin, 1),
+			TimeDuration: formatDuration(r.elapsed),
+		})
+	}
+	// The export has no summary; the parser derives it from the last row
+	last := rows[len(rows)-1]
+	distance := int(math.Round(round(last.lengthM, 1)))
+	blowingTime := formatDuration(last.elapsed)
+	p.Measurements.Summary = JettingSummary{Distance: &distance, BlowingTime: &blowingTime}
+	return p, nil
+}
+
+func newGeneratorRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// generateRun chooses a setup, simulates it and turns the predicted curve
+// into logged rows with noise, stalls and restarts
+func generateRun(rng *rand.Rand, opts GeneratorOptions, defaultStep float64) (*generatedSetup, []generatedRow, error) {
+	setup := newGeneratedSetup(rng, opts)
+
+	length := opts.LengthM
+	if length <= 0 {
+		length = math.Round(generatorMinLengthM + rng.Float64()*(generatorMaxLengthM-generatorMinLengthM))
+	}
+	step := opts.StepM
+	if step <= 0 {
+		step = defaultStep
+	}
+	in := SimulationInput{
+		Duct:                DuctParams{InnerDiameterMM: setup.duct.innerMM, InnerWall: setup.duct.innerWall, LengthM: length},
+		Cable:               CableParams{DiameterMM: setup.cable.diameterMM, Lubricant: setup.lubricant},
+		PressureBar:         setup.pressureBar,
+		PushForceN:          setup.pushForceN,
+		MaxSpeedMMin:        math.Round(40 + rng.Float64()*40),
+		FrictionCoefficient: DefaultFrictionCoefficient(setup.duct.innerWall, setup.lubricant) * (0.8 + rng.Float64()*0.5),
+		AmbientTemperatureC: setup.temperatureC,
+		StepM:               step,
+	}
+	sim, err := Simulate(in)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to simulate generated setup: %v", err)
+	}
+	setup.stalled = !sim.Reached
+
+	// Stalls and restarts happen at distinct points after the first few rows
+	points := sim.JettingDataPoints
+	events := map[int]string{}
+	candidates := rng.Perm(len(points))
+	addEvents := func(count int, kind string) {
+		if count < 0 {
+			count = rng.Intn(generatorMaxRandomEvent + 1)
+		}
+		for _, i := range candidates {
+			if count == 0 {
+				break
+			}
+			if _, taken := events[i]; !taken && i >= 5 && i < len(points)-1 {
+				events[i] = kind
+				count--
+			}
+		}
+	}
+	addEvents(opts.Stalls, "stall")
+	addEvents(opts.Restarts, "restart")
+	setup.events = len(events)
+
+	var rows []generatedRow
+	var elapsed time.Duration
+	ramp := 0
+	temperature := func() float64 { return setup.temperatureC + rng.NormFloat64()*0.3 }
+	stop := func(length float64, rowCount int, wait func() time.Duration, pressure, torque float64) {
+		for k := 0; k < rowCount; k++ {
+			elapsed += wait()
+			rows = append(rows, generatedRow{
+				lengthM:       length,
+				pressureBar:   pressure,
+				torquePercent: torque,
+				forceN:        torque / 100 * setup.pushForceN,
+				temperatureC:  temperature(),
+				elapsed:       elapsed,
+			})
+		}
+		ramp = generatorRampRows
+	}
+
+	for i, pt := range points {
+		switch events[i] {
+		case "stall":
+			// Cable stuck, the machine pushes at full torque until the crew frees it
+			stop(pt.LengthM, 3+rng.Intn(6), func() time.Duration { return time.Duration(5+rng.Intn(11)) * time.Second },
+				setup.pressureBar, 95+rng.Float64()*5)
+		case "restart":
+			// Air turned off and the machine stopped, then the pressure is built up again
+			stop(pt.LengthM, 2, func() time.Duration { return time.Duration(30+rng.Intn(151)) * time.Second }, 0, 0)
+			stop(pt.LengthM, 1, func() time.Duration { return time.Duration(10+rng.Intn(21)) * time.Second }, setup.pressureBar, 5)
+		}
+
+		speed := pt.SpeedMMin * (1 + rng.NormFloat64()*0.05)
+		if ramp > 0 {
+			speed *= float64(generatorRampRows-ramp+1) / float64(generatorRampRows+1)
+			ramp--
+		}
+		torque := math.Min(math.Max(pt.ForceN/setup.pushForceN*100+rng.NormFloat64()*2, 0), 100)
+		if i > 0 {
+			if speed < 1 {
+				speed = 1
+			}
+			elapsed += time.Duration(step / speed * float64(time.Minute))
+		} else {
+			speed = 0
+		}
+		rows = append(rows, generatedRow{
+			lengthM:       pt.LengthM,
+			speedMMin:     speed,
+			pressureBar:   math.Max(setup.pressureBar+rng.NormFloat64()*0.1, 0),
+			torquePercent: torque,
+			forceN:        torque / 100 * setup.pushForceN,
+			temperatureC:  temperature(),
+			elapsed:       elapsed,
+		})
+	}
+	if setup.stalled {
+		// The cable got stuck for good before the end of the section
+		stop(sim.MaxDistanceM, 4, func() time.Duration { return time.Duration(5+rng.Intn(11)) * time.Second },
+			setup.pressureBar, 100)
+	}
+	return setup, rows, nil
+}
+
+// newGeneratedSetup chooses the site and a cable/duct combination within the recommended fill ratio
+func newGeneratedSetup(rng *rand.Rand, opts GeneratorOptions) *generatedSetup {
+	duct := generatorDucts[rng.Intn(len(generatorDucts))]
+	var cables []generatorCable
+	for _, c := range generatorCables {
+		if CheckCableDuctFill(c.diameterMM, duct.innerMM).Status == FillStatusOK {
+			cables = append(cables, c)
+		}
+	}
+	if len(cables) == 0 {
+		cables = generatorCables[:1]
+	}
+
+	start := opts.Start
+	if start.IsZero() {
+		day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local).AddDate(0, 0, rng.Intn(240))
+		start = day.Add(time.Duration(7*60+rng.Intn(9*60)) * time.Minute)
+	}
+
+	colors := make([]string, 0, 2)
+	for _, i := range rng.Perm(len(generatorColors))[:2] {
+		colors = append(colors, generatorColors[i])
+	}
+	sort.Strings(colors)
+
+	device := generatorDevices[rng.Intn(len(generatorDevices))]
+	return &generatedSetup{
+		start:        start,
+		projectNo:    fmt.Sprintf("SM%09d", 200000000+rng.Intn(100000000)),
+		street:       fmt.Sprintf("%s %d", generatorStreets[rng.Intn(len(generatorStreets))], 1+rng.Intn(60)),
+		nvt:          fmt.Sprintf("%dV%04d", 1+rng.Intn(3), 100*rng.Intn(100)),
+		company:      generatorCompanies[rng.Intn(len(generatorCompanies))],
+		operator:     generatorOperators[rng.Intn(len(generatorOperators))],
+		device:       device,
+		controllerSN: fmt.Sprintf("%04d.%04d", 9000+rng.Intn(1000), rng.Intn(10000)),
+		compressor:   generatorCompressors[rng.Intn(len(generatorCompressors))],
+		duct:         duct,
+		cable:        cables[rng.Intn(len(cables))],
+		colors:       colors,
+		lubricant:    generatorLubricants[rng.Intn(len(generatorLubricants))],
+		pressureBar:  round(8+rng.Float64()*5, 1),
+		pushForceN:   DevicePushForce(device),
+		temperatureC: round(5+rng.Float64()*20, 1),
+		humidity:     round(40+rng.Float64()*50, 1),
+		latitude:     round(47.5+rng.Float64()*7, 5),
+		longitude:    round(6.5+rng.Float64()*8, 5),
+	}
+}
+
+// generatorRemarks returns the operator's remark for the run
+func generatorRemarks(setup *generatedSetup) string {
+	switch {
+	case setup.stalled:
+		return "Kabel festgefahren, Einblasen abgebrochen"
+	case setup.events > 0:
+		return "Einblasen mehrfach unterbrochen"
+	default:
+		return "Keine Besonderheiten"
+	}
+}
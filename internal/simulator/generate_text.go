@@ -0,0 +1,259 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default table rows per rendered PDF page
+const (
+	DefaultFremcoRowsPerPage  = 45
+	DefaultJettingRowsPerPage = 10
+)
+
+// formatValue formats a logged value without trailing zeros ("12", "10.5")
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// checkbox renders a Fremco form checkbox ("[ ja ]" / "[ nein ]")
+func checkbox(v bool) string {
+	if v {
+		return "[ ja ]"
+	}
+	return "[ nein ]"
+}
+
+// pageFooter is printed at the bottom of every rendered page
+func pageFooter(page, pages int) string {
+	return fmt.Sprintf("Seite %d von %d", page, pages)
+}
+
+// pageCount returns the number of pages needed for the rows
+func pageCount(rows, rowsPerPage int) int {
+	if rows == 0 {
+		return 1
+	}
+	return (rows + rowsPerPage - 1) / rowsPerPage
+}
+
+// RenderFremcoPages renders a Fremco protocol as normalized text pages in
+// the layout ParseFremcoProtocol consumes: the form on the first page, the
+// measurement table continued on the following pages with repeated headers.
+func RenderFremcoPages(p *FremcoProtocol, rowsPerPage int) []string {
+	if rowsPerPage <= 0 {
+		rowsPerPage = DefaultFremcoRowsPerPage
+	}
+	info, eq, m := p.ProtocolInfo, p.Equipment, p.Measurements
+	header := []string{
+		info.DocumentType,
+		info.System,
+		"Bauvorhaben Nr. " + info.ProjectNumber,
+		info.Date + " " + info.StartTime,
+		"Streckenabschnitt / NVt",
+		info.SectionNVT,
+		"Firma " + info.Company,
+		info.ServiceProvider,
+		"Einbläser: " + info.Operator,
+		"Einblasgerät: " + eq.BlowingDevice.Model,
+		"Controller S/N: " + eq.BlowingDevice.ControllerSN,
+		"+ Lubricator " + checkbox(eq.BlowingDevice.Lubricator),
+		"+ Crashtest Durchgeführt " + checkbox(eq.BlowingDevice.CrashTestPerformed),
+		"Rohr",
+		"Hersteller: " + eq.Pipe.Manufacturer,
+		"Rohrverband: " + eq.Pipe.PipeBundle,
+		"Rohr: " + eq.Pipe.PipeType,
+		"Farbe-Kennung: " + strings.Join(eq.Pipe.ColorCoding, " "),
+		"Rohrinnenwand: " + eq.Pipe.InnerWall,
+		"Kabel",
+		"Hersteller: " + eq.Cable.Manufacturer,
+		"Bezeichnung: " + eq.Cable.Designation,
+		"Faserzahl: " + strconv.Itoa(eq.Cable.FiberCount),
+		"Kabel-Durchmesser:",
+		formatValue(eq.Cable.Diameter),
+	}
+	if eq.Cable.Temperature != nil {
+		header = append(header, "Kabel-Temperatur: "+formatValue(*eq.Cable.Temperature)+"°C")
+	}
+	header = append(header,
+		"Gleitmittel: "+eq.Cable.Lubricant,
+		"Kabel-Einblaskappe: "+checkbox(eq.Cable.BlowingCap),
+		"Kompressor: "+eq.Compressor.Model,
+		"+ Ölabscheider "+checkbox(eq.Compressor.OilSeparator),
+		"+ Nachkühler "+checkbox(eq.Compressor.AfterCooler),
+		"Meterzahlen:",
+		fmt.Sprintf("Start: %d | Ende: %d", m.MeterReadings.Start, m.MeterReadings.End),
+		"Zusammenfassung",
+		fmt.Sprintf("Strecke: %d Einblaszeit: %s Wetter: %s°C, %s%%RH Ort (GPS): %s, %s",
+			m.Summary.Distance, m.Summary.BlowingTime,
+			formatValue(m.Summary.Weather.Temperature), formatValue(m.Summary.Weather.Humidity),
+			formatValue(m.Summary.GPSLocation.Latitude), formatValue(m.Summary.GPSLocation.Longitude)),
+		"Bemerkungen",
+		info.Remarks,
+	)
+
+	tableHeader := "Streckenlänge [m] Geschwindigkeit [m/min] Rohr-Druck [bar] Drehmoment [%] Uhrzeit [hh:mm:ss]"
+	pages := pageCount(len(m.DataPoints), rowsPerPage)
+	rendered := make([]string, 0, pages)
+	for page := 0; page < pages; page++ {
+		var lines []string
+		if page == 0 {
+			lines = append(lines, header...)
+		} else {
+			lines = append(lines, info.System+" "+info.DocumentType)
+		}
+		lines = append(lines, tableHeader)
+		end := min(len(m.DataPoints), (page+1)*rowsPerPage)
+		for _, dp := range m.DataPoints[page*rowsPerPage : end] {
+			lines = append(lines, strings.Join([]string{
+				formatValue(dp.LengthM), formatValue(dp.SpeedMMin), formatValue(dp.PressureBar),
+				formatValue(dp.TorquePercent), dp.Timestamp,
+			}, " "))
+		}
+		lines = append(lines, pageFooter(page+1, pages))
+		rendered = append(rendered, strings.Join(lines, "\n"))
+	}
+	return rendered
+}
+
+// RenderFremcoText renders a Fremco protocol as normalized text (see RenderFremcoPages)
+func RenderFremcoText(p *FremcoProtocol, rowsPerPage int) string {
+	return strings.Join(RenderFremcoPages(p, rowsPerPage), "\n")
+}
+
+// RenderJettingPages renders a Jetting protocol as normalized text pages in
+// the layout ParseJettingProtocol consumes: the header on the first page and
+// on every page the six column headers followed by one value per line.
+func RenderJettingPages(p *JettingProtocol, rowsPerPage int) []string {
+	if rowsPerPage <= 0 {
+		rowsPerPage = DefaultJettingRowsPerPage
+	}
+	info, m := p.ProtocolInfo, p.Measurements
+	header := []string{
+		info.ServiceProvider,
+		info.Company,
+		info.DocumentType,
+		"Datum: " + info.Date,
+		"Uhrzeit: " + info.StartTime,
+		"Adresse: " + info.SectionNVT,
+	}
+	tableHeader := []string{
+		"Länge[m]", "Lufttemperatur[°C]", "Schubkraft[N]", "Einblasdruck[bar]", "Geschwindigkeit[m/min]", "Zeit - Dauer[hh:mm:ss]",
+	}
+
+	pages := pageCount(len(m.DataPoints), rowsPerPage)
+	rendered := make([]string, 0, pages)
+	for page := 0; page < pages; page++ {
+		var lines []string
+		if page == 0 {
+			lines = append(lines, header...)
+		} else {
+			lines = append(lines, info.DocumentType)
+		}
+		lines = append(lines, tableHeader...)
+		end := min(len(m.DataPoints), (page+1)*rowsPerPage)
+		for _, dp := range m.DataPoints[page*rowsPerPage : end] {
+			lines = append(lines,
+				formatValue(dp.LengthM), formatValue(dp.TemperatureC), formatValue(dp.ForceN),
+				formatValue(dp.PressureBar), formatValue(dp.SpeedMMin), dp.TimeDuration)
+		}
+		lines = append(lines, pageFooter(page+1, pages))
+		rendered = append(rendered, strings.Join(lines, "\n"))
+	}
+	return rendered
+}
+
+// RenderJettingText renders a Jetting protocol as normalized text (see RenderJettingPages)
+func RenderJettingText(p *JettingProtocol, rowsPerPage int) string {
+	return strings.Join(RenderJettingPages(p, rowsPerPage), "\n")
+}
+
+// RoundTripDiff renders a generated protocol, parses the text with the parser
+// of its format and returns the fields the parser reads back differently, as
+// JSON paths with both values. The export metadata is not compared.
+func RoundTripDiff(p Protocol, rowsPerPage int) ([]string, error) {
+	var text string
+	switch g := p.(type) {
+	case *FremcoProtocol:
+		text = RenderFremcoText(g, rowsPerPage)
+	case *JettingProtocol:
+		text = RenderJettingText(g, rowsPerPage)
+	default:
+		return nil, fmt.Errorf("no renderer for %s protocols", p.Format())
+	}
+	parser := LookupParser(p.Format())
+	if parser == nil {
+		return nil, fmt.Errorf("no parser for %s protocols", p.Format())
+	}
+	result, _, err := ParseWith(parser, text, ParseOptions{Strict: true})
+	if err != nil {
+		return nil, err
+	}
+
+	generated, err := roundTripValue(p)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := roundTripValue(result.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []string
+	diffValues("", generated, parsed, &diffs)
+	return diffs, nil
+}
+
+// roundTripValue returns the JSON form of a protocol without its export metadata
+func roundTripValue(p Protocol) (map[string]interface{}, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	delete(v, "export_metadata")
+	return v, nil
+}
+
+// diffValues appends the paths at which two decoded JSON values differ
+func diffValues(path string, a, b interface{}, diffs *[]string) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(av)+len(bv))
+			for k := range av {
+				keys = append(keys, k)
+			}
+			for k := range bv {
+				if _, ok := av[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				diffValues(path+"."+k, av[k], bv[k], diffs)
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok && len(av) == len(bv) {
+			for i := range av {
+				diffValues(fmt.Sprintf("%s[%d]", path, i), av[i], bv[i], diffs)
+			}
+			return
+		}
+		if bv, ok := b.([]interface{}); ok {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %d entries, parsed %d", path, len(av), len(bv)))
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: %v, parsed %v", path, a, b))
+	}
+}
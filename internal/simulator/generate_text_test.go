@@ -0,0 +1,36 @@
+package simulator
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedProtocolsRoundTrip(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for seed := int64(1); seed <= 20; seed++ {
+		opts := GeneratorOptions{Seed: seed, Stalls: -1, Restarts: -1}
+		fremco, err := GenerateFremcoProtocol(opts)
+		if err != nil {
+			t.Fatalf("seed %d: GenerateFremcoProtocol: %v", seed, err)
+		}
+		jetting, err := GenerateJettingProtocol(opts)
+		if err != nil {
+			t.Fatalf("seed %d: GenerateJettingProtocol: %v", seed, err)
+		}
+		for _, p := range []Protocol{fremco, jetting} {
+			diffs, err := RoundTripDiff(p, 0)
+			if err != nil {
+				t.Errorf("seed %d %s: %v", seed, p.Format(), err)
+				continue
+			}
+			if len(diffs) > 0 {
+				t.Errorf("seed %d %s: parsed protocol differs:\n  %s", seed, p.Format(), strings.Join(diffs, "\n  "))
+			}
+		}
+	}
+}
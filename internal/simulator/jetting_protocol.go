@@ -18,13 +18,13 @@ type JettingProtocolInfo struct {
 	StartTime       string  `json:"start_time"`       // 13:41
 	ProjectNumber   *string `json:"project_number"`   // null for Jetting
 	SectionNVT      string  `json:"section_nvt"`      // Dammstr 8 / NVT 1V2300
-	Company         string  `json:"company"`          // Wolken-ASM GMBH
+	Company         string  `json:"company"`          // Wolken-ASM GmbH
 	ServiceProvider string  `json:"service_provider"` // M.A.X. Bauservice
 	Operator        *string `json:"operator"`         // null for Jetting
 	Remarks         string  `json:"remarks"`
 }
 
-// JettingEquipment contains equipment specifications (mostly null for Jetting)
+// JettingEquipment contains the equipment fields of the Jetting protocol form
 type JettingEquipment struct {
 	BlowingDevice JettingBlowingDevice `json:"blowing_device"`
 	Pipe          JettingPipe          `json:"pipe"`
@@ -32,7 +32,7 @@ type JettingEquipment struct {
 	Compressor    JettingCompressor    `json:"compressor"`
 }
 
-// JettingBlowingDevice represents Jetting blowing device (minimal data)
+// JettingBlowingDevice represents Jetting blowing device
 type JettingBlowingDevice struct {
 	Model                *string `json:"model"`
 	ControllerSN         *string `json:"controller_sn"`
@@ -40,19 +40,22 @@ type JettingBlowingDevice struct {
 	CrashTestPerformed   *bool   `json:"crash_test_performed"`
 	CrashTestSpeed       *string `json:"crash_test_speed"`
 	CrashTestMoment      *string `json:"crash_test_moment"`
+	CrashTestForce       *string `json:"crash_test_force"` // Ermittelte Schubkraft
 }
 
-// JettingPipe represents Jetting pipe specifications (minimal data)
+// JettingPipe represents Jetting pipe specifications
 type JettingPipe struct {
-	Manufacturer *string   `json:"manufacturer"`
-	PipeBundle   *string   `json:"pipe_bundle"`
-	PipeType     *string   `json:"pipe_type"`
-	ColorCoding  []string  `json:"color_coding"`
-	InnerWall    *string   `json:"inner_wall"`
-	Temperature  *float64  `json:"temperature"`
+	Manufacturer  *string  `json:"manufacturer"`
+	PipeBundle    *string  `json:"pipe_bundle"`
+	PipeType      *string  `json:"pipe_type"`
+	ColorCoding   []string `json:"color_coding"`
+	InnerWall     *string  `json:"inner_wall"`
+	Temperature   *float64 `json:"temperature"`
+	InnerDiameter *float64 `json:"inner_diameter"` // Rohrinnendurchmesser [mm]
+	OuterDiameter *float64 `json:"outer_diameter"` // Rohraußendurchmesser [mm]
 }
 
-// JettingCable represents Jetting cable specifications (minimal data)
+// JettingCable represents Jetting cable specifications
 type JettingCable struct {
 	Manufacturer *string  `json:"manufacturer"`
 	Designation  *string  `json:"designation"`
@@ -61,9 +64,10 @@ type JettingCable struct {
 	Temperature  *float64 `json:"temperature"`
 	Lubricant    *string  `json:"lubricant"`
 	BlowingCap   *bool    `json:"blowing_cap"`
+	DrumNumber   *string  `json:"drum_number"` // Kabeltrommel-Nr
 }
 
-// JettingCompressor represents Jetting compressor specifications (minimal data)
+// JettingCompressor represents Jetting compressor specifications
 type JettingCompressor struct {
 	Model        *string `json:"model"`
 	OilSeparator *bool   `json:"oil_separator"`
@@ -77,13 +81,13 @@ type JettingMeasurements struct {
 	DataPoints    []JettingDataPoint   `json:"data_points"`
 }
 
-// JettingMeterReadings represents meter readings (minimal for Jetting)
+// JettingMeterReadings represents the cable meter readings at start and end
 type JettingMeterReadings struct {
 	Start *int `json:"start"`
 	End   *int `json:"end"`
 }
 
-// JettingSummary contains summary information (minimal for Jetting)
+// JettingSummary contains the summary of the run, from the form or computed from the data points
 type JettingSummary struct {
 	Distance    *int                   `json:"distance"`
 	BlowingTime *string                `json:"blowing_time"`
@@ -91,16 +95,17 @@ type JettingSummary struct {
 	GPSLocation JettingGPSLocation     `json:"gps_location"`
 }
 
-// JettingWeather represents weather conditions (minimal for Jetting)
+// JettingWeather represents the ambient conditions
 type JettingWeather struct {
 	Temperature *float64 `json:"temperature"`
 	Humidity    *float64 `json:"humidity"`
 }
 
-// JettingGPSLocation represents GPS coordinates (minimal for Jetting)
+// JettingGPSLocation represents the GPS position ("52.4914°;9.85174°;95.8000 m")
 type JettingGPSLocation struct {
 	Latitude  *float64 `json:"latitude"`
 	Longitude *float64 `json:"longitude"`
+	Altitude  *float64 `json:"altitude"` // meters
 }
 
 // JettingDataPoint represents individual Jetting measurement point
@@ -0,0 +1,423 @@
+package simulator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Logged measurement tables: the log of a Plumettaz report or CSV export and
+// the raw logs of Fremco and Jetting controllers. Columns are found by name
+// and unit and converted to the units of the Measurement quantities.
+
+// Source column field beside the Measurement quantities
+const logClock = "clock" // time of day, also converted to the time since the first row
+
+// logColumnFields maps column names to Measurement quantities.
+// The first matching entry wins, so "Uhrzeit" is a clock and not an elapsed time.
+var logColumnFields = []struct {
+	field    string
+	keywords []string
+}{
+	{logClock, []string{"uhrzeit", "clock", "heure", "time of day"}},
+	{"temperature_c", []string{"temperatur", "température"}},
+	{"speed_m_min", []string{"speed", "geschwindigkeit", "vitesse"}},
+	{"torque_percent", []string{"torque", "drehmoment", "couple"}},
+	{"force_n", []string{"force", "kraft", "poussée"}},
+	{"pressure_bar", []string{"pressure", "druck", "pression"}},
+	{"length_m", []string{"distance", "length", "länge", "laenge", "longueur"}},
+	{"time_duration", []string{"time", "zeit", "dauer", "temps", "durée", "elapsed"}},
+}
+
+// logQuantity is the unit of a Measurement quantity and the
+// conversions from the units found in exports, keyed by lower case unit
+type logQuantity struct {
+	unit string
+	from map[string]func(float64) float64
+}
+
+func sameUnit(v float64) float64 { return v }
+
+func unitFactor(f float64) func(float64) float64 {
+	return func(v float64) float64 { return v * f }
+}
+
+// logQuantities are the supported units per column. Time columns are
+// read in seconds; cells printed as "hh:mm:ss" are read as durations.
+var logQuantities = map[string]logQuantity{
+	"length_m": {"m", map[string]func(float64) float64{
+		"m": sameUnit, "km": unitFactor(1000), "cm": unitFactor(0.01), "ft": unitFactor(0.3048)}},
+	"speed_m_min": {"m/min", map[string]func(float64) float64{
+		"m/min": sameUnit, "m/s": unitFactor(60), "km/h": unitFactor(1000.0 / 60), "ft/min": unitFactor(0.3048)}},
+	"torque_percent": {"%", map[string]func(float64) float64{
+		"%": sameUnit}},
+	"force_n": {"N", map[string]func(float64) float64{
+		"n": sameUnit, "dan": unitFactor(10), "kn": unitFactor(1000), "kgf": unitFactor(9.80665), "kg": unitFactor(9.80665), "lbf": unitFactor(4.44822)}},
+	"pressure_bar": {"bar", map[string]func(float64) float64{
+		"bar": sameUnit, "mbar": unitFactor(0.001), "kpa": unitFactor(0.01), "mpa": unitFactor(10), "psi": unitFactor(0.0689476)}},
+	"temperature_c": {"°C", map[string]func(float64) float64{
+		"°c": sameUnit, "c": sameUnit, "°f": func(f float64) float64 { return (f - 32) * 5 / 9 }}},
+	"time_duration": {"s", map[string]func(float64) float64{
+		"s": sameUnit, "sec": sameUnit, "min": unitFactor(60), "h": unitFactor(3600),
+		"hh:mm:ss": sameUnit, "h:mm:ss": sameUnit, "mm:ss": sameUnit}},
+	logClock: {"hh:mm:ss", map[string]func(float64) float64{
+		"hh:mm:ss": sameUnit, "hh:mm": sameUnit}},
+}
+
+// logColumnField returns the Measurement quantity of a column name, or ""
+func logColumnField(name string) string {
+	name = strings.ToLower(name)
+	for _, c := range logColumnFields {
+		for _, keyword := range c.keywords {
+			if strings.Contains(name, keyword) {
+				return c.field
+			}
+		}
+	}
+	return ""
+}
+
+var (
+	// logHeaderCellRe splits a CSV header cell into name and unit: "Speed [m/s]", "Kraft (daN)"
+	logHeaderCellRe = regexp.MustCompile(`^(.*?)\s*[\[(]([^\])]*)[\])]$`)
+	// logHeaderRe finds the columns of a space separated header, every column with its unit
+	logHeaderRe = regexp.MustCompile(`([^\[\]\s][^\[\]]*?)\s*\[([^\]]*)\]`)
+	// logPageRe matches page footers of PDF reports
+	logPageRe = regexp.MustCompile(`(?i)^(?:page|seite)\s+\d+`)
+)
+
+// logSeparators are the CSV separators, in the order they are tried
+var logSeparators = []string{";", "\t", ","}
+
+// logLayout is the column layout of a logged table
+type logLayout struct {
+	line      int    // index of the header line, -1 if there is none
+	separator string // CSV separator, empty for the space separated table of a PDF report
+	columns   []SourceColumn
+}
+
+// String lists the columns with their units
+func (l logLayout) String() string {
+	names := make([]string, len(l.columns))
+	for i, c := range l.columns {
+		names[i] = fmt.Sprintf("%s [%s]", c.Name, c.Unit)
+	}
+	return strings.Join(names, ", ")
+}
+
+// has reports whether a column is read into the field
+func (l logLayout) has(field string) bool {
+	for _, c := range l.columns {
+		if c.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// logCells splits a CSV line into trimmed cells, without trailing empty cells
+func logCells(line, separator string) []string {
+	if separator == "" {
+		return strings.Fields(line)
+	}
+	cells := strings.Split(line, separator)
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	for len(cells) > 0 && cells[len(cells)-1] == "" {
+		cells = cells[:len(cells)-1]
+	}
+	return cells
+}
+
+// findLogHeader returns the first line naming a length column and at least two other known columns
+func findLogHeader(lines []string) (logLayout, bool) {
+	for i, line := range lines {
+		if layout, ok := parseLogHeader(strings.TrimSpace(line)); ok {
+			layout.line = i
+			return layout, true
+		}
+	}
+	return logLayout{line: -1}, false
+}
+
+// parseLogHeader reads a header line as CSV cells or as a space separated PDF table header
+func parseLogHeader(line string) (logLayout, bool) {
+	var layout logLayout
+	for _, separator := range logSeparators {
+		cells := logCells(line, separator)
+		if len(cells) < 3 {
+			continue
+		}
+		layout.separator = separator
+		for _, cell := range cells {
+			name, unit := cell, ""
+			if m := logHeaderCellRe.FindStringSubmatch(cell); m != nil {
+				name, unit = m[1], m[2]
+			}
+			layout.columns = append(layout.columns, SourceColumn{Name: name, Unit: unit, Field: logColumnField(name)})
+		}
+		break
+	}
+	if layout.separator == "" {
+		for _, m := range logHeaderRe.FindAllStringSubmatch(line, -1) {
+			layout.columns = append(layout.columns, SourceColumn{Name: m[1], Unit: m[2], Field: logColumnField(m[1])})
+		}
+	}
+	known := 0
+	for _, c := range layout.columns {
+		if c.Field != "" {
+			known++
+		}
+	}
+	return layout, layout.has("length_m") && known >= 3
+}
+
+// logLabels are the header labels of English, German and French exports
+var logLabels = map[string][]string{
+	"model":            {"machine", "machine type", "model", "maschine", "maschinentyp", "gerät", "device", "blowing device", "einblasgerät"},
+	"serial":           {"serial number", "serial no.", "serial no", "s/n", "seriennummer", "serien-nr.", "n° de série", "numéro de série", "controller s/n", "controller sn", "controller serial number"},
+	"project":          {"job", "job no.", "project", "project no.", "projekt", "projekt-nr.", "auftrag", "bauvorhaben", "projet"},
+	"section":          {"site", "section", "location", "baustelle", "streckenabschnitt", "abschnitt", "chantier", "tronçon"},
+	"company":          {"customer", "client", "company", "kunde", "auftraggeber", "firma"},
+	"service_provider": {"contractor", "subcontractor", "unternehmer", "dienstleister", "entreprise"},
+	"operator":         {"operator", "bediener", "einbläser", "opérateur"},
+	"date":             {"date", "datum"},
+	"start_time":       {"start", "start time", "startzeit", "beginn", "uhrzeit", "début", "heure de début"},
+	"duct":             {"duct", "pipe", "microduct", "rohr", "tube", "conduit"},
+	"cable":            {"cable", "kabel", "câble"},
+	"cable_diameter":   {"cable diameter", "kabeldurchmesser", "kabel-durchmesser", "diamètre câble", "diamètre du câble"},
+	"fiber_count":      {"fibers", "fibres", "fiber count", "fibre count", "faserzahl", "fasern", "nombre de fibres"},
+	"lubricant":        {"lubricant", "gleitmittel", "lubrifiant"},
+	"compressor":       {"compressor", "kompressor", "compresseur"},
+	"distance":         {"total length", "blown length", "distance", "gesamtlänge", "einblaslänge", "strecke", "longueur totale", "longueur soufflée"},
+	"blowing_time":     {"duration", "blowing time", "total time", "dauer", "einblaszeit", "durée"},
+	"temperature":      {"ambient temperature", "air temperature", "temperature", "außentemperatur", "temperatur", "température"},
+	"remarks":          {"remarks", "comment", "comments", "notes", "bemerkung", "bemerkungen", "remarque", "remarques"},
+}
+
+// logLabelFields looks up the header field of a lower case label
+var logLabelFields = func() map[string]string {
+	fields := make(map[string]string)
+	for field, labels := range logLabels {
+		for _, label := range labels {
+			fields[label] = field
+		}
+	}
+	return fields
+}()
+
+// logLabelField returns the header field of a label cell ("Serial number:", "Datum"), or ""
+func logLabelField(label string) string {
+	label = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(label), ":")))
+	return logLabelFields[label]
+}
+
+// logHeaderValue is a labeled header value and the line it was read from
+type logHeaderValue struct {
+	field string
+	value string
+	line  int
+}
+
+// readLogHeaderValues reads the labeled header before and after a logged table:
+// "Label: value" lines, or "Label;value" cells in CSV files. A label without
+// value takes the next line, as PDF reports print long values below the label.
+func readLogHeaderValues(lines []string, layout logLayout) []logHeaderValue {
+	var values []logHeaderValue
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if i == layout.line || line == "" || strings.ContainsAny(line[:1], "0123456789") {
+			continue
+		}
+		var cells []string
+		if layout.separator != "" && strings.Contains(line, layout.separator) {
+			cells = logCells(line, layout.separator)
+		} else if idx := strings.Index(line, ":"); idx > 0 {
+			cells = []string{line[:idx], strings.TrimSpace(line[idx+1:])}
+		}
+		for c := 0; c < len(cells); c++ {
+			field := logLabelField(cells[c])
+			if field == "" {
+				continue
+			}
+			value, valueLine := "", i+1
+			if c+1 < len(cells) {
+				value = cells[c+1]
+				c++
+			}
+			if value == "" && len(cells) <= 2 && i+1 < len(lines) && i+1 != layout.line {
+				next := strings.TrimSpace(lines[i+1])
+				if !strings.Contains(next, ":") && logLabelField(next) == "" {
+					value, valueLine = next, i+2
+					i++
+				}
+			}
+			if value != "" {
+				values = append(values, logHeaderValue{field, value, valueLine})
+			}
+		}
+	}
+	return values
+}
+
+// logRow is one row of a logged table in the units of the Measurement
+// quantities. Columns the table does not have are nil.
+type logRow struct {
+	lengthM       float64
+	speedMMin     *float64
+	pressureBar   *float64
+	torquePercent *float64
+	forceN        *float64
+	temperatureC  *float64
+	elapsed       *time.Duration // since the first row, from the time column or the clock
+	clock         *time.Duration // time of day
+}
+
+// readLogTable reads the rows after the header. Columns with an unsupported
+// unit are reported and not read; rows with an unreadable cell are dropped.
+// Page footers, repeated headers and labeled values are skipped.
+func readLogTable(lines []string, layout *logLayout, diag *parseLog) []logRow {
+	header := strings.TrimSpace(lines[layout.line])
+	converters := make([]func(float64) float64, len(layout.columns))
+	for c := range layout.columns {
+		column := &layout.columns[c]
+		if column.Field == "" {
+			continue
+		}
+		quantity := logQuantities[column.Field]
+		convert, ok := quantity.from[strings.ToLower(strings.ReplaceAll(column.Unit, " ", ""))]
+		switch {
+		case column.Unit == "":
+			diag.warn(layout.line+1, column.Field, column.Name, fmt.Sprintf("no unit, read as %s", quantity.unit))
+			convert = sameUnit
+		case !ok:
+			diag.fail(layout.line+1, "table_header", header, fmt.Sprintf("unit %q of column %q is not supported", column.Unit, column.Name))
+			column.Field = ""
+			continue
+		}
+		converters[c] = convert
+	}
+	if !layout.has("length_m") {
+		return nil
+	}
+
+	var rows []logRow
+	var firstClock *time.Duration
+	for i := layout.line + 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		cells := logCells(line, layout.separator)
+		if len(cells) == 0 || !strings.ContainsAny(cells[0][:1], "0123456789+-") || logPageRe.MatchString(line) {
+			continue
+		}
+		if len(cells) != len(layout.columns) {
+			if len(cells) > 1 {
+				diag.fail(i+1, "row", line, fmt.Sprintf("expected %d columns, found %d", len(layout.columns), len(cells)))
+			}
+			continue
+		}
+
+		var row logRow
+		ok := true
+		for c, column := range layout.columns {
+			raw := cells[c]
+			switch column.Field {
+			case "":
+			case "time_duration", logClock:
+				d, err := logDuration(raw, converters[c])
+				if err != nil {
+					diag.fail(i+1, column.Field, raw, "not a time")
+					ok = false
+				} else if column.Field == logClock {
+					row.clock = &d
+				} else {
+					row.elapsed = &d
+				}
+			default:
+				v, cellOK := parseCell(diag, i+1, column.Field, raw)
+				if !cellOK {
+					ok = false
+					continue
+				}
+				v = round(converters[c](v), 6)
+				switch column.Field {
+				case "length_m":
+					row.lengthM = v
+				case "speed_m_min":
+					row.speedMMin = &v
+				case "pressure_bar":
+					row.pressureBar = &v
+				case "torque_percent":
+					row.torquePercent = &v
+				case "force_n":
+					row.forceN = &v
+				case "temperature_c":
+					row.temperatureC = &v
+				}
+			}
+		}
+		if ok {
+			rows = append(rows, row)
+		}
+	}
+
+	// An English "Time" column is a time of day if it does not start near zero
+	if len(rows) > 0 && !layout.has(logClock) && rows[0].elapsed != nil && *rows[0].elapsed >= time.Minute {
+		for c := range layout.columns {
+			if layout.columns[c].Field == "time_duration" {
+				layout.columns[c].Field = logClock
+			}
+		}
+		for i := range rows {
+			rows[i].clock, rows[i].elapsed = rows[i].elapsed, nil
+		}
+	}
+	// A time of day becomes the time since the first row, across midnight
+	for i := range rows {
+		if rows[i].clock == nil || rows[i].elapsed != nil {
+			continue
+		}
+		if firstClock == nil {
+			firstClock = rows[i].clock
+		}
+		d := *rows[i].clock - *firstClock
+		if d < 0 {
+			d += 24 * time.Hour
+		}
+		rows[i].elapsed = &d
+	}
+	return rows
+}
+
+// logMeasurements converts logged rows to measurements and their times of day
+func logMeasurements(rows []logRow) ([]Measurement, []*time.Duration) {
+	measurements := make([]Measurement, 0, len(rows))
+	clocks := make([]*time.Duration, 0, len(rows))
+	for i, row := range rows {
+		measurements = append(measurements, Measurement{
+			Sequence:      i + 1,
+			LengthM:       row.lengthM,
+			SpeedMMin:     row.speedMMin,
+			PressureBar:   row.pressureBar,
+			TorquePercent: row.torquePercent,
+			ForceN:        row.forceN,
+			TemperatureC:  row.temperatureC,
+			Elapsed:       row.elapsed,
+		})
+		clocks = append(clocks, row.clock)
+	}
+	return measurements, clocks
+}
+
+// logDuration reads a time cell as "hh:mm:ss" or as a number in the column's unit
+func logDuration(raw string, toSeconds func(float64) float64) (time.Duration, error) {
+	if strings.Contains(raw, ":") {
+		return ParseElapsed(raw)
+	}
+	v, err := ParseDecimal(raw)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(toSeconds(v) * float64(time.Second)), nil
+}
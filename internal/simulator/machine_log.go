@@ -0,0 +1,301 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// machineLogParserVersion is recorded for protocols created from a machine log
+const machineLogParserVersion = "1.0.0"
+
+// MachineLogStartTolerance is how far the start time of a machine log may be
+// from the start time of the PDF protocol it belongs to. The PDF prints the
+// start to the minute, the controller logs the first row to the second.
+const MachineLogStartTolerance = 10 * time.Minute
+
+// MachineLogLengthTolerance is how far the installed length of a Jetting PDF
+// protocol may be from the last logged length. Jetting PDFs print no controller
+// S/N, so their logs are matched by date, start time and length; the PDF table
+// has a row every 10 m.
+const MachineLogLengthTolerance = 15.0
+
+// MachineLog is a raw measurement log exported from the controller of a Fremco
+// MicroFlow LOG or a Jetting machine (CSV on a USB stick). Logs have more rows
+// and digits than the tables of the PDF protocols, which round values and are
+// sometimes cut off.
+type MachineLog struct {
+	Format         string         `json:"format"`        // FremcoParserName or JettingParserName
+	Device         string         `json:"device"`        // Fremco MicroFlow LOG
+	ControllerSN   string         `json:"controller_sn"` // 9911.0027
+	Date           string         `json:"date"`          // 17.06.2025
+	StartTime      string         `json:"start_time"`    // 15:29:04
+	BlowingTime    string         `json:"blowing_time"`  // hh:mm:ss from the first to the last row
+	SourceFilename string         `json:"source_filename"`
+	Columns        []SourceColumn `json:"columns"`
+	Measurements   []Measurement  `json:"measurements"`
+}
+
+// DetectMachineLog reports whether the text is a Fremco or Jetting controller
+// log and of which format. Logs are CSV files with a header row naming the
+// logged columns; CSV files that another registered parser detects, such as
+// Plumettaz logs, are left to that parser.
+func DetectMachineLog(text string) (string, bool) {
+	if protocolLogFormat(text, "") != "" {
+		return "", false
+	}
+	layout, ok := findLogHeader(strings.Split(text, "\n"))
+	if !ok || layout.separator == "" {
+		return "", false
+	}
+	format := machineLogFormat(text, layout)
+	return format, format != ""
+}
+
+// protocolLogFormat returns the registered format other than Fremco and Jetting
+// that DetectFormat picks for a log without review, "" if there is none
+func protocolLogFormat(text, filename string) string {
+	detection := DetectFormat(text, filename)
+	if detection.Review || detection.Format == FremcoParserName || detection.Format == JettingParserName {
+		return ""
+	}
+	return detection.Format
+}
+
+// machineLogFormat tells Fremco from Jetting logs by the device name, or by
+// the torque column of Fremco machines and the push force column of Jetting machines
+func machineLogFormat(text string, layout logLayout) string {
+	switch {
+	case strings.Contains(text, "Fremco") || strings.Contains(text, "MicroFlow") || strings.Contains(text, "SpeedNet"):
+		return FremcoParserName
+	case strings.Contains(strings.ToLower(text), "jetting"):
+		return JettingParserName
+	case layout.has("torque_percent"):
+		return FremcoParserName
+	case layout.has("force_n"):
+		return JettingParserName
+	}
+	return ""
+}
+
+// ParseMachineLog parses a Fremco or Jetting controller log. Unreadable cells
+// are returned as diagnostics of a ParseResult without protocol; in strict
+// mode they reject the log.
+func ParseMachineLog(text, filename string, opts ParseOptions) (*MachineLog, *ParseResult, error) {
+	normalized := NormalizeFremcoTxt(strings.TrimPrefix(text, "\ufeff"))
+	lines := strings.Split(normalized, "\n")
+	layout, ok := findLogHeader(lines)
+	if !ok || layout.separator == "" {
+		return nil, nil, fmt.Errorf("no logged columns found (length and two of time, speed, pressure, torque, force, temperature)")
+	}
+	if format := protocolLogFormat(normalized, filename); format != "" {
+		return nil, nil, fmt.Errorf("not a Fremco or Jetting controller log: %s logs are uploaded as protocols", format)
+	}
+	format := machineLogFormat(normalized, layout)
+	if format == "" {
+		return nil, nil, fmt.Errorf("unknown machine log: neither a Fremco nor a Jetting controller log")
+	}
+
+	var diag parseLog
+	rows := readLogTable(lines, &layout, &diag)
+	if len(rows) == 0 {
+		diag.fail(0, "data_points", "", "no measurements found")
+	}
+
+	l := &MachineLog{Format: format, SourceFilename: filename, Columns: layout.columns}
+	for _, v := range readLogHeaderValues(lines, layout) {
+		switch v.field {
+		case "model":
+			l.Device = v.value
+		case "serial":
+			l.ControllerSN = v.value
+		case "date":
+			l.Date = v.value
+		case "start_time":
+			// "17.06.2025 15:29:04" carries the date as well
+			if fields := strings.Fields(v.value); len(fields) == 2 {
+				if l.Date == "" {
+					l.Date = fields[0]
+				}
+				l.StartTime = fields[1]
+			} else {
+				l.StartTime = v.value
+			}
+		}
+	}
+	// A title line above the header names the device ("Fremco MicroFlow LOG")
+	if l.Device == "" && layout.line > 0 {
+		if cells := logCells(lines[0], layout.separator); len(cells) == 1 && logLabelField(cells[0]) == "" {
+			l.Device = cells[0]
+		}
+	}
+	if n := len(rows); n > 0 {
+		if l.StartTime == "" && rows[0].clock != nil {
+			l.StartTime = FormatElapsed(*rows[0].clock)
+		}
+		if rows[n-1].elapsed != nil {
+			l.BlowingTime = FormatElapsed(*rows[n-1].elapsed)
+		}
+	}
+
+	measurements, clocks := logMeasurements(rows)
+	start, hasStart := l.StartedAt()
+	completeMeasurementTimes(measurements, clocks, start, hasStart)
+	l.Measurements = measurements
+
+	result := diag.result(nil)
+	if opts.Strict && len(result.Errors) > 0 {
+		return l, result, fmt.Errorf("strict mode: %d parse errors, first at %s", len(result.Errors), result.Errors[0])
+	}
+	return l, result, nil
+}
+
+// StartedAt returns the start of the log from its date and start time
+func (l *MachineLog) StartedAt() (time.Time, bool) {
+	return parseProtocolStart(l.Date, l.StartTime)
+}
+
+// MachineLogCandidate is a stored protocol of the same format and date as a
+// machine log, which the log may belong to
+type MachineLogCandidate struct {
+	ID           int     `db:"id"`
+	StartTime    string  `db:"start_time"` // hh:mm[:ss]
+	ControllerSN string  `db:"controller_sn"`
+	MaxLengthM   float64 `db:"max_length"`
+}
+
+// MatchProtocol returns the candidate whose start time is closest to the start
+// of the log, within MachineLogStartTolerance. A candidate must have the
+// controller S/N of the log; Jetting PDFs print no S/N, so a Jetting candidate
+// without one matches if its installed length is within MachineLogLengthTolerance
+// of the last logged length. Fremco logs without S/N match nothing.
+func (l *MachineLog) MatchProtocol(candidates []MachineLogCandidate) (int, bool) {
+	started, ok := l.StartedAt()
+	if !ok {
+		return 0, false
+	}
+	logStart := time.Duration(started.Hour())*time.Hour + time.Duration(started.Minute())*time.Minute +
+		time.Duration(started.Second())*time.Second
+	jetting := l.Format == JettingParserName
+
+	bestID, bestDiff := 0, MachineLogStartTolerance+time.Second
+	for _, c := range candidates {
+		sameDevice := l.ControllerSN != "" && strings.TrimSpace(c.ControllerSN) == l.ControllerSN
+		sameLength := jetting && strings.TrimSpace(c.ControllerSN) == "" &&
+			math.Abs(c.MaxLengthM-l.MaxLengthM()) <= MachineLogLengthTolerance
+		if !sameDevice && !sameLength {
+			continue
+		}
+		start, err := ParseElapsed(c.StartTime)
+		if err != nil {
+			continue
+		}
+		diff := start - logStart
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= MachineLogStartTolerance && diff < bestDiff {
+			bestID, bestDiff = c.ID, diff
+		}
+	}
+	return bestID, bestID != 0
+}
+
+// DataPointCount returns the number of logged rows
+func (l *MachineLog) DataPointCount() int {
+	return len(l.Measurements)
+}
+
+// MaxLengthM returns the longest logged length
+func (l *MachineLog) MaxLengthM() float64 {
+	maxLength := 0.0
+	for _, m := range l.Measurements {
+		maxLength = math.Max(maxLength, m.LengthM)
+	}
+	return maxLength
+}
+
+// MeasurementSeries returns the logged rows with the logged columns
+func (l *MachineLog) MeasurementSeries() MeasurementSeries {
+	return MeasurementSeries{Format: l.Format, SourceColumns: l.Columns, Measurements: l.Measurements}
+}
+
+// Protocol returns a new protocol holding the log, for logs without a matching PDF protocol
+func (l *MachineLog) Protocol() Protocol {
+	distance := int(math.Round(l.MaxLengthM()))
+	switch l.Format {
+	case FremcoParserName:
+		p := &FremcoProtocol{
+			ProtocolInfo: FremcoProtocolInfo{
+				System:       "SpeedNet-System",
+				DocumentType: "Maschinen-Log",
+				Date:         l.Date,
+				StartTime:    l.StartTime,
+			},
+			Equipment: FremcoEquipment{
+				BlowingDevice: FremcoBlowingDevice{Model: l.Device, ControllerSN: l.ControllerSN},
+			},
+			Measurements: FremcoMeasurements{DataPoints: make([]FremcoDataPoint, 0, len(l.Measurements))},
+			ExportMetadata: FremcoExportMetadata{
+				ParsedAt:       time.Now(),
+				ParserVersion:  machineLogParserVersion,
+				SourceFilename: l.SourceFilename,
+			},
+		}
+		for _, m := range l.Measurements {
+			dp := FremcoDataPoint{
+				LengthM:       m.LengthM,
+				SpeedMMin:     floatValue(m.SpeedMMin),
+				PressureBar:   floatValue(m.PressureBar),
+				TorquePercent: floatValue(m.TorquePercent),
+			}
+			if m.Timestamp != nil {
+				dp.Timestamp = m.Timestamp.Format(MeasurementTimestampLayout)
+			}
+			p.Measurements.DataPoints = append(p.Measurements.DataPoints, dp)
+		}
+		p.Measurements.Summary.Distance = distance
+		p.Measurements.Summary.BlowingTime = l.BlowingTime
+		return p
+	default:
+		p := &JettingProtocol{
+			ProtocolInfo: JettingProtocolInfo{
+				System:       "Jetting System",
+				DocumentType: "Maschinen-Log",
+				Date:         l.Date,
+				StartTime:    l.StartTime,
+			},
+			Measurements: JettingMeasurements{DataPoints: make([]JettingDataPoint, 0, len(l.Measurements))},
+			ExportMetadata: JettingExportMetadata{
+				ParsedAt:       time.Now(),
+				ParserVersion:  machineLogParserVersion,
+				SourceFilename: l.SourceFilename,
+			},
+		}
+		for _, m := range l.Measurements {
+			dp := JettingDataPoint{
+				LengthM:      m.LengthM,
+				TemperatureC: floatValue(m.TemperatureC),
+				ForceN:       floatValue(m.ForceN),
+				PressureBar:  floatValue(m.PressureBar),
+				SpeedMMin:    floatValue(m.SpeedMMin),
+			}
+			if m.Elapsed != nil {
+				dp.TimeDuration = FormatElapsed(*m.Elapsed)
+			}
+			p.Measurements.DataPoints = append(p.Measurements.DataPoints, dp)
+		}
+		if l.Device != "" {
+			p.Equipment.BlowingDevice.Model = &l.Device
+		}
+		if l.ControllerSN != "" {
+			p.Equipment.BlowingDevice.ControllerSN = &l.ControllerSN
+		}
+		p.Measurements.Summary.Distance = &distance
+		if l.BlowingTime != "" {
+			p.Measurements.Summary.BlowingTime = &l.BlowingTime
+		}
+		return p
+	}
+}
@@ -0,0 +1,177 @@
+package simulator
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+const fremcoLogCSV = `Fremco MicroFlow LOG;;;;
+Controller S/N;9911.0027;;;
+Start;17.06.2025 15:29:04;;;
+Length [m];Speed [m/min];Pressure [bar];Torque [%];Time [hh:mm:ss]
+0,00;0,0;10,21;12,5;15:29:04
+1,37;41,2;10,23;14,1;15:29:06
+4,91;53,7;10,25;15,0;15:29:10
+`
+
+const jettingLogCSV = `Jetting Controller
+Seriennummer;JC-4471
+Datum;10.04.2025
+Uhrzeit;08:22
+Länge[m];Lufttemperatur[°C];Schubkraft[N];Einblasdruck[bar];Geschwindigkeit[m/min];Zeit - Dauer[hh:mm:ss]
+0;11,4;3;11,55;0;00:00:00
+0,5;11,4;4;11,55;30,2;00:00:01
+1,1;11,4;5;11,56;36,0;00:00:02
+`
+
+const plumettazLogCSV = "\ufeffPlumettaz;MiniJet 400;;\n" +
+	"Serial number;MJ4-20417;;\n" +
+	"Date;14.06.2025;;\n" +
+	"Start;09:12:05;;\n" +
+	"Time [hh:mm:ss];Distance [m];Speed [m/s];Push force [daN];Air pressure [bar];\n" +
+	"00:00:00;0,0;0,00;0,0;10,2;\n" +
+	"00:00:10;8,5;0,85;2,1;10,3;\n" +
+	"00:00:20;17,9;0,94;2,4;10,3;\n"
+
+func quietLog(t *testing.T) {
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+}
+
+func TestFindLogHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     []string
+		ok        bool
+		line      int
+		separator string
+		fields    []string
+	}{
+		{"semicolon with title", []string{"Fremco MicroFlow LOG;;", "Length [m];Speed [m/min];Torque [%]"},
+			true, 1, ";", []string{"length_m", "speed_m_min", "torque_percent"}},
+		{"units without space", []string{"Länge[m];Schubkraft[N];Einblasdruck[bar]"},
+			true, 0, ";", []string{"length_m", "force_n", "pressure_bar"}},
+		{"tab separated", []string{"Distance (m)\tSpeed (m/s)\tForce (daN)"},
+			true, 0, "\t", []string{"length_m", "speed_m_min", "force_n"}},
+		{"no length column", []string{"Speed [m/min];Pressure [bar];Torque [%]"}, false, 0, "", nil},
+		{"length and one other column", []string{"Length [m];Speed [m/min]"}, false, 0, "", nil},
+		{"plain text", []string{"Einblasprotokoll", "Datum: 17.06.2025"}, false, 0, "", nil},
+	}
+	for _, tt := range tests {
+		layout, ok := findLogHeader(tt.lines)
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if layout.line != tt.line || layout.separator != tt.separator {
+			t.Errorf("%s: line %d separator %q, want %d %q", tt.name, layout.line, layout.separator, tt.line, tt.separator)
+		}
+		for _, field := range tt.fields {
+			if !layout.has(field) {
+				t.Errorf("%s: no %s column in %s", tt.name, field, layout)
+			}
+		}
+	}
+}
+
+func TestDetectMachineLog(t *testing.T) {
+	quietLog(t)
+	tests := []struct {
+		name   string
+		text   string
+		format string
+		ok     bool
+	}{
+		{"fremco device name", fremcoLogCSV, FremcoParserName, true},
+		{"jetting device name", jettingLogCSV, JettingParserName, true},
+		{"fremco by torque column", "Length [m];Speed [m/min];Torque [%]\n1;20;30\n", FremcoParserName, true},
+		{"jetting by force column", "Length [m];Speed [m/min];Force [N]\n1;20;300\n", JettingParserName, true},
+		{"plumettaz log is left to its parser", plumettazLogCSV, "", false},
+		{"no device and no torque or force", "Length [m];Speed [m/min];Pressure [bar]\n1;20;8\n", "", false},
+		{"space separated table", "Length [m] Speed [m/min] Torque [%]\n1 20 30\n", "", false},
+		{"no header", "Fremco MicroFlow LOG\n1;2;3\n", "", false},
+	}
+	for _, tt := range tests {
+		format, ok := DetectMachineLog(tt.text)
+		if format != tt.format || ok != tt.ok {
+			t.Errorf("%s: DetectMachineLog = %q, %v; want %q, %v", tt.name, format, ok, tt.format, tt.ok)
+		}
+	}
+}
+
+func TestParseMachineLog(t *testing.T) {
+	quietLog(t)
+	tests := []struct {
+		name      string
+		text      string
+		format    string
+		serial    string
+		date      string
+		startTime string
+		rows      int
+	}{
+		{"fremco", fremcoLogCSV, FremcoParserName, "9911.0027", "17.06.2025", "15:29:04", 3},
+		{"jetting", jettingLogCSV, JettingParserName, "JC-4471", "10.04.2025", "08:22", 3},
+	}
+	for _, tt := range tests {
+		l, _, err := ParseMachineLog(tt.text, tt.name+".csv", ParseOptions{})
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if l.Format != tt.format || l.ControllerSN != tt.serial || l.Date != tt.date ||
+			l.StartTime != tt.startTime || len(l.Measurements) != tt.rows {
+			t.Errorf("%s: got %s S/N %q %s %s with %d rows", tt.name, l.Format, l.ControllerSN, l.Date, l.StartTime, len(l.Measurements))
+		}
+	}
+
+	_, _, err := ParseMachineLog(plumettazLogCSV, "run.csv", ParseOptions{})
+	if err == nil || !strings.Contains(err.Error(), "uploaded as protocols") {
+		t.Errorf("plumettaz log: err = %v, want hand-off to the plumettaz parser", err)
+	}
+}
+
+func TestMachineLogMatchProtocol(t *testing.T) {
+	length := func(m float64) []Measurement { return []Measurement{{LengthM: 0}, {LengthM: m}} }
+	fremco := &MachineLog{Format: FremcoParserName, ControllerSN: "9911.0027", Date: "17.06.2025", StartTime: "15:29:04", Measurements: length(420)}
+	jetting := &MachineLog{Format: JettingParserName, Date: "10.04.2025", StartTime: "08:22:10", Measurements: length(300)}
+
+	tests := []struct {
+		name       string
+		log        *MachineLog
+		candidates []MachineLogCandidate
+		id         int
+	}{
+		{"same S/N and start", fremco, []MachineLogCandidate{{ID: 1, StartTime: "15:29:00", ControllerSN: "9911.0027"}}, 1},
+		{"S/N with spaces", fremco, []MachineLogCandidate{{ID: 1, StartTime: "15:29:00", ControllerSN: " 9911.0027 "}}, 1},
+		{"other S/N", fremco, []MachineLogCandidate{{ID: 1, StartTime: "15:29:00", ControllerSN: "9328.5155"}}, 0},
+		{"start at the tolerance", fremco, []MachineLogCandidate{{ID: 1, StartTime: "15:39:04", ControllerSN: "9911.0027"}}, 1},
+		{"start beyond the tolerance", fremco, []MachineLogCandidate{{ID: 1, StartTime: "15:39:05", ControllerSN: "9911.0027"}}, 0},
+		{"closest start wins", fremco, []MachineLogCandidate{
+			{ID: 1, StartTime: "15:21:00", ControllerSN: "9911.0027"},
+			{ID: 2, StartTime: "15:30:00", ControllerSN: "9911.0027"},
+		}, 2},
+		{"fremco without S/N never matches by length", &MachineLog{Format: FremcoParserName, Date: "17.06.2025", StartTime: "15:29:04", Measurements: length(420)},
+			[]MachineLogCandidate{{ID: 1, StartTime: "15:29:00", MaxLengthM: 420}}, 0},
+		{"jetting by length", jetting, []MachineLogCandidate{{ID: 3, StartTime: "08:22:00", MaxLengthM: 290}}, 3},
+		{"jetting length at the tolerance", jetting, []MachineLogCandidate{{ID: 3, StartTime: "08:22:00", MaxLengthM: 300 - MachineLogLengthTolerance}}, 3},
+		{"jetting length beyond the tolerance", jetting, []MachineLogCandidate{{ID: 3, StartTime: "08:22:00", MaxLengthM: 284}}, 0},
+		{"jetting candidate with other S/N", jetting, []MachineLogCandidate{{ID: 3, StartTime: "08:22:00", ControllerSN: "JC-1", MaxLengthM: 300}}, 0},
+		{"jetting start beyond the tolerance", jetting, []MachineLogCandidate{{ID: 3, StartTime: "08:40:00", MaxLengthM: 300}}, 0},
+		{"unreadable start", fremco, []MachineLogCandidate{{ID: 1, StartTime: "", ControllerSN: "9911.0027"}}, 0},
+		{"log without start", &MachineLog{Format: FremcoParserName, ControllerSN: "9911.0027"},
+			[]MachineLogCandidate{{ID: 1, StartTime: "15:29:00", ControllerSN: "9911.0027"}}, 0},
+	}
+	for _, tt := range tests {
+		id, found := tt.log.MatchProtocol(tt.candidates)
+		if id != tt.id || found != (tt.id != 0) {
+			t.Errorf("%s: MatchProtocol = %d, %v; want %d", tt.name, id, found, tt.id)
+		}
+	}
+}
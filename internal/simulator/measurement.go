@@ -0,0 +1,259 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Measurement is one measured row of a blowing run in the units shared by all
+// formats. Quantities a machine does not record are nil: Fremco machines log
+// the torque in % of the device maximum, Jetting and Plumettaz machines the
+// push force in N. Elapsed and Timestamp are both filled where the protocol
+// date and start time allow it, whichever of the two was recorded.
+type Measurement struct {
+	Sequence      int            `json:"sequence"` // recording order, from 1
+	LengthM       float64        `json:"length_m"`
+	SpeedMMin     *float64       `json:"speed_m_min,omitempty"`
+	PressureBar   *float64       `json:"pressure_bar,omitempty"`
+	TorquePercent *float64       `json:"torque_percent,omitempty"`
+	ForceN        *float64       `json:"force_n,omitempty"`
+	TemperatureC  *float64       `json:"temperature_c,omitempty"`
+	Elapsed       *time.Duration `json:"-"` // since the first row, as "time_duration" hh:mm:ss
+	Timestamp     *time.Time     `json:"-"` // date and time of day, as "timestamp"
+}
+
+// MeasurementTimestampLayout is the layout of measurement timestamps in JSON and exports
+const MeasurementTimestampLayout = "2006-01-02 15:04:05"
+
+// MarshalJSON writes the elapsed time as "hh:mm:ss" and the timestamp in MeasurementTimestampLayout
+func (m Measurement) MarshalJSON() ([]byte, error) {
+	type fields Measurement
+	out := struct {
+		fields
+		TimeDuration string `json:"time_duration,omitempty"`
+		Timestamp    string `json:"timestamp,omitempty"`
+	}{fields: fields(m)}
+	if m.Elapsed != nil {
+		out.TimeDuration = FormatElapsed(*m.Elapsed)
+	}
+	if m.Timestamp != nil {
+		out.Timestamp = m.Timestamp.Format(MeasurementTimestampLayout)
+	}
+	return json.Marshal(out)
+}
+
+// MeasurementQuantity is a quantity of the measurement model with its unit
+type MeasurementQuantity struct {
+	Field string `json:"field"` // JSON field and protocol_measurements column
+	Name  string `json:"name"`
+	Unit  string `json:"unit"`
+}
+
+// MeasurementQuantities are the quantities of a Measurement in export order
+var MeasurementQuantities = []MeasurementQuantity{
+	{"length_m", "Length", "m"},
+	{"speed_m_min", "Speed", "m/min"},
+	{"pressure_bar", "Pressure", "bar"},
+	{"torque_percent", "Torque", "%"},
+	{"force_n", "Force", "N"},
+	{"temperature_c", "Temperature", "°C"},
+	{"time_duration", "Elapsed", "hh:mm:ss"},
+	{"timestamp", "Time", "yyyy-mm-dd hh:mm:ss"},
+}
+
+// Value returns a numeric quantity by field, false if it was not recorded
+func (m Measurement) Value(field string) (float64, bool) {
+	var v *float64
+	switch field {
+	case "length_m":
+		return m.LengthM, true
+	case "speed_m_min":
+		v = m.SpeedMMin
+	case "pressure_bar":
+		v = m.PressureBar
+	case "torque_percent":
+		v = m.TorquePercent
+	case "force_n":
+		v = m.ForceN
+	case "temperature_c":
+		v = m.TemperatureC
+	}
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+// Text formats a quantity for exports, "" if it was not recorded
+func (m Measurement) Text(field string) string {
+	switch field {
+	case "time_duration":
+		if m.Elapsed != nil {
+			return FormatElapsed(*m.Elapsed)
+		}
+	case "timestamp":
+		if m.Timestamp != nil {
+			return m.Timestamp.Format(MeasurementTimestampLayout)
+		}
+	default:
+		if v, ok := m.Value(field); ok {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// SourceColumn records a column of the exported table and how it was read
+type SourceColumn struct {
+	Name  string `json:"name"`  // header text, e.g. "Push force"
+	Unit  string `json:"unit"`  // unit as exported, e.g. "daN"
+	Field string `json:"field"` // measurement quantity or "clock" for a time of day, empty if the column is not read
+}
+
+// MeasurementSeries is the measured rows of a protocol with the columns they were read from
+type MeasurementSeries struct {
+	Format        string         `json:"format"`
+	SourceColumns []SourceColumn `json:"source_columns"`
+	Measurements  []Measurement  `json:"measurements"`
+}
+
+// Quantities returns the quantities recorded in at least one row, in export order
+func (s MeasurementSeries) Quantities() []MeasurementQuantity {
+	var quantities []MeasurementQuantity
+	for _, q := range MeasurementQuantities {
+		for _, m := range s.Measurements {
+			if m.Text(q.Field) != "" {
+				quantities = append(quantities, q)
+				break
+			}
+		}
+	}
+	return quantities
+}
+
+// WriteCSV writes the rows separated by semicolons, one column per recorded
+// quantity headed by its name and unit ("Force [N]")
+func (s MeasurementSeries) WriteCSV(w io.Writer) error {
+	quantities := s.Quantities()
+	out := csv.NewWriter(w)
+	out.Comma = ';'
+	header := []string{"Sequence"}
+	for _, q := range quantities {
+		header = append(header, q.Name+" ["+q.Unit+"]")
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, m := range s.Measurements {
+		record := []string{strconv.Itoa(m.Sequence)}
+		for _, q := range quantities {
+			record = append(record, m.Text(q.Field))
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// measurementClockTolerance is how far a recorded time of day may lie before
+// the protocol start time and still be on the day of the start, as the start
+// time is often printed to the minute or taken from the file name
+const measurementClockTolerance = 10 * time.Minute
+
+// parseProtocolStart returns the start of a run from the protocol date and start time
+func parseProtocolStart(date, startTime string) (time.Time, bool) {
+	for _, dateLayout := range []string{"02.01.2006", "2006-01-02", "01/02/2006"} {
+		for _, timeLayout := range []string{"15:04:05", "15:04"} {
+			if t, err := time.Parse(dateLayout+" "+timeLayout, date+" "+startTime); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseMeasurementTime reads a recorded time cell as a date and time, or as a time of day
+func parseMeasurementTime(s string) (*time.Time, *time.Duration) {
+	for _, layout := range []string{MeasurementTimestampLayout, "2006-01-02 15:04", "2006-01-02T15:04:05", "02.01.2006 15:04:05", "02.01.2006 15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	if fremcoClockRe.MatchString(s) {
+		// A time of day without seconds is hh:mm, not the mm:ss of an elapsed time
+		if strings.Count(s, ":") == 1 {
+			s += ":00"
+		}
+		if d, err := ParseElapsed(s); err == nil {
+			return nil, &d
+		}
+	}
+	return nil, nil
+}
+
+// completeMeasurementTimes fills the time quantities a format did not record.
+// clocks are recorded times of day (nil entries if not recorded), dated with
+// the day of start; a time of day before the start is on the next day.
+// Without a start, times of day only give the elapsed time. Elapsed times are
+// dated from the start, and timestamps give the time since the first row.
+func completeMeasurementTimes(measurements []Measurement, clocks []*time.Duration, start time.Time, hasStart bool) {
+	day := start.Truncate(24 * time.Hour)
+	var firstClock *time.Duration
+	for i := range measurements {
+		m := &measurements[i]
+		if i < len(clocks) && clocks[i] != nil {
+			clock := *clocks[i]
+			if firstClock == nil {
+				firstClock = &clock
+			}
+			if m.Elapsed == nil {
+				d := clock - *firstClock
+				if d < 0 {
+					d += 24 * time.Hour
+				}
+				m.Elapsed = &d
+			}
+			if hasStart && m.Timestamp == nil {
+				// The recorded clock is exact, the start time may be printed to the minute
+				t := day.Add(clock)
+				if t.Before(start.Add(-measurementClockTolerance)) {
+					t = t.Add(24 * time.Hour)
+				}
+				m.Timestamp = &t
+			}
+		}
+		if hasStart && m.Timestamp == nil && m.Elapsed != nil {
+			t := start.Add(*m.Elapsed)
+			m.Timestamp = &t
+		}
+	}
+
+	var first *time.Time
+	for i := range measurements {
+		m := &measurements[i]
+		if m.Timestamp == nil {
+			continue
+		}
+		if first == nil {
+			first = m.Timestamp
+		}
+		if m.Elapsed == nil {
+			d := m.Timestamp.Sub(*first)
+			m.Elapsed = &d
+		}
+	}
+}
+
+// floatValue returns a recorded value, 0 if it was not recorded
+func floatValue(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,191 @@
+package simulator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeasurementMarshalJSON(t *testing.T) {
+	elapsed := time.Hour + 2*time.Minute + 3*time.Second
+	timestamp := time.Date(2025, 6, 17, 15, 29, 4, 0, time.UTC)
+	tests := []struct {
+		name string
+		m    Measurement
+		want string
+	}{
+		{
+			name: "only length",
+			m:    Measurement{Sequence: 1, LengthM: 0},
+			want: `{"sequence":1,"length_m":0}`,
+		},
+		{
+			// A recorded 0 is kept, a quantity that was not recorded is left out
+			name: "recorded zero",
+			m:    Measurement{Sequence: 2, LengthM: 1.5, SpeedMMin: ptr(0.0), ForceN: ptr(12.5)},
+			want: `{"sequence":2,"length_m":1.5,"speed_m_min":0,"force_n":12.5}`,
+		},
+		{
+			name: "times",
+			m:    Measurement{Sequence: 3, LengthM: 2, TorquePercent: ptr(40.0), Elapsed: &elapsed, Timestamp: &timestamp},
+			want: `{"sequence":3,"length_m":2,"torque_percent":40,"time_duration":"01:02:03","timestamp":"2025-06-17 15:29:04"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasurementSeriesWriteCSV(t *testing.T) {
+	elapsed := 10 * time.Second
+	series := MeasurementSeries{Measurements: []Measurement{
+		{Sequence: 1, LengthM: 0, SpeedMMin: ptr(0.0), ForceN: ptr(3.0)},
+		{Sequence: 2, LengthM: 8.5, SpeedMMin: ptr(51.0), Elapsed: &elapsed},
+	}}
+	var b strings.Builder
+	if err := series.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	// Only recorded quantities get a column; missing values stay empty
+	want := "Sequence;Length [m];Speed [m/min];Force [N];Elapsed [hh:mm:ss]\n" +
+		"1;0;0;3;\n" +
+		"2;8.5;51;;00:00:10\n"
+	if b.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := (MeasurementSeries{}).WriteCSV(&b); err != nil || b.String() != "Sequence\n" {
+		t.Errorf("WriteCSV() of an empty series = %q, %v", b.String(), err)
+	}
+}
+
+func TestParseMeasurementTime(t *testing.T) {
+	tests := []struct {
+		s         string
+		timestamp string // MeasurementTimestampLayout, "" = none
+		clock     time.Duration
+		isClock   bool
+	}{
+		{"2025-06-17 15:29:04", "2025-06-17 15:29:04", 0, false},
+		{"2025-06-17 15:29", "2025-06-17 15:29:00", 0, false},
+		{"2025-06-17T15:29:04", "2025-06-17 15:29:04", 0, false},
+		{"17.06.2025 15:29:04", "2025-06-17 15:29:04", 0, false},
+		{"17.06.2025 15:29", "2025-06-17 15:29:00", 0, false},
+		{"15:29:04", "", 15*time.Hour + 29*time.Minute + 4*time.Second, true},
+		{"7:05", "", 7*time.Hour + 5*time.Minute, true},
+		{"", "", 0, false},
+		{"15.29", "", 0, false},
+		{"2025-06-17", "", 0, false},
+	}
+	for _, tt := range tests {
+		timestamp, clock := parseMeasurementTime(tt.s)
+		got := ""
+		if timestamp != nil {
+			got = timestamp.Format(MeasurementTimestampLayout)
+		}
+		if got != tt.timestamp || (clock != nil) != tt.isClock || (clock != nil && *clock != tt.clock) {
+			t.Errorf("parseMeasurementTime(%q) = %q, %v, want %q, %v", tt.s, got, clock, tt.timestamp, tt.clock)
+		}
+	}
+}
+
+func TestCompleteMeasurementTimes(t *testing.T) {
+	clock := func(h, m, s int) *time.Duration {
+		d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+		return &d
+	}
+	format := func(measurements []Measurement) (elapsed, timestamps []string) {
+		for _, m := range measurements {
+			elapsed = append(elapsed, m.Text("time_duration"))
+			timestamps = append(timestamps, m.Text("timestamp"))
+		}
+		return elapsed, timestamps
+	}
+	start := time.Date(2025, 6, 17, 23, 58, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		elapsed    []*time.Duration
+		clocks     []*time.Duration
+		start      time.Time
+		hasStart   bool
+		wantTimes  []string
+		wantStamps []string
+	}{
+		{
+			// The first clock is before the start time printed to the minute
+			name:       "clocks across midnight",
+			clocks:     []*time.Duration{clock(23, 57, 30), clock(23, 59, 50), clock(0, 0, 20)},
+			start:      start,
+			hasStart:   true,
+			wantTimes:  []string{"00:00:00", "00:02:20", "00:02:50"},
+			wantStamps: []string{"2025-06-17 23:57:30", "2025-06-17 23:59:50", "2025-06-18 00:00:20"},
+		},
+		{
+			name:       "clocks more than the tolerance before the start",
+			clocks:     []*time.Duration{clock(23, 40, 0)},
+			start:      start,
+			hasStart:   true,
+			wantTimes:  []string{"00:00:00"},
+			wantStamps: []string{"2025-06-18 23:40:00"},
+		},
+		{
+			name:       "clocks without start",
+			clocks:     []*time.Duration{clock(8, 22, 0), nil, clock(8, 22, 10)},
+			wantTimes:  []string{"00:00:00", "", "00:00:10"},
+			wantStamps: []string{"", "", ""},
+		},
+		{
+			name:       "elapsed times dated from the start",
+			elapsed:    []*time.Duration{clock(0, 0, 0), clock(0, 1, 30)},
+			start:      start,
+			hasStart:   true,
+			wantTimes:  []string{"00:00:00", "00:01:30"},
+			wantStamps: []string{"2025-06-17 23:58:00", "2025-06-17 23:59:30"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := max(len(tt.elapsed), len(tt.clocks))
+			measurements := make([]Measurement, n)
+			for i := range measurements {
+				measurements[i].Sequence = i + 1
+				if i < len(tt.elapsed) {
+					measurements[i].Elapsed = tt.elapsed[i]
+				}
+			}
+			completeMeasurementTimes(measurements, tt.clocks, tt.start, tt.hasStart)
+			elapsed, stamps := format(measurements)
+			if strings.Join(elapsed, " ") != strings.Join(tt.wantTimes, " ") {
+				t.Errorf("elapsed %q, want %q", elapsed, tt.wantTimes)
+			}
+			if strings.Join(stamps, " ") != strings.Join(tt.wantStamps, " ") {
+				t.Errorf("timestamps %q, want %q", stamps, tt.wantStamps)
+			}
+		})
+	}
+
+	// Timestamps give the time since the first timestamped row
+	stamp := func(s string) *time.Time {
+		t, _ := parseMeasurementTime(s)
+		return t
+	}
+	measurements := []Measurement{
+		{Timestamp: stamp("2025-06-17 10:51:00")},
+		{Timestamp: stamp("2025-06-17 10:53:05")},
+	}
+	completeMeasurementTimes(measurements, nil, time.Time{}, false)
+	if elapsed, _ := format(measurements); strings.Join(elapsed, " ") != "00:00:00 00:02:05" {
+		t.Errorf("elapsed from timestamps %q", elapsed)
+	}
+}
@@ -0,0 +1,264 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Defaults and limits of the Monte Carlo simulation
+const (
+	defaultMonteCarloRuns     = 200
+	maxMonteCarloRuns         = 2000
+	defaultFrictionSpread     = 0.2  // relative std. deviation when no calibration is known
+	defaultOvalityPercent     = 2.0  // mean duct ovality in %
+	defaultOvalitySpread      = 1.0  // std. deviation of the ovality in %
+	maxOvalityPercent         = 15.0 // beyond this a duct counts as crushed
+	defaultAmbientTemperature = 15.0 // °C
+	defaultTemperatureSpread  = 5.0  // °C
+	referenceTemperatureC     = 20.0 // temperature of the nominal cable stiffness
+	stiffnessTemperatureCoeff = 0.02 // relative stiffness loss of the PE sheath per °C
+	envelopeStepM             = 10.0
+
+	maxMonteCarloSteps = 2000000 // simulated steps of all runs together
+)
+
+// Distribution is a normal distribution truncated to [Min, Max]. A zero Min
+// or Max leaves that side of the range open.
+type Distribution struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// UncertaintyParams describes the uncertain inputs of a Monte Carlo run.
+// Zero distributions are replaced by defaults derived from the base input.
+type UncertaintyParams struct {
+	Friction       Distribution `json:"friction"`        // cable/duct friction coefficient
+	OvalityPercent Distribution `json:"ovality_percent"` // reduction of the duct inner diameter in %
+	TemperatureC   Distribution `json:"temperature_c"`   // ambient temperature, softens the cable sheath
+}
+
+// MonteCarloRequest runs a base simulation many times with sampled inputs
+type MonteCarloRequest struct {
+	Base        SimulationInput   `json:"base"`
+	Uncertainty UncertaintyParams `json:"uncertainty"`
+	Runs        int               `json:"runs"` // 0 = default
+	Seed        int64             `json:"seed"` // 0 = random
+}
+
+// EnvelopePoint contains the speed percentiles of all runs at one length
+type EnvelopePoint struct {
+	LengthM      float64 `json:"length_m"`
+	SpeedP10MMin float64 `json:"speed_p10_m_min"`
+	SpeedP50MMin float64 `json:"speed_p50_m_min"`
+	SpeedP90MMin float64 `json:"speed_p90_m_min"`
+	ReachedShare float64 `json:"reached_share"` // share of runs that got at least this far
+}
+
+// MonteCarloResult contains the distribution of the reachable distance
+type MonteCarloResult struct {
+	Runs               int               `json:"runs"`
+	Seed               int64             `json:"seed"`
+	SectionLengthM     float64           `json:"section_length_m"`
+	P10DistanceM       float64           `json:"p10_distance_m"` // 90% of the runs reach at least this distance
+	P50DistanceM       float64           `json:"p50_distance_m"`
+	P90DistanceM       float64           `json:"p90_distance_m"`
+	ReachedProbability float64           `json:"reached_probability"`
+	Uncertainty        UncertaintyParams `json:"uncertainty"` // distributions actually sampled
+	Envelope           []EnvelopePoint   `json:"envelope"`
+}
+
+// sample draws a value from the truncated normal distribution
+func (d Distribution) sample(rng *rand.Rand) float64 {
+	for i := 0; i < 100; i++ {
+		v := d.Mean + rng.NormFloat64()*d.StdDev
+		if d.contains(v) {
+			return v
+		}
+	}
+	return d.clamp(d.Mean)
+}
+
+// contains reports whether v lies within the bounds that are set
+func (d Distribution) contains(v float64) bool {
+	return (d.Min == 0 || v >= d.Min) && (d.Max == 0 || v <= d.Max)
+}
+
+// clamp limits v to the bounds that are set
+func (d Distribution) clamp(v float64) float64 {
+	if d.Min != 0 {
+		v = math.Max(v, d.Min)
+	}
+	if d.Max != 0 {
+		v = math.Min(v, d.Max)
+	}
+	return v
+}
+
+// validate rejects a negative spread and a range whose Min lies above its Max
+func (d Distribution) validate(name string) error {
+	if d.StdDev < 0 {
+		return fmt.Errorf("%s: std. deviation must not be negative", name)
+	}
+	if d.Min != 0 && d.Max != 0 && d.Min > d.Max {
+		return fmt.Errorf("%s: min %g is above max %g", name, d.Min, d.Max)
+	}
+	return nil
+}
+
+func (d Distribution) isZero() bool {
+	return d == Distribution{}
+}
+
+// withDefaults fills unset distributions from the base input
+func (u UncertaintyParams) withDefaults(in SimulationInput) UncertaintyParams {
+	if u.Friction.isZero() {
+		mean := in.FrictionCoefficient
+		if mean <= 0 {
+			mean = DefaultFrictionCoefficient(in.Duct.InnerWall, in.Cable.Lubricant)
+		}
+		u.Friction = Distribution{Mean: round(mean, 4), StdDev: round(mean*defaultFrictionSpread, 4)}
+	}
+	if u.Friction.Min == 0 {
+		u.Friction.Min = minFitFriction
+	}
+	if u.Friction.Max == 0 {
+		u.Friction.Max = maxFitFriction
+	}
+	if u.OvalityPercent.isZero() {
+		u.OvalityPercent = Distribution{Mean: defaultOvalityPercent, StdDev: defaultOvalitySpread, Max: maxOvalityPercent}
+	}
+	if u.TemperatureC.isZero() {
+		mean := in.AmbientTemperatureC
+		if mean == 0 {
+			mean = defaultAmbientTemperature
+		}
+		u.TemperatureC = Distribution{Mean: mean, StdDev: defaultTemperatureSpread}
+	}
+	return u
+}
+
+// validate checks every distribution
+func (u UncertaintyParams) validate() error {
+	if err := u.Friction.validate("friction"); err != nil {
+		return err
+	}
+	if err := u.OvalityPercent.validate("ovality"); err != nil {
+		return err
+	}
+	return u.TemperatureC.validate("temperature")
+}
+
+// MonteCarlo simulates the base input with sampled friction, duct ovality and
+// temperature and returns the P10/P50/P90 reachable distance plus speed
+// envelopes along the section. Ovality narrows the effective duct inner
+// diameter; temperature scales the cable stiffness of the PE sheath.
+// It stops with the context's error once ctx is done.
+func MonteCarlo(ctx context.Context, req MonteCarloRequest) (*MonteCarloResult, error) {
+	if err := req.Base.Validate(); err != nil {
+		return nil, err
+	}
+	runs := req.Runs
+	if runs <= 0 {
+		runs = defaultMonteCarloRuns
+	}
+	if runs > maxMonteCarloRuns {
+		return nil, fmt.Errorf("at most %d runs are allowed", maxMonteCarloRuns)
+	}
+	if steps := int(math.Ceil(req.Base.SectionLength() / req.Base.step())); runs*steps > maxMonteCarloSteps {
+		return nil, fmt.Errorf("%d runs of %d steps exceed %d simulated steps, use fewer runs or a larger step", runs, steps, maxMonteCarloSteps)
+	}
+	uncertainty := req.Uncertainty.withDefaults(req.Base)
+	if err := uncertainty.validate(); err != nil {
+		return nil, err
+	}
+	seed := req.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	length := req.Base.SectionLength()
+	envelopeSize := int(length/envelopeStepM) + 1
+	speeds := make([][]float64, envelopeSize)
+	distances := make([]float64, 0, runs)
+	reached := 0
+
+	stiffness := req.Base.Cable.StiffnessNm2
+	if stiffness <= 0 {
+		stiffness = EstimateCableStiffness(req.Base.Cable.DiameterMM)
+	}
+	for run := 0; run < runs; run++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		in := req.Base
+		in.FrictionCoefficient = uncertainty.Friction.sample(rng)
+		ovality := math.Max(uncertainty.OvalityPercent.sample(rng), 0)
+		in.Duct.InnerDiameterMM *= 1 - ovality/100
+		temperature := uncertainty.TemperatureC.sample(rng)
+		in.AmbientTemperatureC = round(temperature, 1)
+		in.Cable.StiffnessNm2 = stiffness * math.Exp(-stiffnessTemperatureCoeff*(temperature-referenceTemperatureC))
+
+		sim, err := Simulate(in)
+		if err != nil {
+			// The sampled ovality left no room for the cable
+			sim = &SimulationResult{}
+		}
+		distances = append(distances, sim.MaxDistanceM)
+		if sim.Reached {
+			reached++
+		}
+		for i := range speeds {
+			speed, _ := predictedAt(sim, float64(i)*envelopeStepM, 0)
+			speeds[i] = append(speeds[i], speed)
+		}
+	}
+
+	result := &MonteCarloResult{
+		Runs:               runs,
+		Seed:               seed,
+		SectionLengthM:     length,
+		P10DistanceM:       round(percentile(distances, 10), 1),
+		P50DistanceM:       round(percentile(distances, 50), 1),
+		P90DistanceM:       round(percentile(distances, 90), 1),
+		ReachedProbability: round(float64(reached)/float64(runs), 3),
+		Uncertainty:        uncertainty,
+		Envelope:           make([]EnvelopePoint, 0, envelopeSize),
+	}
+	for i, s := range speeds {
+		at := float64(i) * envelopeStepM
+		count := 0
+		for _, d := range distances {
+			if d >= at {
+				count++
+			}
+		}
+		result.Envelope = append(result.Envelope, EnvelopePoint{
+			LengthM:      at,
+			SpeedP10MMin: round(percentile(s, 10), 1),
+			SpeedP50MMin: round(percentile(s, 50), 1),
+			SpeedP90MMin: round(percentile(s, 90), 1),
+			ReachedShare: round(float64(count)/float64(runs), 3),
+		})
+	}
+	return result, nil
+}
+
+// percentile returns the p-th percentile with linear interpolation
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	pos := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (pos-float64(lower))*(sorted[upper]-sorted[lower])
+}
@@ -0,0 +1,141 @@
+package simulator
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDistributionSample(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Distribution
+		min, max float64 // expected range of the draws
+	}{
+		{"unbounded", Distribution{Mean: 0.2, StdDev: 0.05}, math.Inf(-1), math.Inf(1)},
+		{"both bounds", Distribution{Mean: 0.2, StdDev: 0.05, Min: 0.15, Max: 0.25}, 0.15, 0.25},
+		{"only min", Distribution{Mean: 0.2, StdDev: 0.05, Min: 0.1}, 0.1, math.Inf(1)},
+		{"only max", Distribution{Mean: 2, StdDev: 1, Max: 15}, math.Inf(-1), 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			sum := 0.0
+			const n = 1000
+			for i := 0; i < n; i++ {
+				v := tt.d.sample(rng)
+				if v < tt.min || v > tt.max {
+					t.Fatalf("sample() = %v outside [%v, %v]", v, tt.min, tt.max)
+				}
+				sum += v
+			}
+			// A half-open range barely truncates a distribution centred inside it
+			if mean := sum / n; math.Abs(mean-tt.d.Mean) > tt.d.StdDev/2 {
+				t.Errorf("mean of %d draws = %.3f, want about %.3f", n, mean, tt.d.Mean)
+			}
+		})
+	}
+}
+
+func TestDistributionSampleOutsideRange(t *testing.T) {
+	// A mean far outside the range falls back to the nearest bound
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		d    Distribution
+		want float64
+	}{
+		{Distribution{Mean: 0.05, StdDev: 0.001, Min: 0.1}, 0.1},
+		{Distribution{Mean: 0.9, StdDev: 0.001, Max: 0.8}, 0.8},
+		{Distribution{Mean: 0.9, StdDev: 0.001, Min: 0.1, Max: 0.8}, 0.8},
+	}
+	for _, tt := range tests {
+		if got := tt.d.sample(rng); got != tt.want {
+			t.Errorf("%+v.sample() = %v, want %v", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestUncertaintyWithDefaults(t *testing.T) {
+	in := SimulationInput{FrictionCoefficient: 0.2}
+
+	u := UncertaintyParams{}.withDefaults(in)
+	if u.Friction.Mean != 0.2 || u.Friction.Min != minFitFriction || u.Friction.Max != maxFitFriction {
+		t.Errorf("default friction = %+v", u.Friction)
+	}
+	if u.OvalityPercent.Max != maxOvalityPercent || u.TemperatureC.Mean != defaultAmbientTemperature {
+		t.Errorf("default ovality %+v, temperature %+v", u.OvalityPercent, u.TemperatureC)
+	}
+
+	// A half-set friction range keeps its bound and gets the fit limit on the other side
+	u = UncertaintyParams{Friction: Distribution{Mean: 0.2, StdDev: 0.05, Min: 0.1}}.withDefaults(in)
+	if u.Friction.Min != 0.1 || u.Friction.Max != maxFitFriction {
+		t.Errorf("friction with only min = %+v", u.Friction)
+	}
+	u = UncertaintyParams{Friction: Distribution{Mean: 0.2, StdDev: 0.05, Max: 0.3}}.withDefaults(in)
+	if u.Friction.Min != minFitFriction || u.Friction.Max != 0.3 {
+		t.Errorf("friction with only max = %+v", u.Friction)
+	}
+}
+
+func TestMonteCarloRejectsInvalidDistributions(t *testing.T) {
+	tests := []struct {
+		name string
+		u    UncertaintyParams
+		want string
+	}{
+		{"min above max", UncertaintyParams{Friction: Distribution{Mean: 0.2, StdDev: 0.05, Min: 0.3, Max: 0.1}}, "friction: min"},
+		{"min above default max", UncertaintyParams{Friction: Distribution{Mean: 0.9, StdDev: 0.05, Min: 0.9}}, "friction: min"},
+		{"negative spread", UncertaintyParams{TemperatureC: Distribution{Mean: 10, StdDev: -1}}, "temperature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := MonteCarloRequest{Base: testInput(), Uncertainty: tt.u, Runs: 5, Seed: 1}
+			_, err := MonteCarlo(context.Background(), req)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("MonteCarlo() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonteCarloHalfBoundedFriction(t *testing.T) {
+	// Only Min set used to reject every draw and run with friction 0, i.e.
+	// the default friction of the duct. With the other inputs fixed every run
+	// must match a plain simulation at the mean friction.
+	req := MonteCarloRequest{
+		Base: testInput(),
+		Uncertainty: UncertaintyParams{
+			Friction:       Distribution{Mean: 0.3, StdDev: 1e-9, Min: 0.1},
+			OvalityPercent: Distribution{Mean: 1e-9},
+			TemperatureC:   Distribution{Mean: referenceTemperatureC},
+		},
+		Runs: 5,
+		Seed: 1,
+	}
+	result, err := MonteCarlo(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := testInput()
+	in.FrictionCoefficient = 0.3
+	sim, err := Simulate(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.P10DistanceM != sim.MaxDistanceM || result.P90DistanceM != sim.MaxDistanceM {
+		t.Errorf("P10/P90 distance = %.1f/%.1f m, want %.1f m", result.P10DistanceM, result.P90DistanceM, sim.MaxDistanceM)
+	}
+}
+
+// testInput is a 7x1,5 duct with a 2.5 mm cable that stalls before its end
+func testInput() SimulationInput {
+	return SimulationInput{
+		Duct:                DuctParams{InnerDiameterMM: 4, InnerWall: "Glatt", LengthM: 5000},
+		Cable:               CableParams{DiameterMM: 2.5},
+		PushForceN:          100,
+		FrictionCoefficient: 0.2,
+	}
+}
@@ -0,0 +1,155 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Protocols print numbers in German ("2,4mm", "1.520 m") and English notation
+// ("10.1", "1,234.5"). The decimal separator is told apart from thousands
+// separators by position and count:
+//
+//   - both "." and "," present: the last one is the decimal separator ("1.234,5", "1,234.5")
+//   - one separator repeated: thousands separators ("1.234.567")
+//   - one separator once: decimal separator ("2,4", "10.1"), except in counts and
+//     meter readings, where it separates thousands if three digits follow and
+//     one to three digits without leading zero precede it ("1.520 m", not "0,123")
+//
+// Apostrophes and no-break spaces always separate thousands ("1'520"). A
+// number never ends with a separator ("12." is rejected).
+
+// numberRe matches a number with decimal and thousands separators
+var numberRe = regexp.MustCompile(`[+-]?\d[\d.,'\x{00a0}\x{202f}]*`)
+
+// unitRe matches the unit printed after a number ("mm", "°C", "%RH", "m/min")
+var unitRe = regexp.MustCompile(`^[\p{L}°%/µ²³ ]*$`)
+
+// numberSeparators are the characters that may end a matched number without belonging to it
+const numberSeparators = ".,'\u00a0\u202f"
+
+// ParseDecimal reads a number without unit in comma or dot notation
+func ParseDecimal(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || numberRe.FindString(s) != s || strings.TrimRight(s, numberSeparators) != s {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return parseNumber(s, false)
+}
+
+// ParseQuantity reads a number followed by an optional unit, as in "2,4mm",
+// "11°C" or "0.0 %", and returns the value and the unit
+func ParseQuantity(s string) (float64, string, error) {
+	number, unit, err := splitQuantity(s)
+	if err != nil {
+		return 0, "", err
+	}
+	v, err := parseNumber(number, false)
+	return v, unit, err
+}
+
+// ParseCount reads a whole number such as a fiber count, meter reading or
+// section length with an optional unit ("48", "1.520 m", "282 m"); a single
+// separator followed by three digits separates thousands
+func ParseCount(s string) (int, error) {
+	number, _, err := splitQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	v, err := parseNumber(number, true)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Round(v)), nil
+}
+
+// splitQuantity splits a quantity into its number and unit
+func splitQuantity(s string) (number, unit string, err error) {
+	s = strings.TrimSpace(s)
+	loc := numberRe.FindStringIndex(s)
+	if loc == nil || loc[0] != 0 {
+		return "", "", fmt.Errorf("invalid number %q", s)
+	}
+	number = strings.TrimRight(s[:loc[1]], numberSeparators)
+	unit = strings.TrimSpace(s[len(number):])
+	if !unitRe.MatchString(unit) {
+		return "", "", fmt.Errorf("invalid number %q: unexpected %q after %q", s, unit, number)
+	}
+	return number, unit, nil
+}
+
+// firstNumber reads the first number in a text such as "ca. 14 °C" or "Ø 6,5 mm"
+func firstNumber(text string, count bool) (float64, bool) {
+	number := strings.TrimRight(numberRe.FindString(text), numberSeparators)
+	if number == "" {
+		return 0, false
+	}
+	v, err := parseNumber(number, count)
+	return v, err == nil
+}
+
+// parseNumber converts a matched number to float64. In counts a single
+// separator followed by three digits is a thousands separator.
+func parseNumber(number string, count bool) (float64, error) {
+	s := number
+	for _, group := range []string{"'", "\u00a0", "\u202f"} {
+		s = strings.ReplaceAll(s, group, "")
+	}
+
+	dots, commas := strings.Count(s, "."), strings.Count(s, ",")
+	var decimal, group string
+	switch {
+	case dots > 0 && commas > 0:
+		decimal, group = ".", ","
+		if strings.LastIndex(s, ",") > strings.LastIndex(s, ".") {
+			decimal, group = ",", "."
+		}
+	case dots > 1:
+		group = "."
+	case commas > 1:
+		group = ","
+	case dots == 1:
+		decimal = "."
+	case commas == 1:
+		decimal = ","
+	}
+	if count && group == "" && decimal != "" && len(s)-strings.Index(s, decimal)-1 == 3 &&
+		isThousandsLead(strings.TrimLeft(s[:strings.Index(s, decimal)], "+-")) {
+		decimal, group = "", decimal
+	}
+
+	whole, fraction := s, ""
+	if decimal != "" {
+		i := strings.LastIndex(s, decimal)
+		whole, fraction = s[:i], s[i+1:]
+	}
+	if group != "" {
+		// The first group has one to three digits, every other group three ("1.234.567")
+		groups := strings.Split(strings.TrimLeft(whole, "+-"), group)
+		if !isThousandsLead(groups[0]) {
+			return 0, fmt.Errorf("invalid number %q: misplaced thousands separator", number)
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return 0, fmt.Errorf("invalid number %q: misplaced thousands separator", number)
+			}
+		}
+		whole = strings.ReplaceAll(whole, group, "")
+	}
+	if fraction != "" {
+		whole += "." + fraction
+	}
+	v, err := strconv.ParseFloat(whole, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", number)
+	}
+	return v, nil
+}
+
+// isThousandsLead reports whether digits can be the first group of a number
+// with thousands separators: one to three digits without leading zero
+func isThousandsLead(digits string) bool {
+	return len(digits) >= 1 && len(digits) <= 3 && digits[0] != '0'
+}
@@ -0,0 +1,84 @@
+package simulator
+
+import "testing"
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"2,4", 2.4, true},
+		{"10.1", 10.1, true},
+		{"-2.5", -2.5, true},
+		{"1.234,5", 1234.5, true},
+		{"1,234.5", 1234.5, true},
+		{"1.234.567", 1234567, true},
+		{"1'520", 1520, true},
+		{"0,123", 0.123, true},
+		{"1.520", 1.52, true},
+		{"12.", 0, false},
+		{"12,", 0, false},
+		{"1.23.456", 0, false},
+		{"0.123.456", 0, false},
+		{"2,4mm", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %v, %v; want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		unit string
+		ok   bool
+	}{
+		{"2,4mm", 2.4, "mm", true},
+		{"11°C", 11, "°C", true},
+		{"0.0 %", 0, "%", true},
+		{"12,5 m/min", 12.5, "m/min", true},
+		{"12", 12, "", true},
+		{"12.", 0, "", false},
+		{"12. m", 0, "", false},
+		{"ca. 14 °C", 0, "", false},
+		{"10 bar (max)", 0, "", false},
+	}
+	for _, tt := range tests {
+		got, unit, err := ParseQuantity(tt.in)
+		if (err == nil) != tt.ok || got != tt.want || unit != tt.unit {
+			t.Errorf("ParseQuantity(%q) = %v, %q, %v; want %v, %q, ok %v", tt.in, got, unit, err, tt.want, tt.unit, tt.ok)
+		}
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"48", 48, true},
+		{"1.520 m", 1520, true},
+		{"1,520", 1520, true},
+		{"282 m", 282, true},
+		{"1.234.567", 1234567, true},
+		{"0,123", 0, true},
+		{"1234.567", 1235, true},
+		{"2,4", 2, true},
+		{"0.123.456", 0, false},
+		{"12.", 0, false},
+		{"m 12", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseCount(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseCount(%q) = %v, %v; want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package simulator
 
 import (
+	"fmt"
 	"log"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -30,7 +32,7 @@ type JettingMeasurement struct {
 // ExtractFremcoMetadata parses normalized Fremco text and returns a map of metadata fields.
 func ExtractFremcoMetadata(normalized string) map[string]string {
 	meta := make(map[string]string)
-	lines := strings.Split(normalized, "\n")
+	lines := strings.Split(translateFremcoLabels(normalized), "\n")
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "Bauvorhaben Nr.") {
@@ -127,55 +129,165 @@ func ExtractFremcoMetadata(normalized string) map[string]string {
 // ParseFremcoSimple parses normalized Fremco TXT and returns a slice of SimpleMeasurement.
 // It expects the table header to start with "Streckenlänge" and each row to have:
 // Length [m], Speed [m/min], Pressure [bar], Torque [%], DateTime [hh:mm:ss]
+// Page footers, the page header and the column header repeated on every page are skipped.
+// English exports ("Length [m]", "Speed [m/min]", ...) are read the same way.
 func ParseFremcoSimple(normalized string) []SimpleMeasurement {
+	return parseFremcoTable(translateFremcoLabels(normalized), nil)
+}
+
+// fremcoTableFields names the numeric columns of a Fremco table row in diagnostics
+var fremcoTableFields = []string{"length_m", "speed_m_min", "pressure_bar", "torque_percent"}
+
+// fremcoTableColumns is the column count of the table: the numeric columns and the time
+var fremcoTableColumns = len(fremcoTableFields) + 1
+
+// fremcoClockRe matches a time of day as printed in the Uhrzeit column
+var fremcoClockRe = regexp.MustCompile(`^\d{1,2}:\d{2}(?::\d{2})?$`)
+
+// fremcoPageRe matches the footer printed at the bottom of every page ("Seite 2 von 11")
+var fremcoPageRe = regexp.MustCompile(`(?:Seite|Page)\s+(\d+)\s*(?:von|of|/)\s*(\d+)`)
+
+// fremcoUnitRe matches a column unit in the table header ("[m/min]")
+var fremcoUnitRe = regexp.MustCompile(`\[[^\]]*\]`)
+
+// isFremcoTableHeader reports whether a line is the column header of the measurement table
+func isFremcoTableHeader(line string) bool {
+	return strings.HasPrefix(line, "Länge") || strings.HasPrefix(line, "Streckenlänge")
+}
+
+// parseFremcoTable parses the measurement table, recording unreadable cells.
+// Rows with an unreadable number are dropped rather than stored as 0.
+//
+// Long runs span several pages. Every page ends with a footer ("Seite 2 von 11")
+// and the next one starts with the page header and the repeated column header.
+// The columns of every page must match the first page; rows of a page whose
+// column count or units differ are dropped. A row repeated across a page break
+// is read once.
+func parseFremcoTable(normalized string, diag *parseLog) []SimpleMeasurement {
 	lines := strings.Split(normalized, "\n")
 	var measurements []SimpleMeasurement
+	var units []string // column units of the first page
 	inTable := false
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	skipPage := false   // the columns of this page differ from the first page
+	pageBreak := false  // after a page footer, until the first row of the next page
+	pageHeader := false // after a page footer, until the column header or the first row
+	page, pages := 0, 0
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
 		if line == "" {
 			continue
 		}
-		// Detect start of table (accept both German and English headers)
-		if strings.HasPrefix(line, "Länge") || strings.HasPrefix(line, "Streckenlänge") {
-			inTable = true
-			continue
-		}
-		// Skip headers inside the table
-		if strings.HasPrefix(line, "Lufttemperatur") ||
+		// Page footer, sometimes printed on the same line as the last row
+		footer := fremcoPageRe.FindStringSubmatchIndex(line)
+		if footer != nil {
+			n, _ := strconv.Atoi(line[footer[2]:footer[3]])
+			total, _ := strconv.Atoi(line[footer[4]:footer[5]])
+			if n != page+1 {
+				diag.warn(i+1, "page", line[footer[0]:footer[1]], fmt.Sprintf("page %d follows page %d, rows may be missing", n, page))
+			}
+			page, pages = n, total
+			line = strings.TrimSpace(line[:footer[0]])
+		}
+		switch {
+		case line == "":
+			// Only the page footer was on this line
+		case isFremcoTableHeader(line):
+			// Start of table or the header repeated on every page (accept both German and English headers)
+			headerUnits := fremcoUnitRe.FindAllString(line, -1)
+			// Some exports print the units on the line below the column names
+			if headerUnits == nil && i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "[") {
+				i++
+				headerUnits = fremcoUnitRe.FindAllString(lines[i], -1)
+			}
+			switch {
+			case headerUnits != nil && len(headerUnits) != fremcoTableColumns:
+				diag.fail(i+1, "table_header", line, fmt.Sprintf("expected %d columns, found %d; rows are skipped until the next header", fremcoTableColumns, len(headerUnits)))
+				skipPage = true
+			case units != nil && headerUnits != nil && strings.Join(headerUnits, " ") != strings.Join(units, " "):
+				diag.fail(i+1, "table_header", line, fmt.Sprintf("units %s differ from the first page %s; rows are skipped until the next header",
+					strings.Join(headerUnits, " "), strings.Join(units, " ")))
+				skipPage = true
+			default:
+				if units == nil {
+					units = headerUnits
+				}
+				skipPage = false
+			}
+			inTable, pageHeader = true, false
+		case line == "Zusammenfassung" || line == "Bemerkungen":
+			// The table ends at a following form section
+			inTable = false
+		case strings.HasPrefix(line, "Lufttemperatur") ||
 			strings.HasPrefix(line, "Schubkraft") ||
 			strings.HasPrefix(line, "Einblasdruck") ||
 			strings.HasPrefix(line, "Geschwindigkeit") ||
 			strings.HasPrefix(line, "Zeit - Dauer") ||
-			strings.HasPrefix(line, "[m]") || strings.HasPrefix(line, "[°C]") {
-			continue
-		}
-		if inTable {
+			strings.HasPrefix(line, "[m]") || strings.HasPrefix(line, "[°C]"):
+			// Skip headers inside the table
+		case inTable && !skipPage:
 			fields := strings.Fields(line)
-			// Accept rows with at least 5 columns: length, speed, pressure, torque, time
-			if len(fields) >= 5 {
-				length, _ := strconv.ParseFloat(fields[0], 64)
-				speed, _ := strconv.ParseFloat(fields[1], 64)
-				pressure, _ := strconv.ParseFloat(fields[2], 64)
-				torque, _ := strconv.ParseFloat(fields[3], 64)
-				time := fields[4]
-				// If time field is split (date + time), join them
-				if len(fields) > 5 && isDateTime(fields[4]+" "+fields[5]) {
-					time = fields[4] + " " + fields[5]
-				}
-				measurements = append(measurements, SimpleMeasurement{
-					Length:   length,
-					Speed:    speed,
-					Pressure: pressure,
-					Torque:   torque,
-					Time:     time,
-				})
+			// Page headers and other text lines do not start with a number
+			if !strings.ContainsAny(fields[0], "0123456789") {
+				break
+			}
+			var rowLog parseLog
+			row, ok := parseFremcoRow(&rowLog, i+1, fields)
+			// Between a footer and the next header, lines that are not rows belong to the page header
+			if !ok && pageHeader {
+				break
+			}
+			diag.merge(&rowLog)
+			if !ok {
+				break
 			}
+			// Some exports repeat the last row of a page at the top of the next one
+			if pageBreak && len(measurements) > 0 && measurements[len(measurements)-1] == row {
+				pageBreak, pageHeader = false, false
+				break
+			}
+			pageBreak, pageHeader = false, false
+			measurements = append(measurements, row)
+		}
+		if footer != nil && inTable {
+			pageBreak, pageHeader = true, true
 		}
 	}
+	if pages > 0 && page != pages {
+		diag.warn(0, "page", fmt.Sprintf("Seite %d von %d", page, pages), "last page is missing, rows may be missing")
+	}
 	return measurements
 }
 
+// parseFremcoRow reads one table row: length, speed, pressure, torque and the
+// time, which may be split into date and time of day
+func parseFremcoRow(diag *parseLog, line int, fields []string) (SimpleMeasurement, bool) {
+	var time string
+	switch {
+	case len(fields) == fremcoTableColumns:
+		time = fields[fremcoTableColumns-1]
+		if !fremcoClockRe.MatchString(time) {
+			diag.warn(line, "timestamp", time, "not a date and time or time of day")
+		}
+	// If time field is split (date + time), join them
+	case len(fields) == fremcoTableColumns+1 && isDateTime(fields[fremcoTableColumns-1]+" "+fields[fremcoTableColumns]):
+		time = fields[fremcoTableColumns-1] + " " + fields[fremcoTableColumns]
+	default:
+		diag.fail(line, "row", strings.Join(fields, " "), fmt.Sprintf("expected %d columns, found %d", fremcoTableColumns, len(fields)))
+		return SimpleMeasurement{}, false
+	}
+	values, ok := parseCells(diag, line, fremcoTableFields, fields[:len(fremcoTableFields)])
+	if !ok {
+		return SimpleMeasurement{}, false
+	}
+	return SimpleMeasurement{
+		Length:   values[0],
+		Speed:    values[1],
+		Pressure: values[2],
+		Torque:   values[3],
+		Time:     time,
+	}, true
+}
+
 // Helper functions
 func isNumeric(s string) bool {
 	_, err := strconv.ParseFloat(s, 64)
@@ -187,8 +299,14 @@ func isDateTime(s string) bool {
 	return len(s) >= 16 && s[4] == '-' && s[7] == '-' && (s[10] == ' ' || s[10] == 'T')
 }
 
-// ParseFremcoProtocol extracts comprehensive protocol data from Fremco PDF text
+// ParseFremcoProtocol extracts comprehensive protocol data from Fremco PDF text.
+// English exports are translated to the German labels first (see fremcoLabels).
 func ParseFremcoProtocol(normalized string) *FremcoProtocol {
+	return parseFremcoProtocol(normalized, nil)
+}
+
+// parseFremcoProtocol parses the protocol, recording unreadable values in diag
+func parseFremcoProtocol(normalized string, diag *parseLog) *FremcoProtocol {
 	protocol := &FremcoProtocol{
 		ExportMetadata: FremcoExportMetadata{
 			ParsedAt:      time.Now(),
@@ -196,16 +314,16 @@ func ParseFremcoProtocol(normalized string) *FremcoProtocol {
 		},
 	}
 	
-	lines := strings.Split(normalized, "\n")
+	lines := strings.Split(translateFremcoLabels(normalized), "\n")
 	
 	// Extract protocol info
 	protocol.ProtocolInfo = extractFremcoProtocolInfo(lines)
 	
 	// Extract equipment specifications
-	protocol.Equipment = extractFremcoEquipment(lines)
+	protocol.Equipment = extractFremcoEquipment(lines, diag)
 	
 	// Extract measurements and summary
-	protocol.Measurements = extractFremcoMeasurements(lines)
+	protocol.Measurements = extractFremcoMeasurements(lines, diag)
 	
 	return protocol
 }
@@ -256,16 +374,20 @@ func extractFremcoProtocolInfo(lines []string) FremcoProtocolInfo {
 		// Company - extract from same line after "Firma"
 		if strings.Contains(line, "Firma") {
 			// Extract company from same line
-			companyMatch := regexp.MustCompile(`Firma\s+(.+)`).FindStringSubmatch(line)
+			companyMatch := fremcoCompanyRe.FindStringSubmatch(line)
 			if len(companyMatch) > 1 {
-				info.Company = strings.TrimSpace(companyMatch[1])
+				info.Company = NormalizeCompany(companyMatch[1])
 				log.Printf("extractFremcoProtocolInfo: Found Company - Line %d: '%s' -> Extracted: '%s'", i, line, info.Company)
 			}
 		}
 		
-		// Service provider
-		if strings.Contains(line, "M.A.X. Bauservice") {
-			info.ServiceProvider = "M.A.X. Bauservice"
+		// Service provider - labeled, or printed without a label on the line under the company
+		if providerMatch := fremcoServiceProviderRe.FindStringSubmatch(line); len(providerMatch) > 1 {
+			info.ServiceProvider = NormalizeCompany(providerMatch[1])
+		} else if fremcoCompanyRe.MatchString(line) && i+1 < len(lines) && info.ServiceProvider == "" {
+			if next := strings.TrimSpace(lines[i+1]); next != "" && !strings.Contains(next, ":") {
+				info.ServiceProvider = NormalizeCompany(next)
+			}
 		}
 		
 		// Operator
@@ -285,9 +407,21 @@ func extractFremcoProtocolInfo(lines []string) FremcoProtocolInfo {
 	return info
 }
 
+var (
+	fremcoCompanyRe      = regexp.MustCompile(`Firma\s+(.+)`)
+	fremcoManufacturerRe = regexp.MustCompile(`Hersteller:\s*(.+?)(?:\s{2,}|$)`)
+	// "Rohr: SNR 7x1,5" - the dimensions follow the type after a single space,
+	// a two-column layout separates the next label by a wider gap
+	fremcoPipeBundleRe      = regexp.MustCompile(`Rohrverband:\s*(.+?)(?:\s{2,}|$)`)
+	fremcoPipeTypeRe        = regexp.MustCompile(`Rohr:\s*(.+?)(?:\s{2,}|$)`)
+	fremcoServiceProviderRe = regexp.MustCompile(`(?:Dienstleister|Nachunternehmer|Subunternehmer):\s*(.+)`)
+)
+
 // extractFremcoEquipment parses equipment specifications
-func extractFremcoEquipment(lines []string) FremcoEquipment {
+func extractFremcoEquipment(lines []string, diag *parseLog) FremcoEquipment {
 	equipment := FremcoEquipment{}
+	section := ""
+	manufacturers := 0
 	
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
@@ -315,20 +449,34 @@ func extractFremcoEquipment(lines []string) FremcoEquipment {
 			equipment.BlowingDevice.CrashTestPerformed = strings.Contains(line, "ja")
 		}
 		
-		// Pipe specifications
-		if strings.Contains(line, "Hersteller:") && strings.Contains(line, "Gabocom") {
-			equipment.Pipe.Manufacturer = "Gabocom"
+		// Manufacturers - the pipe section comes before the cable section, a
+		// two-column layout prints both on one line
+		if line == "Rohr" {
+			section = "pipe"
+		} else if line == "Kabel" {
+			section = "cable"
+		}
+		for _, m := range fremcoManufacturerRe.FindAllStringSubmatch(line, -1) {
+			manufacturer := NormalizeCompany(m[1])
+			if section == "cable" || (section == "" && manufacturers > 0) {
+				equipment.Cable.Manufacturer = manufacturer
+			} else {
+				equipment.Pipe.Manufacturer = manufacturer
+			}
+			manufacturers++
 		}
 		
+		// Pipe specifications
+		
 		if strings.Contains(line, "Rohrverband:") {
-			bundleMatch := regexp.MustCompile(`Rohrverband:\s*(.+?)(?:\s|$)`).FindStringSubmatch(line)
+			bundleMatch := fremcoPipeBundleRe.FindStringSubmatch(line)
 			if len(bundleMatch) > 1 {
 				equipment.Pipe.PipeBundle = strings.TrimSpace(bundleMatch[1])
 			}
 		}
 		
 		if strings.Contains(line, "Rohr:") {
-			pipeMatch := regexp.MustCompile(`Rohr:\s*(.+?)(?:\s|$)`).FindStringSubmatch(line)
+			pipeMatch := fremcoPipeTypeRe.FindStringSubmatch(line)
 			if len(pipeMatch) > 1 {
 				equipment.Pipe.PipeType = strings.TrimSpace(pipeMatch[1])
 			}
@@ -350,10 +498,6 @@ func extractFremcoEquipment(lines []string) FremcoEquipment {
 		}
 		
 		// Cable specifications
-		if strings.Contains(line, "Hersteller:") && strings.Contains(line, "Prysmian") {
-			equipment.Cable.Manufacturer = "Prysmian"
-		}
-		
 		if strings.Contains(line, "Bezeichnung:") {
 			designMatch := regexp.MustCompile(`Bezeichnung:\s*(.+)`).FindStringSubmatch(line)
 			if len(designMatch) > 1 {
@@ -362,18 +506,20 @@ func extractFremcoEquipment(lines []string) FremcoEquipment {
 		}
 		
 		if strings.Contains(line, "Faserzahl:") {
-			fiberMatch := regexp.MustCompile(`Faserzahl:\s*(\d+)`).FindStringSubmatch(line)
-			if len(fiberMatch) > 1 {
-				if count, err := strconv.Atoi(fiberMatch[1]); err == nil {
-					equipment.Cable.FiberCount = count
-				}
+			fiberMatch := regexp.MustCompile(`Faserzahl:\s*(.*)`).FindStringSubmatch(line)
+			if count, err := ParseCount(fiberMatch[1]); err == nil {
+				equipment.Cable.FiberCount = count
+			} else {
+				diag.warn(i+1, "fiber_count", line, "no fiber count after label")
 			}
 		}
 		
 		if strings.Contains(line, "Kabel-Durchmesser:") && i+1 < len(lines) {
 			diameterStr := strings.TrimSpace(lines[i+1])
-			if diameter, err := strconv.ParseFloat(diameterStr, 64); err == nil {
+			if diameter, _, err := ParseQuantity(diameterStr); err == nil {
 				equipment.Cable.Diameter = diameter
+			} else {
+				diag.warn(i+2, "cable_diameter", diameterStr, "not a number")
 			}
 		}
 		
@@ -385,11 +531,11 @@ func extractFremcoEquipment(lines []string) FremcoEquipment {
 		}
 		
 		if strings.Contains(line, "Kabel-Temperatur:") {
-			tempMatch := regexp.MustCompile(`Kabel-Temperatur:\s*(\d+(?:\.\d+)?)°C`).FindStringSubmatch(line)
-			if len(tempMatch) > 1 {
-				if temp, err := strconv.ParseFloat(tempMatch[1], 64); err == nil {
-					equipment.Cable.Temperature = &temp
-				}
+			tempMatch := regexp.MustCompile(`Kabel-Temperatur:\s*(.*)`).FindStringSubmatch(line)
+			if temp, unit, err := ParseQuantity(tempMatch[1]); err == nil && (unit == "" || strings.HasPrefix(unit, "°")) {
+				equipment.Cable.Temperature = &temp
+			} else {
+				diag.warn(i+1, "cable_temperature", line, "no temperature in °C after label")
 			}
 		}
 		
@@ -418,67 +564,84 @@ func extractFremcoEquipment(lines []string) FremcoEquipment {
 }
 
 // extractFremcoMeasurements parses measurements and summary data
-func extractFremcoMeasurements(lines []string) FremcoMeasurements {
+func extractFremcoMeasurements(lines []string, diag *parseLog) FremcoMeasurements {
 	measurements := FremcoMeasurements{
 		DataPoints: []FremcoDataPoint{},
 	}
 	
-	for _, line := range lines {
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		
 		// Meter readings
 		if strings.Contains(line, "Start:") && strings.Contains(line, "Ende:") {
-			meterMatch := regexp.MustCompile(`Start:\s*(\d+)\s*\|\s*Ende:\s*(\d+)`).FindStringSubmatch(line)
+			meterMatch := regexp.MustCompile(`Start:\s*([^|]*?)\s*\|\s*Ende:\s*(.*)`).FindStringSubmatch(line)
+			read := false
 			if len(meterMatch) > 2 {
-				if start, err := strconv.Atoi(meterMatch[1]); err == nil {
+				start, err1 := ParseCount(meterMatch[1])
+				end, err2 := ParseCount(meterMatch[2])
+				if read = err1 == nil && err2 == nil; read {
 					measurements.MeterReadings.Start = start
-				}
-				if end, err := strconv.Atoi(meterMatch[2]); err == nil {
 					measurements.MeterReadings.End = end
 				}
 			}
+			if !read {
+				diag.warn(i+1, "meter_readings", line, "expected Start: <m> | Ende: <m>")
+			}
 		}
 		
 		// Summary data
 		if strings.Contains(line, "Strecke:") && strings.Contains(line, "Einblaszeit:") {
-			summaryMatch := regexp.MustCompile(`Strecke:\s*(\d+)\s+Einblaszeit:\s*([\d:]+)`).FindStringSubmatch(line)
+			summaryMatch := regexp.MustCompile(`Strecke:\s*(.*?)\s*Einblaszeit:\s*([\d:]+)`).FindStringSubmatch(line)
 			if len(summaryMatch) > 2 {
-				if distance, err := strconv.Atoi(summaryMatch[1]); err == nil {
+				if distance, err := ParseCount(summaryMatch[1]); err == nil {
 					measurements.Summary.Distance = distance
+				} else {
+					diag.warn(i+1, "distance", summaryMatch[1], "not a length")
 				}
 				measurements.Summary.BlowingTime = summaryMatch[2]
+			} else {
+				diag.warn(i+1, "distance", line, "expected Strecke: <m> Einblaszeit: <hh:mm:ss>")
 			}
 		}
 		
 		// Weather data
 		if strings.Contains(line, "Wetter:") {
-			weatherMatch := regexp.MustCompile(`Wetter:\s*([\d.]+)°C,\s*([\d.]+)%RH`).FindStringSubmatch(line)
+			// "19.3°C, 61.6%RH" or "19,3 °C, 61,6 %RH"
+			weatherMatch := regexp.MustCompile(`Wetter:\s*([+-]?\d[\d.,]*?)\s*°\s*C\s*,\s*(\d[\d.,]*?)\s*%\s*RH`).FindStringSubmatch(line)
 			if len(weatherMatch) > 2 {
-				if temp, err := strconv.ParseFloat(weatherMatch[1], 64); err == nil {
+				if temp, err := ParseDecimal(weatherMatch[1]); err == nil {
 					measurements.Summary.Weather.Temperature = temp
 				}
-				if humidity, err := strconv.ParseFloat(weatherMatch[2], 64); err == nil {
+				if humidity, err := ParseDecimal(weatherMatch[2]); err == nil {
 					measurements.Summary.Weather.Humidity = humidity
 				}
+			} else {
+				diag.warn(i+1, "weather", line, "expected Wetter: <°C>, <%RH>")
 			}
 		}
 		
 		// GPS data
 		if strings.Contains(line, "Ort (GPS):") {
-			gpsMatch := regexp.MustCompile(`Ort \(GPS\):\s*([\d.]+),\s*([\d.]+)`).FindStringSubmatch(line)
+			// "52.48653, 9.85465" or with comma decimals "52,48653; 9,85465"
+			gpsMatch := regexp.MustCompile(`Ort \(GPS\):\s*(-?\d+(?:[.,]\d+)?)\s*[,;]\s*(-?\d+(?:[.,]\d+)?)`).FindStringSubmatch(line)
 			if len(gpsMatch) > 2 {
-				if lat, err := strconv.ParseFloat(gpsMatch[1], 64); err == nil {
+				if lat, err := ParseDecimal(gpsMatch[1]); err == nil {
 					measurements.Summary.GPSLocation.Latitude = lat
 				}
-				if lon, err := strconv.ParseFloat(gpsMatch[2], 64); err == nil {
+				if lon, err := ParseDecimal(gpsMatch[2]); err == nil {
 					measurements.Summary.GPSLocation.Longitude = lon
 				}
+			} else {
+				diag.warn(i+1, "gps_location", line, "expected Ort (GPS): <lat>, <lon>")
 			}
 		}
 	}
 	
 	// Parse measurement data points using existing function
-	simpleMeasurements := ParseFremcoSimple(strings.Join(lines, "\n"))
+	simpleMeasurements := parseFremcoTable(strings.Join(lines, "\n"), diag)
+	if len(simpleMeasurements) == 0 {
+		diag.fail(0, "data_points", "", "no measurements found")
+	}
 	for _, sm := range simpleMeasurements {
 		measurements.DataPoints = append(measurements.DataPoints, FremcoDataPoint{
 			LengthM:       sm.Length,
@@ -491,3 +654,127 @@ func extractFremcoMeasurements(lines []string) FremcoMeasurements {
 	
 	return measurements
 }
+
+// FremcoParserName is the registry name of the Fremco parser
+const FremcoParserName = "fremco"
+
+func init() {
+	RegisterParser(fremcoParser{})
+}
+
+// fremcoParser parses Fremco SpeedNet "Einblas - Protokoll" exports
+type fremcoParser struct{}
+
+func (fremcoParser) Name() string { return FremcoParserName }
+
+// fremcoFullScore is the number of indicator points that make a Fremco export certain
+const fremcoFullScore = 8
+
+// Detect scores Fremco device names, form labels and the measurement table header
+func (fremcoParser) Detect(text, filename string) Detection {
+	var score detectionScore
+	if strings.Contains(text, "Fremco") {
+		score.add(3, `"Fremco"`)
+	}
+	if strings.Contains(text, "SpeedNet") {
+		score.add(2, `"SpeedNet"`)
+	}
+	if strings.Contains(text, "MicroFlow") {
+		score.add(2, `"MicroFlow"`)
+	}
+	if strings.Contains(text, "Blowing distance") {
+		score.add(1, `"Blowing distance"`)
+	}
+	if strings.Contains(text, "Blowing time") {
+		score.add(1, `"Blowing time"`)
+	}
+	if strings.Contains(text, "Streckenabschnitt") {
+		score.add(1, `"Streckenabschnitt"`)
+		if strings.Contains(text, "Einblasgerät") {
+			score.add(1, `"Einblasgerät"`)
+		}
+	}
+	if strings.Contains(text, "Equipment") && strings.Contains(text, "Serial") {
+		score.add(1, `"Equipment" and "Serial"`)
+	}
+	if strings.Contains(text, "Streckenlänge [m]") && strings.Contains(text, "Geschwindigkeit [m/min]") &&
+		strings.Contains(text, "Rohr-Druck [bar]") && strings.Contains(text, "Drehmoment [%]") {
+		score.add(2, "Fremco measurement table header")
+	} else if strings.Contains(text, "Length [m]") && strings.Contains(text, "Speed [m/min]") &&
+		strings.Contains(text, "Torque [%]") {
+		score.add(2, "Fremco measurement table header (English)")
+	}
+	return score.detection(FremcoParserName, fremcoFullScore)
+}
+
+func (fremcoParser) Normalize(text string) string { return NormalizeFremcoTxt(text) }
+
+func (fremcoParser) Parse(normalized string) (*ParseResult, error) {
+	var diag parseLog
+	protocol := parseFremcoProtocol(normalized, &diag)
+	return diag.result(protocol), nil
+}
+
+// Format implements Protocol
+func (p *FremcoProtocol) Format() string { return FremcoParserName }
+
+// DataPointCount implements Protocol
+func (p *FremcoProtocol) DataPointCount() int { return len(p.Measurements.DataPoints) }
+
+// MaxLengthM implements Protocol
+func (p *FremcoProtocol) MaxLengthM() float64 {
+	maxLength := 0.0
+	for _, dp := range p.Measurements.DataPoints {
+		maxLength = math.Max(maxLength, dp.LengthM)
+	}
+	return maxLength
+}
+
+// ApplyFilenameInfo takes project, date, start time and section from the file name where present
+func (p *FremcoProtocol) ApplyFilenameInfo(info FilenameInfo) {
+	p.ExportMetadata.SourceFilename = info.Filename
+	if info.Date != "" {
+		p.ProtocolInfo.Date = info.Date
+	}
+	if info.Time != "" {
+		p.ProtocolInfo.StartTime = info.Time
+	}
+	if info.Project != "" {
+		p.ProtocolInfo.ProjectNumber = info.Project
+	}
+	if section := info.SectionNVT(); section != "" {
+		p.ProtocolInfo.SectionNVT = section
+	}
+}
+
+// fremcoSourceColumns are the columns of the Fremco measurement table
+var fremcoSourceColumns = []SourceColumn{
+	{Name: "Länge", Unit: "m", Field: "length_m"},
+	{Name: "Geschwindigkeit", Unit: "m/min", Field: "speed_m_min"},
+	{Name: "Rohr-Druck", Unit: "bar", Field: "pressure_bar"},
+	{Name: "Drehmoment", Unit: "%", Field: "torque_percent"},
+	{Name: "Uhrzeit", Unit: "hh:mm:ss", Field: logClock},
+}
+
+// MeasurementSeries implements Protocol. The Uhrzeit column is a time of day
+// on the protocol date, or a full date and time in some exports.
+func (p *FremcoProtocol) MeasurementSeries() MeasurementSeries {
+	measurements := make([]Measurement, 0, len(p.Measurements.DataPoints))
+	clocks := make([]*time.Duration, 0, len(p.Measurements.DataPoints))
+	for i, dp := range p.Measurements.DataPoints {
+		speed, pressure, torque := dp.SpeedMMin, dp.PressureBar, dp.TorquePercent
+		timestamp, clock := parseMeasurementTime(dp.Timestamp)
+		measurements = append(measurements, Measurement{
+			Sequence:      i + 1,
+			LengthM:       dp.LengthM,
+			SpeedMMin:     &speed,
+			PressureBar:   &pressure,
+			TorquePercent: &torque,
+			Timestamp:     timestamp,
+		})
+		clocks = append(clocks, clock)
+	}
+	start, hasStart := parseProtocolStart(p.ProtocolInfo.Date, p.ProtocolInfo.StartTime)
+	completeMeasurementTimes(measurements, clocks, start, hasStart)
+	return MeasurementSeries{Format: FremcoParserName, SourceColumns: fremcoSourceColumns, Measurements: measurements}
+}
@@ -0,0 +1,203 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+)
+
+const fremcoTableHeader = "Streckenlänge [m] Geschwindigkeit [m/min] Rohr-Druck [bar] Drehmoment [%] Uhrzeit [hh:mm:ss]"
+
+func TestParseFremcoTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		lengths  []float64 // lengths of the expected rows
+		errors   []string  // fields of the expected errors
+		warnings []string  // fields of the expected warnings
+	}{
+		{
+			name: "pages with footers and repeated headers",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "1 63 12.1 0 07:36:01", "Seite 1 von 2",
+				"SpeedNet-System Einblas - Protokoll", fremcoTableHeader, "2 63 12.1 3 07:36:02", "Seite 2 von 2",
+			},
+			lengths: []float64{0, 1, 2},
+		},
+		{
+			name: "row repeated at the top of the next page",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "1 63 12.1 0 07:36:01", "Seite 1 von 2",
+				fremcoTableHeader, "1 63 12.1 0 07:36:01", "2 63 12.1 3 07:36:02", "Seite 2 von 2",
+			},
+			lengths: []float64{0, 1, 2},
+		},
+		{
+			name: "row repeated above the column header",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "1 63 12.1 0 07:36:01", "Seite 1 von 2",
+				"1 63 12.1 0 07:36:01", fremcoTableHeader, "2 63 12.1 3 07:36:02", "Seite 2 von 2",
+			},
+			lengths: []float64{0, 1, 2},
+		},
+		{
+			// Only a page break makes a repeated row a duplicate
+			name: "equal rows inside a page",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "0 0 12.2 2 07:36:00", "1 63 12.1 0 07:36:01", "Seite 1 von 1",
+			},
+			lengths: []float64{0, 0, 1},
+		},
+		{
+			name: "footer on the line of the last row",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "1 63 12.1 0 07:36:01 Seite 1 von 2",
+				fremcoTableHeader, "2 63 12.1 3 07:36:02 Seite 2 von 2",
+			},
+			lengths: []float64{0, 1, 2},
+		},
+		{
+			name: "page header starting with a number",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "Seite 1 von 2",
+				"2025-07-16 07:36 Lindenallee 15 / NVT2V3300", fremcoTableHeader, "1 63 12.1 0 07:36:01", "Seite 2 von 2",
+			},
+			lengths: []float64{0, 1},
+		},
+		{
+			name: "units below the column names",
+			lines: []string{
+				"Streckenlänge Geschwindigkeit Rohr-Druck Drehmoment Uhrzeit", "[m] [m/min] [bar] [%] [hh:mm:ss]",
+				"0 0 12.2 2 07:36:00", "Seite 1 von 2",
+				"Streckenlänge Geschwindigkeit Rohr-Druck Drehmoment Uhrzeit", "[m] [m/min] [bar] [%] [hh:mm:ss]",
+				"1 63 12.1 0 07:36:01", "Seite 2 von 2",
+			},
+			lengths: []float64{0, 1},
+		},
+		{
+			name: "page with other units",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "Seite 1 von 3",
+				"Streckenlänge [ft] Geschwindigkeit [ft/min] Rohr-Druck [psi] Drehmoment [%] Uhrzeit [hh:mm:ss]",
+				"3.3 207 175 0 07:36:01", "Seite 2 von 3",
+				fremcoTableHeader, "2 63 12.1 3 07:36:02", "Seite 3 von 3",
+			},
+			lengths: []float64{0, 2},
+			errors:  []string{"table_header"},
+		},
+		{
+			name: "page with a missing column",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "Seite 1 von 2",
+				"Streckenlänge [m] Geschwindigkeit [m/min] Drehmoment [%] Uhrzeit [hh:mm:ss]",
+				"1 63 0 07:36:01", "Seite 2 von 2",
+			},
+			lengths: []float64{0},
+			errors:  []string{"table_header"},
+		},
+		{
+			name: "row with a missing cell",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "1 63 0 07:36:01", "2 63 12.1 3 07:36:02", "Seite 1 von 1",
+			},
+			lengths: []float64{0, 2},
+			errors:  []string{"row"},
+		},
+		{
+			name: "skipped page",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "Seite 1 von 3",
+				fremcoTableHeader, "5 63 12.1 3 07:36:05", "Seite 3 von 3",
+			},
+			lengths:  []float64{0, 5},
+			warnings: []string{"page"},
+		},
+		{
+			name: "last page missing",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "Seite 1 von 2",
+			},
+			lengths:  []float64{0},
+			warnings: []string{"page"},
+		},
+		{
+			name: "table ends at the summary",
+			lines: []string{
+				fremcoTableHeader, "0 0 12.2 2 07:36:00", "Zusammenfassung", "562 00:16:51 16.8 50.5", "Seite 1 von 1",
+			},
+			lengths: []float64{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diag parseLog
+			rows := parseFremcoTable(strings.Join(tt.lines, "\n"), &diag)
+			var lengths []float64
+			for _, r := range rows {
+				lengths = append(lengths, r.Length)
+			}
+			if !equalFloats(lengths, tt.lengths) {
+				t.Errorf("row lengths %v, want %v", lengths, tt.lengths)
+			}
+			result := diag.result(nil)
+			if got, want := diagnosticFields(result.Errors), strings.Join(tt.errors, " "); got != want {
+				t.Errorf("errors %v, want %q", result.Errors, want)
+			}
+			if got, want := diagnosticFields(result.Warnings), strings.Join(tt.warnings, " "); got != want {
+				t.Errorf("warnings %v, want %q", result.Warnings, want)
+			}
+		})
+	}
+}
+
+func TestFremcoMultiPageRoundTrip(t *testing.T) {
+	quietLog(t)
+	for _, rowsPerPage := range []int{1, 3, 7, 10} {
+		generated, err := GenerateFremcoProtocol(GeneratorOptions{Seed: int64(rowsPerPage), LengthM: 120, Stalls: -1, Restarts: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		diffs, err := RoundTripDiff(generated, rowsPerPage)
+		if err != nil {
+			t.Fatalf("%d rows per page: %v", rowsPerPage, err)
+		}
+		if len(diffs) > 0 {
+			t.Errorf("%d rows per page: parsed protocol differs:\n  %s", rowsPerPage, strings.Join(diffs, "\n  "))
+		}
+
+		// Repeat the last row of every page at the top of the next one
+		pages := RenderFremcoPages(generated, rowsPerPage)
+		for p := 1; p < len(pages); p++ {
+			previous := strings.Split(pages[p-1], "\n")
+			lastRow := previous[len(previous)-2]
+			pages[p] = strings.Replace(pages[p], fremcoTableHeader, fremcoTableHeader+"\n"+lastRow, 1)
+		}
+		var diag parseLog
+		rows := parseFremcoTable(strings.Join(pages, "\n"), &diag)
+		if len(rows) != generated.DataPointCount() || len(diag.errors) != 0 || len(diag.warnings) != 0 {
+			t.Errorf("%d rows per page, repeated rows: %d rows, want %d; diagnostics %v",
+				rowsPerPage, len(rows), generated.DataPointCount(), diag.result(nil).Diagnostics())
+		}
+	}
+}
+
+// diagnosticFields lists the fields of the diagnostics, separated by spaces
+func diagnosticFields(d []Diagnostic) string {
+	fields := make([]string, len(d))
+	for i, x := range d {
+		fields[i] = x.Field
+	}
+	return strings.Join(fields, " ")
+}
+
+// equalFloats reports whether two slices hold the same values
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,11 +1,14 @@
 package simulator
 
 import (
+	"fmt"
 	"log"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // ExtractJettingMetadata scans normalized Jetting TXT for metadata fields.
@@ -73,9 +76,9 @@ func min(vals ...int) int {
 	return m
 }
 
-// isJettingNumeric checks if a string is a valid number (including decimals)
+// isJettingNumeric checks if a string is a valid number (including comma decimals)
 func isJettingNumeric(s string) bool {
-	_, err := strconv.ParseFloat(s, 64)
+	_, err := ParseDecimal(s)
 	return err == nil
 }
 
@@ -88,44 +91,88 @@ func isTimeFormat(s string) bool {
 
 // ParseJettingTxt parses normalized Jetting TXT data and returns a slice of JettingMeasurement.
 func ParseJettingTxt(normalized string) []JettingMeasurement {
+	return parseJettingTable(normalized, nil)
+}
+
+// jettingTableFields names the numeric columns of a Jetting table row in diagnostics
+var jettingTableFields = []string{"length_m", "temperature_c", "force_n", "pressure_bar", "speed_m_min"}
+
+// jettingCell is a table value with its line in the normalized text
+type jettingCell struct {
+	text string
+	line int
+}
+
+// jettingRow builds a measurement from five numbers and a duration. A row
+// with an unreadable cell is recorded and dropped.
+func jettingRow(diag *parseLog, cells []jettingCell) (JettingMeasurement, bool) {
+	values := make([]float64, len(jettingTableFields))
+	ok := true
+	for i, field := range jettingTableFields {
+		v, cellOK := parseCell(diag, cells[i].line, field, cells[i].text)
+		values[i] = v
+		ok = ok && cellOK
+	}
+	duration := cells[len(jettingTableFields)]
+	if _, err := ParseElapsed(duration.text); err != nil {
+		diag.fail(duration.line, "time_duration", duration.text, "not a duration hh:mm:ss")
+		ok = false
+	}
+	return JettingMeasurement{
+		Length:      values[0],     // Länge[m]
+		Temperature: values[1],     // Lufttemperatur[°C]
+		Force:       values[2],     // Schubkraft[N]
+		Pressure:    values[3],     // Einblasdruck[bar]
+		Speed:       values[4],     // Geschwindigkeit[m/min]
+		Time:        duration.text, // Zeit - Dauer[hh:mm:ss]
+	}, ok
+}
+
+// parseJettingTable parses the measurement table, recording unreadable cells
+func parseJettingTable(normalized string, diag *parseLog) []JettingMeasurement {
 	lines := strings.Split(normalized, "\n")
 	var measurements []JettingMeasurement
+	// Diagnostics of the row layouts are only kept if they produce the measurements
+	var rows parseLog
 	
 	// First try: Parse as individual values per line (6 consecutive lines = 1 measurement)
-	var valueBuffer []string
-	for _, line := range lines {
+	var valueBuffer []jettingCell
+	inTable := false
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
 		
-		// Skip header lines and company names
-		if isBlockHeader(line) || strings.Contains(line, "M.A.X.") || strings.Contains(line, "Bauservice") || strings.Contains(line, "Wolken-ASM") || strings.Contains(line, "GMBH") {
+		// Skip header lines
+		if isBlockHeader(line) {
+			inTable = true
 			continue
 		}
 		
-		// Collect numeric values and time values
-		if isJettingNumeric(line) || isTimeFormat(line) {
-			valueBuffer = append(valueBuffer, line)
+		// Collect numeric values and time values. Inside the table a garbled
+		// single value is collected too, so that the rows after it stay aligned.
+		garbled := inTable && !strings.ContainsAny(line, " \t") && strings.ContainsAny(line, "0123456789")
+		if isJettingNumeric(line) || isTimeFormat(line) || garbled {
+			cell := jettingCell{line, i + 1}
+			// The duration ends a row: a row with missing values is dropped
+			// instead of shifting every row after it
+			if isTimeFormat(line) && len(valueBuffer) != len(jettingTableFields) {
+				if inTable {
+					rows.fail(cell.line, "row", jettingCellTexts(append(valueBuffer, cell)),
+						fmt.Sprintf("%d of %d values before the duration", len(valueBuffer), len(jettingTableFields)))
+				}
+				valueBuffer = nil
+				continue
+			}
+			valueBuffer = append(valueBuffer, cell)
 			
 			// When we have 6 values, create a measurement
 			if len(valueBuffer) == 6 {
-				length, _ := strconv.ParseFloat(valueBuffer[0], 64)    // Länge[m]
-				temp, _ := strconv.ParseFloat(valueBuffer[1], 64)      // Lufttemperatur[°C]
-				force, _ := strconv.ParseFloat(valueBuffer[2], 64)     // Schubkraft[N]
-				pressure, _ := strconv.ParseFloat(valueBuffer[3], 64)  // Einblasdruck[bar] 
-				speed, _ := strconv.ParseFloat(valueBuffer[4], 64)     // Geschwindigkeit[m/min]
-				time := valueBuffer[5]                                 // Zeit - Dauer[hh:mm:ss]
-				
-				measurements = append(measurements, JettingMeasurement{
-					Length:      length,
-					Temperature: temp,
-					Force:       force,
-					Pressure:    pressure,
-					Speed:       speed,
-					Time:        time,
-				})
-				valueBuffer = []string{} // Reset buffer
+				if m, ok := jettingRow(&rows, valueBuffer); ok {
+					measurements = append(measurements, m)
+				}
+				valueBuffer = nil // Reset buffer
 			}
 			continue
 		}
@@ -134,52 +181,54 @@ func ParseJettingTxt(normalized string) []JettingMeasurement {
 		fields := strings.Fields(line)
 		if len(fields) >= 6 {
 			// Only parse lines that start with a valid number (skip text headers)
-			if length, err := strconv.ParseFloat(fields[0], 64); err == nil {
-				temp, _ := strconv.ParseFloat(fields[1], 64)     
-				force, _ := strconv.ParseFloat(fields[2], 64)    
-				pressure, _ := strconv.ParseFloat(fields[3], 64) 
-				speed, _ := strconv.ParseFloat(fields[4], 64)    
-				time := fields[5]                                
-				measurements = append(measurements, JettingMeasurement{
-					Length:      length,
-					Temperature: temp,
-					Force:       force,
-					Pressure:    pressure,
-					Speed:       speed,
-					Time:        time,
-				})
+			if isJettingNumeric(fields[0]) {
+				cells := make([]jettingCell, 6)
+				for j := range cells {
+					cells[j] = jettingCell{fields[j], i + 1}
+				}
+				if m, ok := jettingRow(&rows, cells); ok {
+					measurements = append(measurements, m)
+				}
 			}
 		}
 	}
+	if len(valueBuffer) > 0 {
+		rows.warn(valueBuffer[0].line, "row", jettingCellTexts(valueBuffer), "incomplete row at the end of the table")
+	}
 	
 	// If no measurements found with individual line parsing, try vertical block format
 	if len(measurements) == 0 {
-		measurements = parseVerticalBlocks(normalized)
+		return parseVerticalBlocks(normalized, diag)
 	}
+	diag.merge(&rows)
 	return measurements
 }
 
+// jettingCellTexts joins the texts of table cells for a diagnostic
+func jettingCellTexts(cells []jettingCell) string {
+	texts := make([]string, len(cells))
+	for i, c := range cells {
+		texts[i] = c.text
+	}
+	return strings.Join(texts, " ")
+}
+
 // parseVerticalBlocks parses the vertical block format where each column header is followed by all its values
-func parseVerticalBlocks(normalized string) []JettingMeasurement {
+func parseVerticalBlocks(normalized string, diag *parseLog) []JettingMeasurement {
 	lines := strings.Split(normalized, "\n")
 	
 	// Find the data blocks for each column
-	var lengthValues, tempValues, forceValues, pressureValues, speedValues, timeValues []string
+	var lengthValues, tempValues, forceValues, pressureValues, speedValues, timeValues []jettingCell
 	
 	currentBlock := ""
 	collectingData := false
 	
-	for _, line := range lines {
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
 		
-		// Skip company header
-		if strings.HasPrefix(line, "M.A.X.") || strings.Contains(line, "Bauservice") || strings.Contains(line, "Wolken-ASM") || strings.Contains(line, "GMBH") {
-			continue
-		}
-		
 		// Detect which block we're in by flexible header match
 		isHeader := false
 		if strings.Contains(line, "Länge[m]") || strings.Contains(line, "Laenge[m]") {
@@ -219,22 +268,24 @@ func parseVerticalBlocks(normalized string) []JettingMeasurement {
 			continue
 		}
 		
-		// Collect values for current block only if we're actively collecting
-		if collectingData && currentBlock != "" {
+		// Collect values for current block only if we're actively collecting.
+		// Page headers (company names, titles) repeated inside a block are no values.
+		if collectingData && currentBlock != "" && strings.IndexFunc(line, unicode.IsLetter) < 0 {
 			println("DEBUG: Collecting for block:", currentBlock, "line:", line)
+			cell := jettingCell{line, i + 1}
 			switch currentBlock {
 			case "length":
-				lengthValues = append(lengthValues, line)
+				lengthValues = append(lengthValues, cell)
 			case "temp":
-				tempValues = append(tempValues, line)
+				tempValues = append(tempValues, cell)
 			case "force":
-				forceValues = append(forceValues, line)
+				forceValues = append(forceValues, cell)
 			case "pressure":
-				pressureValues = append(pressureValues, line)
+				pressureValues = append(pressureValues, cell)
 			case "speed":
-				speedValues = append(speedValues, line)
+				speedValues = append(speedValues, cell)
 			case "time":
-				timeValues = append(timeValues, line)
+				timeValues = append(timeValues, cell)
 			}
 		} else if strings.TrimSpace(line) != "" && !isHeader {
 			println("DEBUG: Skipping line (not collecting):", line, "currentBlock:", currentBlock, "collectingData:", collectingData)
@@ -254,6 +305,9 @@ func parseVerticalBlocks(normalized string) []JettingMeasurement {
 	println("timeValues count:", len(timeValues))
 	
 	maxLen := len(lengthValues)
+	if len(tempValues) < maxLen {
+		maxLen = len(tempValues)
+	}
 	if len(forceValues) < maxLen {
 		maxLen = len(forceValues)
 	}
@@ -267,22 +321,11 @@ func parseVerticalBlocks(normalized string) []JettingMeasurement {
 		maxLen = len(timeValues)
 	}
 	
-		for i := 0; i < maxLen; i++ {
-		length, _ := strconv.ParseFloat(lengthValues[i], 64)
-		temp, _ := strconv.ParseFloat(tempValues[i], 64)        // Lufttemperatur[°C] -> Temperature
-		force, _ := strconv.ParseFloat(forceValues[i], 64)      // Schubkraft[N] -> Force 
-		pressure, _ := strconv.ParseFloat(pressureValues[i], 64) // Einblasdruck[bar] -> Pressure
-		speed, _ := strconv.ParseFloat(speedValues[i], 64)      // Geschwindigkeit[m/min] -> Speed
-		time := timeValues[i]                                   // Zeit - Dauer[hh:mm:ss] -> Time
-		
-		measurements = append(measurements, JettingMeasurement{
-			Length:      length,
-			Temperature: temp,
-			Force:       force,
-			Pressure:    pressure,  
-			Speed:       speed,     
-			Time:        time,
-		})
+	for i := 0; i < maxLen; i++ {
+		row := []jettingCell{lengthValues[i], tempValues[i], forceValues[i], pressureValues[i], speedValues[i], timeValues[i]}
+		if m, ok := jettingRow(diag, row); ok {
+			measurements = append(measurements, m)
+		}
 	}
 	
 	return measurements
@@ -290,6 +333,11 @@ func parseVerticalBlocks(normalized string) []JettingMeasurement {
 
 // ParseJettingProtocol extracts comprehensive protocol data from Jetting PDF text
 func ParseJettingProtocol(normalized string) *JettingProtocol {
+	return parseJettingProtocol(normalized, nil)
+}
+
+// parseJettingProtocol parses the protocol, recording unreadable values in diag
+func parseJettingProtocol(normalized string, diag *parseLog) *JettingProtocol {
 	protocol := &JettingProtocol{
 		ExportMetadata: JettingExportMetadata{
 			ParsedAt:      time.Now(),
@@ -298,21 +346,22 @@ func ParseJettingProtocol(normalized string) *JettingProtocol {
 	}
 	
 	lines := strings.Split(normalized, "\n")
+	values := jettingFormValues(lines)
 	
 	// Extract protocol info
-	protocol.ProtocolInfo = extractJettingProtocolInfo(lines)
+	protocol.ProtocolInfo = extractJettingProtocolInfo(lines, values)
 	
-	// Extract equipment specifications (minimal for Jetting)
-	protocol.Equipment = extractJettingEquipment(lines)
+	// Extract equipment specifications
+	protocol.Equipment = extractJettingEquipment(values, diag)
 	
 	// Extract measurements
-	protocol.Measurements = extractJettingMeasurements(lines)
+	protocol.Measurements = extractJettingMeasurements(lines, values, diag)
 	
 	return protocol
 }
 
 // extractJettingProtocolInfo parses header information from Jetting PDFs
-func extractJettingProtocolInfo(lines []string) JettingProtocolInfo {
+func extractJettingProtocolInfo(lines []string, values map[string]jettingCell) JettingProtocolInfo {
 	info := JettingProtocolInfo{
 		System:       "Jetting System",
 		DocumentType: "Jetting Protokoll",
@@ -358,35 +407,386 @@ func extractJettingProtocolInfo(lines []string) JettingProtocolInfo {
 			}
 		}
 		
-		// Company detection
-		if strings.Contains(line, "Wolken-ASM") {
-			info.Company = "Wolken-ASM GMBH"
-			log.Printf("extractJettingProtocolInfo: Found Company - Line %d: '%s'", i, line)
-		}
-		
-		if strings.Contains(line, "M.A.X. Bauservice") {
-			info.ServiceProvider = "M.A.X. Bauservice"
-			log.Printf("extractJettingProtocolInfo: Found Service Provider - Line %d: '%s'", i, line)
+	}
+	
+	// The export prints the service provider and the company without a label
+	// above the document title. A single name is the company unless it is a
+	// known service provider.
+	switch names := jettingHeaderNames(lines); {
+	case len(names) >= 2:
+		info.ServiceProvider = NormalizeCompany(names[0])
+		info.Company = NormalizeCompany(names[1])
+	case len(names) == 1:
+		if _, ok := FindKnownCompany(names[0], CompanyRoleServiceProvider); ok {
+			info.ServiceProvider = NormalizeCompany(names[0])
+		} else {
+			info.Company = NormalizeCompany(names[0])
 		}
 	}
 	
+	// Labeled form values win over names recognized in the header
+	if company, ok := values["company"]; ok {
+		info.Company = NormalizeCompany(company.text)
+	}
+	if provider, ok := values["service_provider"]; ok {
+		info.ServiceProvider = NormalizeCompany(provider.text)
+	}
+	
 	return info
 }
 
-// extractJettingEquipment parses equipment info (minimal for Jetting)
-func extractJettingEquipment(lines []string) JettingEquipment {
-	// Jetting PDFs typically don't contain detailed equipment specifications
-	// Return empty/null structure
-	return JettingEquipment{
-		BlowingDevice: JettingBlowingDevice{},
-		Pipe:          JettingPipe{ColorCoding: []string{}},
-		Cable:         JettingCable{},
-		Compressor:    JettingCompressor{},
+// jettingHeaderNames returns the unlabeled lines printed before the
+// "... Protokoll" title of a Jetting export.
+func jettingHeaderNames(lines []string) []string {
+	var names []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, "Protokoll") {
+			return names
+		}
+		if line != "" && !strings.Contains(line, ":") {
+			names = append(names, line)
+		}
 	}
+	return nil
+}
+
+// jettingFormLabels maps the labels of the Jetting protocol form to the field
+// they fill. The equipment part has three column groups (pipe, cable, blowing
+// device / compressor), so a plain "Hersteller" is the pipe manufacturer the
+// first time and the cable manufacturer the second time. Labels without a
+// field only end the value of the label before them.
+var jettingFormLabels = map[string]string{
+	"Firma":                   "company",
+	"Dienstleister":           "service_provider",
+	"Nachunternehmer":         "service_provider",
+	"Subunternehmer":          "service_provider",
+	"Bauvorhaben":             "",
+	"Streckenabschnitt / NVt": "",
+	"Einbläser":               "",
+
+	"Hersteller":                 "manufacturer",
+	"Rohr-Hersteller":            "pipe_manufacturer",
+	"Rohrtyp":                    "pipe_type",
+	"Farbe / Kennung":            "pipe_color_coding",
+	"Farbe-Kennung":              "pipe_color_coding",
+	"Rohrinnendurchmesser":       "pipe_inner_diameter",
+	"Rohraußendurchmesser":       "pipe_outer_diameter",
+	"Roraußendurchmesser":        "pipe_outer_diameter", // spelling of the printed form
+	"Rohrtemperatur":             "pipe_temperature",
+	"Rohrinnenwand":              "pipe_inner_wall",
+	"Kabel-Hersteller":           "cable_manufacturer",
+	"Kabeltyp":                   "cable_designation",
+	"Kabel-Ø":                    "cable_diameter",
+	"Faseranzahl":                "cable_fiber_count",
+	"Kabeltrommel-Nr":            "cable_drum_number",
+	"Kabeleinblaskapp":           "cable_blowing_cap",
+	"Kabeleinblaskappe":          "cable_blowing_cap",
+	"Kabeltemperatur":            "cable_temperature",
+	"Gleitmittel":                "cable_lubricant",
+	"Einblasgerät":               "device_model",
+	"Crash Test ausgeführt":      "crash_test_performed",
+	"Crash Test Geschwindigkeit": "crash_test_speed",
+	"Crashtest Geschwindigkeit":  "crash_test_speed",
+	"Crash Test Moment":          "crash_test_moment",
+	"Crashtest Moment":           "crash_test_moment",
+	"Ermittelte Schubkraft":      "crash_test_force", // push force determined by the crash test
+	"Kompressor":                 "compressor_model",
+	"Ölabscheider":               "compressor_oil_separator",
+	"Nachkühler":                 "compressor_after_cooler",
+	"Verlegungsart des Rohres":   "",
+
+	"Start":                   "meter_start",
+	"Ende":                    "meter_end",
+	"Streckenlänge":           "distance",
+	"Zeit - Dauer [hh:mm:ss]": "blowing_time",
+	"Umgebungsluft temp":      "weather_temperature",
+	"Umgebungslufttemperatur": "weather_temperature",
+	"Luftfeuchtigkeit":        "weather_humidity",
+	"Datum / Uhrzeit":         "",
+	"Datum":                   "",
+	"Uhrzeit":                 "",
+	"Ort(GPS)":                "",
+	"Ort (GPS)":               "",
+	"Sicherheitsabschaltung":  "",
+	"Bemerkungen":             "",
+}
+
+var (
+	jettingCellRe = regexp.MustCompile(`\s{2,}`)
+	// jettingGPSRe matches "52.4914°;9.85174°;95.8000 m" (latitude; longitude; altitude)
+	jettingGPSRe = regexp.MustCompile(`(-?\d+[.,]\d+)\s*°\s*;\s*(-?\d+[.,]\d+)\s*°(?:\s*;\s*(-?\d+(?:[.,]\d+)?)\s*m)?`)
+)
+
+// jettingCheckMarks are the characters that tick a form checkbox
+const jettingCheckMarks = "xX☒☑✓✔✗✘"
+
+// isJettingCheckMark reports whether s is a single tick mark
+func isJettingCheckMark(s string) bool {
+	r := []rune(s)
+	return len(r) == 1 && strings.ContainsRune(jettingCheckMarks, r[0])
+}
+
+// jettingChoice returns the ticked option of a form choice like "Ja ☒ Nein ☐"
+// (a mark following the option) or the option itself if it is the only word
+func jettingChoice(value string, options ...string) string {
+	fields := strings.Fields(value)
+	for i, f := range fields {
+		for _, option := range options {
+			if !strings.EqualFold(f, option) {
+				continue
+			}
+			if len(fields) == 1 || (i+1 < len(fields) && isJettingCheckMark(fields[i+1])) {
+				return option
+			}
+		}
+	}
+	return ""
+}
+
+// jettingCheckbox reads a Ja/Nein choice or a single ticked checkbox, nil if unset
+func jettingCheckbox(value string) *bool {
+	ticked := isJettingCheckMark(value)
+	if choice := jettingChoice(value, "Ja", "Nein"); choice != "" {
+		ticked = choice == "Ja"
+	} else if !ticked {
+		return nil
+	}
+	return &ticked
+}
+
+// jettingNumber reads the first number of a value such as "10,4 mm" or "-2.5°C"
+func jettingNumber(value string) *float64 {
+	v, ok := firstNumber(value, false)
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// jettingFormNumber reads the number of a form value, recording a filled in
+// value without a number
+func jettingFormNumber(diag *parseLog, values map[string]jettingCell, field string) *float64 {
+	value, ok := values[field]
+	if !ok {
+		return nil
+	}
+	v := jettingNumber(value.text)
+	if v == nil {
+		diag.warn(value.line, field, value.text, "no number")
+	}
+	return v
+}
+
+// jettingFormInt reads a count or meter reading of a form value ("1.520 m" → 1520)
+func jettingFormInt(diag *parseLog, values map[string]jettingCell, field string) *int {
+	value, ok := values[field]
+	if !ok {
+		return nil
+	}
+	v, ok := firstNumber(value.text, true)
+	if !ok {
+		diag.warn(value.line, field, value.text, "no number")
+		return nil
+	}
+	n := int(math.Round(v))
+	return &n
+}
+
+// jettingFormValues reads the values of the Jetting protocol form by field
+// name. Cells are separated by at least two spaces. The value of a label is
+// the text of the following cells up to the next label or the end of the
+// line, or the text after the colon of a "Label: value" cell. Each value
+// keeps the line of its label.
+func jettingFormValues(lines []string) map[string]jettingCell {
+	values := map[string]jettingCell{}
+	manufacturers := 0
+	field, pending, fieldLine := "", false, 0
+	var texts []string
+
+	finish := func() {
+		if value := strings.Join(texts, " "); pending && field != "" && value != "" {
+			values[field] = jettingCell{value, fieldLine}
+		}
+		field, pending, texts = "", false, nil
+	}
+	start := func(label string, line int) {
+		finish()
+		field, pending, fieldLine = jettingFormLabels[label], true, line
+		if field == "manufacturer" {
+			manufacturers++
+			field = "pipe_manufacturer"
+			if manufacturers > 1 {
+				field = "cable_manufacturer"
+			}
+		}
+	}
+
+	for n, line := range lines {
+		for _, cell := range jettingCellRe.Split(strings.TrimSpace(line), -1) {
+			if cell == "" {
+				continue
+			}
+			if _, ok := jettingFormLabels[cell]; ok {
+				start(cell, n+1)
+				continue
+			}
+			if i := strings.Index(cell, ":"); i > 0 {
+				label := strings.TrimSpace(cell[:i])
+				if _, ok := jettingFormLabels[label]; ok {
+					start(label, n+1)
+					if value := strings.TrimSpace(cell[i+1:]); value != "" {
+						texts = append(texts, value)
+						finish()
+					}
+					continue
+				}
+			}
+			if pending {
+				texts = append(texts, cell)
+			}
+		}
+		finish()
+	}
+	return values
+}
+
+// extractJettingEquipment parses the pipe, cable, blowing device and
+// compressor fields of the Jetting protocol form
+func extractJettingEquipment(values map[string]jettingCell, diag *parseLog) JettingEquipment {
+	equipment := JettingEquipment{
+		Pipe: JettingPipe{ColorCoding: []string{}},
+	}
+
+	for field, cell := range values {
+		value := cell.text
+		switch field {
+		case "pipe_manufacturer":
+			equipment.Pipe.Manufacturer = &value
+		case "pipe_type":
+			equipment.Pipe.PipeType = &value
+		case "pipe_color_coding":
+			equipment.Pipe.ColorCoding = strings.FieldsFunc(value, func(r rune) bool {
+				return r == ' ' || r == ',' || r == '/'
+			})
+		case "pipe_inner_diameter":
+			equipment.Pipe.InnerDiameter = jettingFormNumber(diag, values, field)
+		case "pipe_outer_diameter":
+			equipment.Pipe.OuterDiameter = jettingFormNumber(diag, values, field)
+		case "pipe_temperature":
+			equipment.Pipe.Temperature = jettingFormNumber(diag, values, field)
+		case "pipe_inner_wall":
+			if wall := jettingChoice(value, "glatt", "gerieft"); wall != "" {
+				equipment.Pipe.InnerWall = &wall
+			}
+		case "cable_manufacturer":
+			equipment.Cable.Manufacturer = &value
+		case "cable_designation":
+			equipment.Cable.Designation = &value
+		case "cable_diameter":
+			equipment.Cable.Diameter = jettingFormNumber(diag, values, field)
+		case "cable_fiber_count":
+			equipment.Cable.FiberCount = jettingFormInt(diag, values, field)
+		case "cable_drum_number":
+			equipment.Cable.DrumNumber = &value
+		case "cable_blowing_cap":
+			equipment.Cable.BlowingCap = jettingCheckbox(value)
+		case "cable_temperature":
+			equipment.Cable.Temperature = jettingFormNumber(diag, values, field)
+		case "cable_lubricant":
+			equipment.Cable.Lubricant = &value
+		case "device_model":
+			equipment.BlowingDevice.Model = &value
+		case "crash_test_performed":
+			equipment.BlowingDevice.CrashTestPerformed = jettingCheckbox(value)
+		case "crash_test_speed":
+			equipment.BlowingDevice.CrashTestSpeed = &value
+		case "crash_test_moment":
+			equipment.BlowingDevice.CrashTestMoment = &value
+		case "crash_test_force":
+			equipment.BlowingDevice.CrashTestForce = &value
+		case "compressor_model":
+			equipment.Compressor.Model = &value
+		case "compressor_oil_separator":
+			equipment.Compressor.OilSeparator = jettingCheckbox(value)
+		case "compressor_after_cooler":
+			equipment.Compressor.AfterCooler = jettingCheckbox(value)
+		}
+	}
+
+	return equipment
+}
+
+// extractJettingSummary parses the meter readings, section length, blowing
+// time, ambient conditions and GPS position of the Jetting protocol form.
+// Values the form leaves empty are computed from the data points where
+// possible: the length from the meter readings or the longest data point and
+// the blowing time from the longest duration. The logged Lufttemperatur is the
+// blowing air after the compressor, so the ambient temperature is only read
+// from the form.
+func extractJettingSummary(lines []string, values map[string]jettingCell, dataPoints []JettingDataPoint, diag *parseLog) (JettingMeterReadings, JettingSummary) {
+	var meters JettingMeterReadings
+	var summary JettingSummary
+
+	meters.Start = jettingFormInt(diag, values, "meter_start")
+	meters.End = jettingFormInt(diag, values, "meter_end")
+	summary.Distance = jettingFormInt(diag, values, "distance")
+	if value, ok := values["blowing_time"]; ok {
+		if d, err := ParseElapsed(value.text); err == nil {
+			blowingTime := FormatElapsed(d)
+			summary.BlowingTime = &blowingTime
+		} else {
+			diag.warn(value.line, "blowing_time", value.text, "not a duration hh:mm:ss")
+		}
+	}
+	summary.Weather.Temperature = jettingFormNumber(diag, values, "weather_temperature")
+	summary.Weather.Humidity = jettingFormNumber(diag, values, "weather_humidity")
+
+	for _, line := range lines {
+		if m := jettingGPSRe.FindStringSubmatch(line); m != nil {
+			summary.GPSLocation.Latitude = jettingNumber(m[1])
+			summary.GPSLocation.Longitude = jettingNumber(m[2])
+			summary.GPSLocation.Altitude = jettingNumber(m[3])
+			break
+		}
+	}
+
+	if summary.Distance == nil && meters.Start != nil && meters.End != nil {
+		distance := *meters.End - *meters.Start
+		if distance < 0 {
+			distance = -distance
+		}
+		summary.Distance = &distance
+	}
+	if len(dataPoints) == 0 {
+		return meters, summary
+	}
+	if summary.Distance == nil {
+		maxLength := 0.0
+		for _, dp := range dataPoints {
+			maxLength = math.Max(maxLength, dp.LengthM)
+		}
+		distance := int(math.Round(maxLength))
+		summary.Distance = &distance
+	}
+	if summary.BlowingTime == nil {
+		var longest time.Duration
+		found := false
+		for _, dp := range dataPoints {
+			if d, err := ParseElapsed(dp.TimeDuration); err == nil && d >= longest {
+				longest, found = d, true
+			}
+		}
+		if found {
+			blowingTime := FormatElapsed(longest)
+			summary.BlowingTime = &blowingTime
+		}
+	}
+	return meters, summary
 }
 
 // extractJettingMeasurements parses measurement data from Jetting PDFs
-func extractJettingMeasurements(lines []string) JettingMeasurements {
+func extractJettingMeasurements(lines []string, values map[string]jettingCell, diag *parseLog) JettingMeasurements {
 	measurements := JettingMeasurements{
 		MeterReadings: JettingMeterReadings{},
 		Summary:       JettingSummary{},
@@ -394,7 +794,10 @@ func extractJettingMeasurements(lines []string) JettingMeasurements {
 	}
 	
 	// Parse measurement data points using existing function
-	jettingMeasurements := ParseJettingTxt(strings.Join(lines, "\n"))
+	jettingMeasurements := parseJettingTable(strings.Join(lines, "\n"), diag)
+	if len(jettingMeasurements) == 0 {
+		diag.fail(0, "data_points", "", "no measurements found")
+	}
 	for _, jm := range jettingMeasurements {
 		measurements.DataPoints = append(measurements.DataPoints, JettingDataPoint{
 			LengthM:      jm.Length,
@@ -406,5 +809,158 @@ func extractJettingMeasurements(lines []string) JettingMeasurements {
 		})
 	}
 	
+	measurements.MeterReadings, measurements.Summary = extractJettingSummary(lines, values, measurements.DataPoints, diag)
+
 	return measurements
 }
+
+// JettingParserName is the registry name of the Jetting parser
+const JettingParserName = "jetting"
+
+func init() {
+	RegisterParser(jettingParser{})
+}
+
+// jettingParser parses Jetting "Messwerte" exports
+type jettingParser struct{}
+
+var (
+	jettingFilenameDateRe = regexp.MustCompile(`\d{2}\.\d{2}\.\d{4}`)
+	jettingFilenameTimeRe = regexp.MustCompile(`\d{2} \d{2}`)
+	jettingMeasurementRe  = regexp.MustCompile(`\d+[.,]\d+\s*(m|bar|°C|N|%)`)
+)
+
+func (jettingParser) Name() string { return JettingParserName }
+
+// jettingFullScore is the number of indicator points that make a Jetting export certain
+const jettingFullScore = 20
+
+// jettingIndicators are the weighted labels and units of the Jetting measurement table
+var jettingIndicators = []struct {
+	label  string
+	weight int
+}{
+	{"Streckenlänge", 2}, {"Geschwindigkeit", 2}, {"Drehmoment", 2}, {"Schubkraft", 2}, {"Uhrzeit", 1},
+	{"Rohr-Druck", 2}, {"Lufttemperatur", 1}, {"Einblasdruck", 1},
+	{"[hh:mm:ss]", 1}, {"Zeit - Dauer", 1}, {"[°C]", 1}, {"[N]", 1}, {"[%]", 1},
+}
+
+// Detect scores German column labels and units plus the Jetting file name
+// pattern "DD.MM.YYYY, HH MM, Location NVT XXXXX.pdf". Fremco device names
+// exclude the content, the file name alone can still identify an export.
+// Generic words like "bar" and "min" are no evidence on their own.
+func (jettingParser) Detect(text, filename string) Detection {
+	var score detectionScore
+	if strings.Contains(text, "Fremco") || strings.Contains(text, "SpeedNet") || strings.Contains(text, "MicroFlow") {
+		score.note("content ignored: Fremco device name found")
+	} else {
+		jettingContentIndicators(text, &score)
+	}
+
+	var hints detectionScore
+	if strings.Contains(filename, "NVT") && strings.Contains(filename, ",") {
+		hints.add(3, `file name "..., NVT ..."`)
+	}
+	if jettingFilenameDateRe.MatchString(filename) {
+		hints.add(1, "file name date DD.MM.YYYY")
+	}
+	if jettingFilenameTimeRe.MatchString(filename) {
+		hints.add(1, `file name time "HH MM"`)
+	}
+	if hints.points >= 3 {
+		score.points += hints.points
+		score.evidence = append(score.evidence, hints.evidence...)
+	}
+	return score.detection(JettingParserName, jettingFullScore)
+}
+
+// jettingContentIndicators scores the Jetting indicators found in the text
+func jettingContentIndicators(text string, score *detectionScore) {
+	for _, w := range jettingIndicators {
+		if strings.Contains(text, w.label) {
+			score.add(w.weight, strconv.Quote(w.label))
+		}
+	}
+	if strings.Contains(text, "Länge") && strings.Contains(text, "m/min") {
+		score.add(2, `"Länge" with "m/min"`)
+	}
+	if strings.Contains(text, "[m]") && strings.Contains(text, "[bar]") {
+		score.add(1, `"[m]" and "[bar]" units`)
+	}
+	if strings.Contains(text, "Protokoll") && (strings.Contains(text, "Meter") || strings.Contains(text, "Zeit")) {
+		score.add(1, `"Protokoll" with "Meter" or "Zeit"`)
+	}
+	if m := jettingMeasurementRe.FindString(text); m != "" && score.points > 0 {
+		score.add(1, fmt.Sprintf("measurement value %q", m))
+	}
+}
+
+func (jettingParser) Normalize(text string) string { return NormalizeJettingTxt(text) }
+
+func (jettingParser) Parse(normalized string) (*ParseResult, error) {
+	var diag parseLog
+	protocol := parseJettingProtocol(normalized, &diag)
+	return diag.result(protocol), nil
+}
+
+// Format implements Protocol
+func (p *JettingProtocol) Format() string { return JettingParserName }
+
+// DataPointCount implements Protocol
+func (p *JettingProtocol) DataPointCount() int { return len(p.Measurements.DataPoints) }
+
+// MaxLengthM implements Protocol
+func (p *JettingProtocol) MaxLengthM() float64 {
+	maxLength := 0.0
+	for _, dp := range p.Measurements.DataPoints {
+		maxLength = math.Max(maxLength, dp.LengthM)
+	}
+	return maxLength
+}
+
+// ApplyFilenameInfo takes date, start time and section from the file name where present
+func (p *JettingProtocol) ApplyFilenameInfo(info FilenameInfo) {
+	p.ExportMetadata.SourceFilename = info.Filename
+	if info.Date != "" {
+		p.ProtocolInfo.Date = info.Date
+	}
+	if info.Time != "" {
+		p.ProtocolInfo.StartTime = info.Time
+	}
+	if section := info.SectionNVT(); section != "" {
+		p.ProtocolInfo.SectionNVT = section
+	}
+}
+
+// jettingSourceColumns are the columns of the Jetting measurement table
+var jettingSourceColumns = []SourceColumn{
+	{Name: "Länge", Unit: "m", Field: "length_m"},
+	{Name: "Lufttemperatur", Unit: "°C", Field: "temperature_c"},
+	{Name: "Schubkraft", Unit: "N", Field: "force_n"},
+	{Name: "Einblasdruck", Unit: "bar", Field: "pressure_bar"},
+	{Name: "Geschwindigkeit", Unit: "m/min", Field: "speed_m_min"},
+	{Name: "Zeit - Dauer", Unit: "hh:mm:ss", Field: "time_duration"},
+}
+
+// MeasurementSeries implements Protocol, dating the elapsed times from the protocol start
+func (p *JettingProtocol) MeasurementSeries() MeasurementSeries {
+	measurements := make([]Measurement, 0, len(p.Measurements.DataPoints))
+	for i, dp := range p.Measurements.DataPoints {
+		temperature, force, pressure, speed := dp.TemperatureC, dp.ForceN, dp.PressureBar, dp.SpeedMMin
+		m := Measurement{
+			Sequence:     i + 1,
+			LengthM:      dp.LengthM,
+			SpeedMMin:    &speed,
+			PressureBar:  &pressure,
+			ForceN:       &force,
+			TemperatureC: &temperature,
+		}
+		if d, err := ParseElapsed(dp.TimeDuration); err == nil {
+			m.Elapsed = &d
+		}
+		measurements = append(measurements, m)
+	}
+	start, hasStart := parseProtocolStart(p.ProtocolInfo.Date, p.ProtocolInfo.StartTime)
+	completeMeasurementTimes(measurements, nil, start, hasStart)
+	return MeasurementSeries{Format: JettingParserName, SourceColumns: jettingSourceColumns, Measurements: measurements}
+}
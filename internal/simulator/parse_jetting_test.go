@@ -0,0 +1,293 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+)
+
+// jettingFormText is the equipment form of a Jetting protocol as laid out by
+// the PDF extraction, cells separated by at least two spaces
+const jettingFormText = `M.A.X. Bauservice
+Glasfaser Nord GmbH
+Jetting Protokoll
+Hersteller  Gabocom  Hersteller  Prysmian  Einblasgerät  Jetting
+Rohrtyp  SNR 7x1,5  Kabeltyp  A-D 2Y 1x6  Gleitmittel  Plumettaz
+Farbe / Kennung  rot  Kabel-Ø  2,5  Kompressor  M17
+Verlegungsart des Rohres  Graben  Faseranzahl  24  Ölabscheider  Ja ☒ Nein ☐
+Rohrinnendurchmesser  4  Kabeltrommel-Nr  T123  Nachkühler  Ja ☐ Nein ☒
+Roraußendurchmesser  7  Kabeleinblaskapp  Ja ☒ Nein ☐  Crash Test ausgeführt  Ja ☒ Nein ☐
+Rohrinnenwand  glatt ☒ gerieft ☐  Kabeltemperatur  12  Ermittelte Schubkraft  180 N
+Crash Test Geschwindigkeit  40 m/min  Crash Test Moment  35 Nm
+Datum: 16.05.2025
+Uhrzeit: 12:49
+Länge[m]
+Lufttemperatur[°C]
+Schubkraft[N]
+Einblasdruck[bar]
+Geschwindigkeit[m/min]
+Zeit - Dauer[hh:mm:ss]
+0
+20
+100
+8
+30
+00:00:01
+`
+
+func TestJettingFormValues(t *testing.T) {
+	values := jettingFormValues(strings.Split(jettingFormText, "\n"))
+
+	tests := []struct {
+		field string
+		want  string
+		line  int
+	}{
+		// The first "Hersteller" is the pipe's, the second the cable's
+		{"pipe_manufacturer", "Gabocom", 4},
+		{"cable_manufacturer", "Prysmian", 4},
+		{"device_model", "Jetting", 4},
+		{"cable_designation", "A-D 2Y 1x6", 5},
+		{"pipe_color_coding", "rot", 6},
+		{"compressor_oil_separator", "Ja ☒ Nein ☐", 7},
+		{"pipe_outer_diameter", "7", 9},
+		{"crash_test_performed", "Ja ☒ Nein ☐", 9},
+		{"pipe_inner_wall", "glatt ☒ gerieft ☐", 10},
+		// The push force and the torque of the crash test are separate fields
+		{"crash_test_force", "180 N", 10},
+		{"crash_test_speed", "40 m/min", 11},
+		{"crash_test_moment", "35 Nm", 11},
+	}
+	for _, tt := range tests {
+		got, ok := values[tt.field]
+		if !ok {
+			t.Errorf("%s: missing", tt.field)
+			continue
+		}
+		if got.text != tt.want || got.line != tt.line {
+			t.Errorf("%s = %q (line %d), want %q (line %d)", tt.field, got.text, got.line, tt.want, tt.line)
+		}
+	}
+	// Ignored labels end the value before them and are not stored
+	if _, ok := values[""]; ok {
+		t.Errorf("value of an ignored label stored: %q", values[""].text)
+	}
+	if got := values["pipe_color_coding"].text; got != "rot" {
+		t.Errorf("pipe_color_coding ran into the next label: %q", got)
+	}
+}
+
+func TestJettingFormValuesLabelWithColon(t *testing.T) {
+	lines := []string{
+		"Hersteller: Gabocom  Rohrtyp:  SNR 10x1",
+		"Hersteller  Prysmian",
+		"Hersteller  Lancier",
+		"Kabel-Ø",
+	}
+	values := jettingFormValues(lines)
+	if got := values["pipe_manufacturer"].text; got != "Gabocom" {
+		t.Errorf("pipe_manufacturer = %q, want Gabocom", got)
+	}
+	// A value in the next cell after "Label:" belongs to the label
+	if got := values["pipe_type"].text; got != "SNR 10x1" {
+		t.Errorf("pipe_type = %q, want SNR 10x1", got)
+	}
+	// Every further "Hersteller" is the cable's, the last one wins
+	if got := values["cable_manufacturer"].text; got != "Lancier" {
+		t.Errorf("cable_manufacturer = %q, want Lancier", got)
+	}
+	// A label without a value is no value
+	if v, ok := values["cable_diameter"]; ok {
+		t.Errorf("cable_diameter = %q for an empty field", v.text)
+	}
+}
+
+func TestJettingEquipmentFromForm(t *testing.T) {
+	quietLog(t)
+	eq := ParseJettingProtocol(jettingFormText).Equipment
+
+	str := func(name string, got *string, want string) {
+		t.Helper()
+		if got == nil || *got != want {
+			t.Errorf("%s = %v, want %q", name, got, want)
+		}
+	}
+	str("pipe manufacturer", eq.Pipe.Manufacturer, "Gabocom")
+	str("cable manufacturer", eq.Cable.Manufacturer, "Prysmian")
+	str("device model", eq.BlowingDevice.Model, "Jetting")
+	str("crash test speed", eq.BlowingDevice.CrashTestSpeed, "40 m/min")
+	str("crash test moment", eq.BlowingDevice.CrashTestMoment, "35 Nm")
+	str("crash test force", eq.BlowingDevice.CrashTestForce, "180 N")
+	str("pipe inner wall", eq.Pipe.InnerWall, "glatt")
+
+	checkbox := func(name string, got *bool, want bool) {
+		t.Helper()
+		if got == nil || *got != want {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	checkbox("crash test performed", eq.BlowingDevice.CrashTestPerformed, true)
+	checkbox("oil separator", eq.Compressor.OilSeparator, true)
+	checkbox("after cooler", eq.Compressor.AfterCooler, false)
+
+	if eq.Pipe.InnerDiameter == nil || *eq.Pipe.InnerDiameter != 4 || eq.Pipe.OuterDiameter == nil || *eq.Pipe.OuterDiameter != 7 {
+		t.Errorf("pipe diameters = %v / %v, want 4 / 7", eq.Pipe.InnerDiameter, eq.Pipe.OuterDiameter)
+	}
+	if eq.Cable.Diameter == nil || *eq.Cable.Diameter != 2.5 || eq.Cable.FiberCount == nil || *eq.Cable.FiberCount != 24 {
+		t.Errorf("cable diameter %v, fiber count %v, want 2.5 and 24", eq.Cable.Diameter, eq.Cable.FiberCount)
+	}
+}
+
+func TestJettingCheckbox(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		value string
+		want  *bool
+	}{
+		{"Ja ☒ Nein ☐", &yes},
+		{"Ja ☐ Nein ☒", &no},
+		{"Nein", &no},
+		{"ja", &yes},
+		{"☒", &yes},
+		{"Ja ☐ Nein ☐", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := jettingCheckbox(tt.value)
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("jettingCheckbox(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestExtractJettingSummary(t *testing.T) {
+	// The logged Lufttemperatur is the blowing air after the compressor
+	points := []JettingDataPoint{
+		{LengthM: 0, TemperatureC: 31, TimeDuration: "00:00:00"},
+		{LengthM: 250.4, TemperatureC: 34, TimeDuration: "00:04:10"},
+		{LengthM: 612.6, TemperatureC: 36, TimeDuration: "00:09:58"},
+	}
+
+	tests := []struct {
+		name        string
+		form        string
+		points      []JettingDataPoint
+		start, end  *int
+		distance    *int
+		blowingTime string
+		temperature *float64
+		humidity    *float64
+		warnings    int
+	}{
+		{
+			name: "complete form",
+			form: "Start  1.200 m  Ende  1.815 m  Streckenlänge  610 m\n" +
+				"Zeit - Dauer [hh:mm:ss]  00:10:02  Umgebungsluft temp  18,5 °C  Luftfeuchtigkeit  65 %",
+			points: points, start: ptr(1200), end: ptr(1815), distance: ptr(610), blowingTime: "00:10:02",
+			temperature: ptr(18.5), humidity: ptr(65.0),
+		},
+		{
+			name:   "distance from the meter readings",
+			form:   "Start  1815  Ende  1200",
+			points: points, start: ptr(1815), end: ptr(1200), distance: ptr(615), blowingTime: "00:09:58",
+		},
+		{
+			name:   "no ambient temperature in the form, none from the data points",
+			form:   "Streckenlänge  610 m",
+			points: points, distance: ptr(610), blowingTime: "00:09:58",
+		},
+		{
+			name:   "empty form, length and time from the data points",
+			points: points, distance: ptr(613), blowingTime: "00:09:58",
+		},
+		{
+			name: "empty form without data points",
+		},
+		{
+			name:   "unreadable values",
+			form:   "Umgebungslufttemperatur  warm  Zeit - Dauer [hh:mm:ss]  lang",
+			points: points, distance: ptr(613), blowingTime: "00:09:58", warnings: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(tt.form, "\n")
+			diag := &parseLog{}
+			meters, summary := extractJettingSummary(lines, jettingFormValues(lines), tt.points, diag)
+
+			check := func(name string, ok bool, got, want interface{}) {
+				t.Helper()
+				if !ok {
+					t.Errorf("%s = %v, want %v", name, got, want)
+				}
+			}
+			check("meter start", equalPtr(meters.Start, tt.start), deref(meters.Start), deref(tt.start))
+			check("meter end", equalPtr(meters.End, tt.end), deref(meters.End), deref(tt.end))
+			check("distance", equalPtr(summary.Distance, tt.distance), deref(summary.Distance), deref(tt.distance))
+			check("ambient temperature", equalPtr(summary.Weather.Temperature, tt.temperature),
+				deref(summary.Weather.Temperature), deref(tt.temperature))
+			check("humidity", equalPtr(summary.Weather.Humidity, tt.humidity), deref(summary.Weather.Humidity), deref(tt.humidity))
+			if got := deref(summary.BlowingTime); got != tt.blowingTime {
+				t.Errorf("blowing time = %q, want %q", got, tt.blowingTime)
+			}
+			if len(diag.warnings) != tt.warnings {
+				t.Errorf("warnings = %v, want %d", diag.warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestJettingAmbientTemperatureOnlyFromForm(t *testing.T) {
+	quietLog(t)
+	m := ParseJettingProtocol(jettingFormText).Measurements
+	if len(m.DataPoints) == 0 || m.DataPoints[0].TemperatureC != 20 {
+		t.Fatalf("data points = %+v, want a Lufttemperatur of 20", m.DataPoints)
+	}
+	if temp := m.Summary.Weather.Temperature; temp != nil {
+		t.Errorf("ambient temperature = %v, want none without a form value", *temp)
+	}
+
+	m = ParseJettingProtocol(jettingFormText + "Umgebungsluft temp  11 °C\n").Measurements
+	if temp := m.Summary.Weather.Temperature; temp == nil || *temp != 11 {
+		t.Errorf("ambient temperature = %v, want 11 from the form", deref(temp))
+	}
+}
+
+func TestExtractJettingSummaryGPS(t *testing.T) {
+	tests := []struct {
+		line                          string
+		latitude, longitude, altitude *float64
+	}{
+		{"Ort(GPS)  52.4914°;9.85174°;95.8000 m", ptr(52.4914), ptr(9.85174), ptr(95.8)},
+		{"Ort (GPS)  52,4914° ; 9,85174°", ptr(52.4914), ptr(9.85174), nil},
+		{"Ort(GPS)", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		lines := []string{tt.line}
+		_, summary := extractJettingSummary(lines, jettingFormValues(lines), nil, nil)
+		gps := summary.GPSLocation
+		if !equalPtr(gps.Latitude, tt.latitude) || !equalPtr(gps.Longitude, tt.longitude) || !equalPtr(gps.Altitude, tt.altitude) {
+			t.Errorf("%q: GPS = %v;%v;%v", tt.line, deref(gps.Latitude), deref(gps.Longitude), deref(gps.Altitude))
+		}
+	}
+}
+
+// ptr returns a pointer to v
+func ptr[T any](v T) *T { return &v }
+
+// deref returns the value of a pointer or the zero value for nil
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+// equalPtr reports whether both pointers are nil or point to equal values
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,294 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PlumettazParserName is the registry name of the Plumettaz parser
+const PlumettazParserName = "plumettaz"
+
+func init() {
+	RegisterParser(plumettazParser{})
+}
+
+// plumettazParser parses Plumettaz blowing machine runs. The PDF report and the
+// data logger CSV export carry the same job header ("Label: value" lines, or
+// "Label;value" cells in the CSV) and the same log columns, the CSV separated by
+// semicolons with decimal commas. Columns are found by name and unit and
+// converted to the units of the other formats: speed in m/min, force in N,
+// pressure in bar.
+type plumettazParser struct{}
+
+func (plumettazParser) Name() string { return PlumettazParserName }
+
+// plumettazFullScore is the number of indicator points that make a Plumettaz export certain
+const plumettazFullScore = 6
+
+// plumettazModelRe matches Plumettaz machine names ("MiniJet 400", "SuperJet")
+var plumettazModelRe = regexp.MustCompile(`\b(?:Micro|Mini|Mega|Super|Ultimate|Hydro)Jet(?:\s+\d+\w*)?`)
+
+// Detect scores the manufacturer and machine names. Log columns with units
+// found in Plumettaz exports only count together with one of them, as the
+// Fremco and Jetting tables have length, speed and pressure columns as well.
+func (plumettazParser) Detect(text, filename string) Detection {
+	var score detectionScore
+	if strings.Contains(strings.ToLower(text), "plumettaz") {
+		score.add(4, `"Plumettaz"`)
+	}
+	if m := plumettazModelRe.FindString(text); m != "" {
+		score.add(2, fmt.Sprintf("machine %q", m))
+	}
+	if score.points > 0 {
+		if layout, ok := findLogHeader(strings.Split(text, "\n")); ok {
+			score.add(1, fmt.Sprintf("log columns %s", layout))
+		}
+		if strings.Contains(text, "daN") || strings.Contains(text, "m/s") {
+			score.add(1, `"daN" or "m/s" units`)
+		}
+	}
+	return score.detection(PlumettazParserName, plumettazFullScore)
+}
+
+// Normalize removes the byte order mark of CSV exports and empty lines
+func (plumettazParser) Normalize(text string) string {
+	return NormalizeFremcoTxt(strings.TrimPrefix(text, "\ufeff"))
+}
+
+func (plumettazParser) Parse(normalized string) (*ParseResult, error) {
+	var diag parseLog
+	protocol := parsePlumettazProtocol(normalized, &diag)
+	return diag.result(protocol), nil
+}
+
+// ParsePlumettazProtocol extracts the protocol from a normalized Plumettaz PDF report or CSV log
+func ParsePlumettazProtocol(normalized string) *PlumettazProtocol {
+	return parsePlumettazProtocol(normalized, nil)
+}
+
+// plumettazDuctRe matches a duct size printed as outer/inner diameter ("14/10", "10/8 mm")
+var plumettazDuctRe = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*/\s*(\d+(?:[.,]\d+)?)\s*(?:mm)?$`)
+
+// parsePlumettazProtocol parses the protocol, recording unreadable values in diag
+func parsePlumettazProtocol(normalized string, diag *parseLog) *PlumettazProtocol {
+	protocol := &PlumettazProtocol{
+		ProtocolInfo: PlumettazProtocolInfo{
+			System:       "Plumettaz",
+			DocumentType: "Blowing report",
+		},
+		Measurements: PlumettazMeasurements{
+			DataPoints: []PlumettazDataPoint{},
+		},
+		ExportMetadata: PlumettazExportMetadata{
+			ParsedAt:      time.Now(),
+			ParserVersion: "1.0.0",
+			SourceFormat:  "pdf",
+		},
+	}
+
+	lines := strings.Split(normalized, "\n")
+	layout, ok := findLogHeader(lines)
+	if ok {
+		if layout.separator != "" {
+			protocol.ProtocolInfo.DocumentType = "Data log"
+			protocol.ExportMetadata.SourceFormat = "csv"
+		}
+		protocol.Measurements.DataPoints = plumettazDataPoints(readLogTable(lines, &layout, diag))
+		protocol.ExportMetadata.Columns = layout.columns
+	} else {
+		diag.fail(0, "data_points", "", "no log columns found (length and two of time, speed, force, pressure, temperature, with units)")
+	}
+	if ok && len(protocol.Measurements.DataPoints) == 0 {
+		diag.fail(0, "data_points", "", "no measurements found")
+	}
+
+	for _, v := range readLogHeaderValues(lines, layout) {
+		setPlumettazField(protocol, diag, v.line, v.field, v.value)
+	}
+	if protocol.Equipment.Machine.Model == "" {
+		protocol.Equipment.Machine.Model = plumettazModelRe.FindString(normalized)
+	}
+
+	// Summary values the report does not print are computed from the log
+	points := protocol.Measurements.DataPoints
+	summary := &protocol.Measurements.Summary
+	if summary.Distance == nil && len(points) > 0 {
+		distance := int(math.Round(protocol.MaxLengthM()))
+		summary.Distance = &distance
+	}
+	if summary.BlowingTime == nil && len(points) > 0 && points[len(points)-1].TimeDuration != "" {
+		summary.BlowingTime = &points[len(points)-1].TimeDuration
+	}
+	return protocol
+}
+
+// setPlumettazField stores one job header value, recording unreadable numbers as warnings
+func setPlumettazField(protocol *PlumettazProtocol, diag *parseLog, line int, field, value string) {
+	info := &protocol.ProtocolInfo
+	equipment := &protocol.Equipment
+	summary := &protocol.Measurements.Summary
+	switch field {
+	case "model":
+		equipment.Machine.Model = value
+	case "serial":
+		equipment.Machine.SerialNumber = value
+	case "project":
+		info.ProjectNumber = value
+	case "section":
+		info.SectionNVT = value
+	case "company":
+		info.Company = NormalizeCompany(value)
+	case "service_provider":
+		info.ServiceProvider = NormalizeCompany(value)
+	case "operator":
+		info.Operator = value
+	case "date":
+		info.Date = value
+	case "start_time":
+		info.StartTime = value
+	case "remarks":
+		info.Remarks = value
+	case "duct":
+		equipment.Duct.Type = value
+		if manufacturer, ok := FindKnownCompany(value, CompanyRoleManufacturer); ok {
+			equipment.Duct.Manufacturer = manufacturer
+		}
+		if m := plumettazDuctRe.FindStringSubmatch(value); m != nil {
+			outer, err1 := ParseDecimal(m[1])
+			inner, err2 := ParseDecimal(m[2])
+			if err1 == nil && err2 == nil && inner < outer {
+				equipment.Duct.OuterDiameter, equipment.Duct.InnerDiameter = &outer, &inner
+			}
+		} else if outer, wall, ok := ParsePipeDimensions(value); ok {
+			inner := outer - 2*wall
+			equipment.Duct.OuterDiameter, equipment.Duct.InnerDiameter = &outer, &inner
+		}
+	case "cable":
+		equipment.Cable.Designation = value
+		if manufacturer, ok := FindKnownCompany(value, CompanyRoleManufacturer); ok {
+			equipment.Cable.Manufacturer = manufacturer
+		}
+	case "cable_diameter":
+		if diameter, unit, err := ParseQuantity(strings.TrimPrefix(strings.TrimSpace(value), "Ø")); err == nil && (unit == "" || unit == "mm") {
+			equipment.Cable.Diameter = &diameter
+		} else {
+			diag.warn(line, "cable_diameter", value, "no diameter in mm")
+		}
+	case "fiber_count":
+		if count, err := ParseCount(value); err == nil {
+			equipment.Cable.FiberCount = &count
+		} else {
+			diag.warn(line, "fiber_count", value, "not a fiber count")
+		}
+	case "lubricant":
+		equipment.Cable.Lubricant = value
+	case "compressor":
+		equipment.Compressor.Model = value
+	case "distance":
+		if distance, unit, err := ParseQuantity(value); err == nil && (unit == "" || unit == "m") {
+			d := int(math.Round(distance))
+			summary.Distance = &d
+		} else {
+			diag.warn(line, "distance", value, "no length in m")
+		}
+	case "blowing_time":
+		if d, err := ParseElapsed(value); err == nil {
+			blowingTime := FormatElapsed(d)
+			summary.BlowingTime = &blowingTime
+		} else {
+			diag.warn(line, "blowing_time", value, "not a duration")
+		}
+	case "temperature":
+		if temp, unit, err := ParseQuantity(value); err == nil && (unit == "" || strings.HasPrefix(unit, "°C")) {
+			summary.AmbientTemperature = &temp
+		} else {
+			diag.warn(line, "ambient_temperature", value, "no temperature in °C")
+		}
+	}
+}
+
+// plumettazDataPoints converts the logged rows to data points
+func plumettazDataPoints(rows []logRow) []PlumettazDataPoint {
+	points := make([]PlumettazDataPoint, 0, len(rows))
+	for _, row := range rows {
+		point := PlumettazDataPoint{
+			LengthM:      row.lengthM,
+			SpeedMMin:    row.speedMMin,
+			PressureBar:  row.pressureBar,
+			ForceN:       row.forceN,
+			TemperatureC: row.temperatureC,
+		}
+		if row.elapsed != nil {
+			point.TimeDuration = FormatElapsed(*row.elapsed)
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// Format implements Protocol
+func (p *PlumettazProtocol) Format() string { return PlumettazParserName }
+
+// DataPointCount implements Protocol
+func (p *PlumettazProtocol) DataPointCount() int { return len(p.Measurements.DataPoints) }
+
+// MaxLengthM implements Protocol
+func (p *PlumettazProtocol) MaxLengthM() float64 {
+	maxLength := 0.0
+	for _, dp := range p.Measurements.DataPoints {
+		maxLength = math.Max(maxLength, dp.LengthM)
+	}
+	return maxLength
+}
+
+// FillCheck checks the recorded cable against the recorded duct
+func (p *PlumettazProtocol) FillCheck() FillCheck {
+	var diameter, inner float64
+	if p.Equipment.Cable.Diameter != nil {
+		diameter = *p.Equipment.Cable.Diameter
+	}
+	if p.Equipment.Duct.InnerDiameter != nil {
+		inner = *p.Equipment.Duct.InnerDiameter
+	}
+	return CheckCableDuctFill(diameter, inner)
+}
+
+// ApplyFilenameInfo records the file name and fills header fields the report
+// does not print; Plumettaz file names follow no fixed pattern, so the report wins
+func (p *PlumettazProtocol) ApplyFilenameInfo(info FilenameInfo) {
+	p.ExportMetadata.SourceFilename = info.Filename
+	if p.ProtocolInfo.Date == "" {
+		p.ProtocolInfo.Date = info.Date
+	}
+	if p.ProtocolInfo.StartTime == "" {
+		p.ProtocolInfo.StartTime = info.Time
+	}
+	if p.ProtocolInfo.SectionNVT == "" {
+		p.ProtocolInfo.SectionNVT = info.SectionNVT()
+	}
+}
+
+// MeasurementSeries implements Protocol, with the logged columns as source columns
+func (p *PlumettazProtocol) MeasurementSeries() MeasurementSeries {
+	measurements := make([]Measurement, 0, len(p.Measurements.DataPoints))
+	for i, dp := range p.Measurements.DataPoints {
+		m := Measurement{
+			Sequence:     i + 1,
+			LengthM:      dp.LengthM,
+			SpeedMMin:    dp.SpeedMMin,
+			PressureBar:  dp.PressureBar,
+			ForceN:       dp.ForceN,
+			TemperatureC: dp.TemperatureC,
+		}
+		if d, err := ParseElapsed(dp.TimeDuration); err == nil {
+			m.Elapsed = &d
+		}
+		measurements = append(measurements, m)
+	}
+	start, hasStart := parseProtocolStart(p.ProtocolInfo.Date, p.ProtocolInfo.StartTime)
+	completeMeasurementTimes(measurements, nil, start, hasStart)
+	return MeasurementSeries{Format: PlumettazParserName, SourceColumns: p.ExportMetadata.Columns, Measurements: measurements}
+}
@@ -0,0 +1,234 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+)
+
+// parsePlumettaz parses a Plumettaz report or log through the registered parser
+func parsePlumettaz(t *testing.T, text string) *ParseResult {
+	t.Helper()
+	result, _, err := ParseWith(LookupParser(PlumettazParserName), text, ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+const plumettazReportText = `Plumettaz SA
+Blowing report
+Machine: MiniJet 400
+Serial number: MJ4-20417
+Job: 2025-118
+Site: Lindenstr 12 / NVT 2V1100
+Customer: Wolken-ASM
+Contractor: Tiefbau Nord
+Date: 14.06.2025
+Start time: 09:12
+Duct: Gabocom 14/10
+Cable: Prysmian 96F
+Cable diameter: Ø 6,5 mm
+Fibres: 96
+Total length: 412 m
+Duration: 00:31:40
+Temperature: 18,5 °C
+Remarks:
+Cable reached the hand hole
+Time [s] Distance [m] Speed [km/h] Push force [kN] Pressure [psi]
+0 0,0 0,0 0,05 145
+30 25,0 3,0 0,12 150
+Page 1 of 2
+Plumettaz SA Blowing report
+Time [s] Distance [m] Speed [km/h] Push force [kN] Pressure [psi]
+60 50,0 3,6 0,15 150
+Page 2 of 2`
+
+func TestPlumettazCSV(t *testing.T) {
+	result := parsePlumettaz(t, plumettazLogCSV)
+	if len(result.Errors) != 0 || len(result.Warnings) != 0 {
+		t.Fatalf("diagnostics %v", result.Diagnostics())
+	}
+	p := result.Protocol.(*PlumettazProtocol)
+	info, machine := p.ProtocolInfo, p.Equipment.Machine
+	if info.DocumentType != "Data log" || p.ExportMetadata.SourceFormat != "csv" {
+		t.Errorf("document type %q, source format %q", info.DocumentType, p.ExportMetadata.SourceFormat)
+	}
+	if machine.Model != "MiniJet 400" || machine.SerialNumber != "MJ4-20417" || info.Date != "14.06.2025" || info.StartTime != "09:12:05" {
+		t.Errorf("header %+v, machine %+v", info, machine)
+	}
+
+	// Speed in m/s and push force in daN are stored in m/min and N
+	want := []PlumettazDataPoint{
+		{LengthM: 0, SpeedMMin: ptr(0.0), ForceN: ptr(0.0), PressureBar: ptr(10.2), TimeDuration: "00:00:00"},
+		{LengthM: 8.5, SpeedMMin: ptr(51.0), ForceN: ptr(21.0), PressureBar: ptr(10.3), TimeDuration: "00:00:10"},
+		{LengthM: 17.9, SpeedMMin: ptr(56.4), ForceN: ptr(24.0), PressureBar: ptr(10.3), TimeDuration: "00:00:20"},
+	}
+	comparePlumettazPoints(t, p.Measurements.DataPoints, want)
+
+	// The log has no summary, it is computed from the rows
+	summary := p.Measurements.Summary
+	if deref(summary.Distance) != 18 || deref(summary.BlowingTime) != "00:00:20" || summary.AmbientTemperature != nil {
+		t.Errorf("summary %d m, %q, %v", deref(summary.Distance), deref(summary.BlowingTime), summary.AmbientTemperature)
+	}
+}
+
+func TestPlumettazReport(t *testing.T) {
+	withKnownCompanies(t, defaultKnownCompanies)
+	result := parsePlumettaz(t, plumettazReportText)
+	if len(result.Errors) != 0 || len(result.Warnings) != 0 {
+		t.Fatalf("diagnostics %v", result.Diagnostics())
+	}
+	p := result.Protocol.(*PlumettazProtocol)
+	info, eq := p.ProtocolInfo, p.Equipment
+
+	if info.DocumentType != "Blowing report" || p.ExportMetadata.SourceFormat != "pdf" {
+		t.Errorf("document type %q, source format %q", info.DocumentType, p.ExportMetadata.SourceFormat)
+	}
+	wantInfo := PlumettazProtocolInfo{
+		System:          "Plumettaz",
+		DocumentType:    "Blowing report",
+		Date:            "14.06.2025",
+		StartTime:       "09:12",
+		ProjectNumber:   "2025-118",
+		SectionNVT:      "Lindenstr 12 / NVT 2V1100",
+		Company:         "Wolken-ASM GmbH",
+		ServiceProvider: "Tiefbau Nord",
+		Remarks:         "Cable reached the hand hole",
+	}
+	if info != wantInfo {
+		t.Errorf("protocol info\n got %+v\nwant %+v", info, wantInfo)
+	}
+	if eq.Machine.Model != "MiniJet 400" || eq.Machine.SerialNumber != "MJ4-20417" {
+		t.Errorf("machine %+v", eq.Machine)
+	}
+	if eq.Duct.Manufacturer != "Gabocom" || deref(eq.Duct.OuterDiameter) != 14 || deref(eq.Duct.InnerDiameter) != 10 {
+		t.Errorf("duct %q %v/%v", eq.Duct.Manufacturer, deref(eq.Duct.OuterDiameter), deref(eq.Duct.InnerDiameter))
+	}
+	if eq.Cable.Manufacturer != "Prysmian" || deref(eq.Cable.Diameter) != 6.5 || deref(eq.Cable.FiberCount) != 96 {
+		t.Errorf("cable %q, %v mm, %d fibers", eq.Cable.Manufacturer, deref(eq.Cable.Diameter), deref(eq.Cable.FiberCount))
+	}
+
+	// The printed summary wins over the rows
+	summary := p.Measurements.Summary
+	if deref(summary.Distance) != 412 || deref(summary.BlowingTime) != "00:31:40" || deref(summary.AmbientTemperature) != 18.5 {
+		t.Errorf("summary %d m, %q, %v °C", deref(summary.Distance), deref(summary.BlowingTime), deref(summary.AmbientTemperature))
+	}
+
+	// Page footers, page headers and the repeated column header are skipped;
+	// km/h, kN and psi are converted
+	want := []PlumettazDataPoint{
+		{LengthM: 0, SpeedMMin: ptr(0.0), ForceN: ptr(50.0), PressureBar: ptr(9.997402), TimeDuration: "00:00:00"},
+		{LengthM: 25, SpeedMMin: ptr(50.0), ForceN: ptr(120.0), PressureBar: ptr(10.34214), TimeDuration: "00:00:30"},
+		{LengthM: 50, SpeedMMin: ptr(60.0), ForceN: ptr(150.0), PressureBar: ptr(10.34214), TimeDuration: "00:01:00"},
+	}
+	comparePlumettazPoints(t, p.Measurements.DataPoints, want)
+}
+
+func TestPlumettazDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		points   int
+		errors   []string // fields of the expected errors
+		warnings []string // fields of the expected warnings
+	}{
+		{
+			name:   "unsupported unit",
+			text:   "Plumettaz\nDistance [m];Speed [knots];Push force [daN]\n1,0;2,0;3,0\n2,0;2,0;3,5\n",
+			points: 2,
+			errors: []string{"table_header"},
+		},
+		{
+			name:     "column without unit",
+			text:     "Plumettaz\nDistance [m];Speed;Push force [daN]\n1,0;30;3,0\n",
+			points:   1,
+			warnings: []string{"speed_m_min"},
+		},
+		{
+			name:   "unreadable cell",
+			text:   "Plumettaz\nDistance [m];Speed [m/min];Push force [daN]\n1,0;30;3,0\n2,0;3x;3,5\n3,0;31;3,5\n",
+			points: 2,
+			errors: []string{"speed_m_min"},
+		},
+		{
+			name:   "row with a missing cell",
+			text:   "Plumettaz\nDistance [m];Speed [m/min];Push force [daN]\n1,0;30;3,0\n2,0;31\n",
+			points: 1,
+			errors: []string{"row"},
+		},
+		{
+			name:     "unreadable header values",
+			text:     "Plumettaz\nFibres;many\nTemperature;warm\nDistance [m];Speed [m/min];Push force [daN]\n1,0;30;3,0\n",
+			points:   1,
+			warnings: []string{"fiber_count", "ambient_temperature"},
+		},
+		{
+			name:   "no log columns",
+			text:   "Plumettaz MiniJet 400\nSerial number: MJ4-20417\n",
+			errors: []string{"data_points"},
+		},
+		{
+			name:   "header without rows",
+			text:   "Plumettaz\nDistance [m];Speed [m/min];Push force [daN]\n",
+			errors: []string{"data_points"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parsePlumettaz(t, tt.text)
+			if got := result.Protocol.DataPointCount(); got != tt.points {
+				t.Errorf("%d data points, want %d", got, tt.points)
+			}
+			fields := func(d []Diagnostic) string {
+				var f []string
+				for _, x := range d {
+					f = append(f, x.Field)
+				}
+				return strings.Join(f, " ")
+			}
+			if got, want := fields(result.Errors), strings.Join(tt.errors, " "); got != want {
+				t.Errorf("errors %v, want %q", result.Errors, want)
+			}
+			if got, want := fields(result.Warnings), strings.Join(tt.warnings, " "); got != want {
+				t.Errorf("warnings %v, want %q", result.Warnings, want)
+			}
+		})
+	}
+
+	// A column with an unsupported unit is not read
+	p := parsePlumettaz(t, tests[0].text).Protocol.(*PlumettazProtocol)
+	if dp := p.Measurements.DataPoints[0]; dp.SpeedMMin != nil || deref(dp.ForceN) != 30 {
+		t.Errorf("data point %+v, want no speed and 30 N", dp)
+	}
+}
+
+// comparePlumettazPoints compares parsed data points with the expected ones
+func comparePlumettazPoints(t *testing.T, got, want []PlumettazDataPoint) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%d data points, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.LengthM != w.LengthM || g.TimeDuration != w.TimeDuration ||
+			!equalPtr(g.SpeedMMin, w.SpeedMMin) || !equalPtr(g.ForceN, w.ForceN) ||
+			!equalPtr(g.PressureBar, w.PressureBar) || !equalPtr(g.TemperatureC, w.TemperatureC) {
+			t.Errorf("data point %d = %s, want %s", i, formatPlumettazPoint(g), formatPlumettazPoint(w))
+		}
+	}
+}
+
+// formatPlumettazPoint prints a data point with its optional values
+func formatPlumettazPoint(dp PlumettazDataPoint) string {
+	var b strings.Builder
+	b.WriteString(formatValue(dp.LengthM) + " m")
+	for _, v := range []struct {
+		value *float64
+		unit  string
+	}{{dp.SpeedMMin, "m/min"}, {dp.ForceN, "N"}, {dp.PressureBar, "bar"}, {dp.TemperatureC, "°C"}} {
+		if v.value != nil {
+			b.WriteString(" " + formatValue(*v.value) + " " + v.unit)
+		}
+	}
+	return b.String() + " " + dp.TimeDuration
+}
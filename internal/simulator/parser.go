@@ -0,0 +1,246 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Format detection thresholds. Files below MinDetectionConfidence, or whose best
+// format is not clearly ahead of the runner-up, are held for review instead of parsed.
+const (
+	MinDetectionConfidence = 0.5
+	MinDetectionLead       = 0.2
+)
+
+// Protocol is a parsed protocol of any supported format
+type Protocol interface {
+	Format() string      // name of the parser that produced it
+	DataPointCount() int // number of measured data points
+	MaxLengthM() float64 // longest installed length of the data points
+	FillCheck() FillCheck
+	// ApplyFilenameInfo fills header fields from the upload's file name
+	ApplyFilenameInfo(info FilenameInfo)
+	// MeasurementSeries returns the data points in the units shared by all formats
+	MeasurementSeries() MeasurementSeries
+}
+
+// ProtocolParser detects and parses one protocol format from extracted PDF text
+type ProtocolParser interface {
+	Name() string
+	// Detect scores how likely the text is of this format and lists the matched indicators
+	Detect(text, filename string) Detection
+	// Normalize prepares extracted text for Parse
+	Normalize(text string) string
+	// Parse returns the protocol with the values it could not read
+	Parse(normalized string) (*ParseResult, error)
+}
+
+var parserRegistry = struct {
+	sync.RWMutex
+	parsers []ProtocolParser
+}{}
+
+// RegisterParser adds a parser to the registry. Parsers registered first win ties.
+func RegisterParser(p ProtocolParser) {
+	parserRegistry.Lock()
+	defer parserRegistry.Unlock()
+	parserRegistry.parsers = append(parserRegistry.parsers, p)
+}
+
+// Parsers returns the registered parsers in registration order
+func Parsers() []ProtocolParser {
+	parserRegistry.RLock()
+	defer parserRegistry.RUnlock()
+	return append([]ProtocolParser(nil), parserRegistry.parsers...)
+}
+
+// LookupParser returns the registered parser with the given name, or nil
+func LookupParser(name string) ProtocolParser {
+	for _, p := range Parsers() {
+		if strings.EqualFold(p.Name(), name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Detection is how well a file matches one protocol format
+type Detection struct {
+	Parser     string   `json:"parser"`
+	Confidence float64  `json:"confidence"` // 0 = not this format, 1 = certain
+	Evidence   []string `json:"evidence"`   // indicators found in the text or file name
+}
+
+// detectionScore collects weighted evidence for a Detection
+type detectionScore struct {
+	points   int
+	evidence []string
+}
+
+// add records an indicator worth the given points
+func (s *detectionScore) add(points int, evidence string) {
+	s.points += points
+	s.evidence = append(s.evidence, fmt.Sprintf("%s (%+d)", evidence, points))
+}
+
+// note records an observation that does not change the score
+func (s *detectionScore) note(evidence string) {
+	s.evidence = append(s.evidence, evidence)
+}
+
+// detection converts the points to a confidence, full points meaning certain
+func (s *detectionScore) detection(parser string, full int) Detection {
+	confidence := 0.0
+	if s.points > 0 {
+		confidence = round(math.Min(float64(s.points)/float64(full), 1), 2)
+	}
+	evidence := s.evidence
+	if evidence == nil {
+		evidence = []string{}
+	}
+	return Detection{Parser: parser, Confidence: confidence, Evidence: evidence}
+}
+
+// FormatDetection ranks all registered formats for one file
+type FormatDetection struct {
+	Format       string         `json:"format"`     // best candidate, empty if no format matched at all
+	Confidence   float64        `json:"confidence"` // confidence of the best candidate
+	Candidates   []Detection    `json:"candidates"` // best first
+	Review       bool           `json:"review"`     // detection too weak or ambiguous to parse unattended
+	ReviewReason string         `json:"review_reason,omitempty"`
+	Parser       ProtocolParser `json:"-"` // parser of the best candidate, nil if no format matched
+}
+
+// DetectFormat scores the text against every registered parser. The best
+// candidate is only accepted without review if its confidence reaches
+// MinDetectionConfidence and it leads the runner-up by MinDetectionLead.
+func DetectFormat(text, filename string) FormatDetection {
+	parsers := Parsers()
+	result := FormatDetection{Candidates: make([]Detection, len(parsers))}
+	for i, p := range parsers {
+		result.Candidates[i] = p.Detect(text, filename)
+	}
+	// Stable so that parsers registered first win ties
+	order := make([]int, len(parsers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return result.Candidates[order[a]].Confidence > result.Candidates[order[b]].Confidence
+	})
+	ranked := make([]Detection, len(order))
+	for i, idx := range order {
+		ranked[i] = result.Candidates[idx]
+	}
+	result.Candidates = ranked
+
+	if len(ranked) == 0 || ranked[0].Confidence == 0 {
+		result.Review = true
+		result.ReviewReason = "no known protocol format matched"
+		return result
+	}
+	best := ranked[0]
+	result.Format = best.Parser
+	result.Confidence = best.Confidence
+	result.Parser = parsers[order[0]]
+	if best.Confidence < MinDetectionConfidence {
+		result.Review = true
+		result.ReviewReason = fmt.Sprintf("low confidence %.2f for %s (minimum %.2f)", best.Confidence, best.Parser, MinDetectionConfidence)
+	} else if len(ranked) > 1 && best.Confidence-ranked[1].Confidence < MinDetectionLead {
+		result.Review = true
+		result.ReviewReason = fmt.Sprintf("ambiguous: %s %.2f vs %s %.2f", best.Parser, best.Confidence, ranked[1].Parser, ranked[1].Confidence)
+	}
+	return result
+}
+
+// ParseWith normalizes the extracted text and parses it with the given parser.
+// In strict mode a protocol with unreadable required values is rejected: the
+// result is returned for its diagnostics together with an error.
+func ParseWith(p ProtocolParser, text string, opts ParseOptions) (*ParseResult, string, error) {
+	normalized := p.Normalize(text)
+	result, err := p.Parse(normalized)
+	if err != nil {
+		return nil, normalized, err
+	}
+	if opts.Strict && len(result.Errors) > 0 {
+		return result, normalized, fmt.Errorf("strict mode: %d parse errors, first at %s", len(result.Errors), result.Errors[0])
+	}
+	return result, normalized, nil
+}
+
+// FilenameInfo holds the protocol header fields encoded in an export file name
+type FilenameInfo struct {
+	Filename string `json:"filename"`
+	Project  string `json:"project"`
+	Date     string `json:"date"` // DD.MM.YYYY
+	Time     string `json:"time"` // HH:MM
+	Address  string `json:"address"`
+	NVT      string `json:"nvt"`
+}
+
+// SectionNVT returns "address / NVT" as stored in the protocol header
+func (f FilenameInfo) SectionNVT() string {
+	if f.Address != "" && f.NVT != "" {
+		return f.Address + " / " + f.NVT
+	}
+	return f.Address
+}
+
+// ParseProtocolFilename extracts the header fields from an export file name.
+// Jetting exports are named "29.10.2025, 11 20, Eiermarkt 15 B NVT 1V2200.pdf",
+// Fremco exports "SM209214964_2025-10-22 10_51_Oldenburger Koppel_10_NVT1V3400.pdf".
+func ParseProtocolFilename(filename string) FilenameInfo {
+	info := FilenameInfo{Filename: filename}
+	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
+
+	if strings.Contains(baseName, ",") {
+		parts := strings.Split(baseName, ",")
+		if len(parts) < 3 {
+			return info
+		}
+		info.Date = strings.TrimSpace(parts[0])
+		// "11 20" -> "11:20"
+		timeRaw := strings.TrimSpace(parts[1])
+		if timeParts := strings.Fields(timeRaw); len(timeParts) == 2 {
+			info.Time = timeParts[0] + ":" + timeParts[1]
+		} else {
+			info.Time = timeRaw
+		}
+		rest := strings.TrimSpace(parts[2])
+		if nvtIdx := strings.Index(rest, "NVT "); nvtIdx != -1 {
+			info.Address = strings.TrimSpace(rest[:nvtIdx])
+			info.NVT = strings.TrimSpace(rest[nvtIdx+4:])
+		} else {
+			info.Address = rest
+		}
+		return info
+	}
+
+	parts := strings.Split(baseName, "_")
+	if len(parts) < 4 {
+		return info
+	}
+	info.Project = parts[0]
+	// "2025-10-22 10" plus the minutes in the next part
+	if dateTimeFields := strings.Fields(parts[1]); len(dateTimeFields) >= 2 {
+		if dateParts := strings.Split(dateTimeFields[0], "-"); len(dateParts) == 3 {
+			info.Date = dateParts[2] + "." + dateParts[1] + "." + dateParts[0]
+		}
+		info.Time = dateTimeFields[1] + ":" + parts[2]
+	}
+	// Address is everything between the time and the NVT part
+	for i, part := range parts {
+		if strings.HasPrefix(part, "NVT") {
+			info.NVT = part
+			if i > 3 {
+				info.Address = strings.TrimSpace(strings.Join(parts[3:i], " "))
+			}
+			break
+		}
+	}
+	return info
+}
@@ -0,0 +1,103 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	fremcoSnippet = "Fremco SpeedNet-System\nStreckenabschnitt / NVt\nEinblasgerät: Fremco MicroFlow\n" +
+		"Streckenlänge [m] Geschwindigkeit [m/min] Rohr-Druck [bar] Drehmoment [%] Uhrzeit [hh:mm:ss]\n" +
+		"0 0 12.2 2 07:36:00"
+	jettingSnippet = "Jetting Protokoll\nStreckenlänge\n[m]\nLufttemperatur\n[°C]\nSchubkraft\n[N]\n" +
+		"Einblasdruck\n[bar]\nGeschwindigkeit\n[m/min]\nZeit - Dauer\n[hh:mm:ss]\n0\n11,4\n3\n11,55\n0\n00:00:00"
+	// jettingLabels are Jetting column labels without units, worth 8 of 20 points
+	jettingLabels   = "Streckenlänge\nGeschwindigkeit\nSchubkraft\nEinblasdruck\nLufttemperatur"
+	jettingExport   = "29.10.2025, 11 20, Eiermarkt 15 B NVT 1V2200.pdf"
+	fremcoExport    = "SM209214964_2025-10-22 10_51_Oldenburger Koppel_10_NVT1V3400.pdf"
+	reviewLow       = "low confidence"
+	reviewAmbiguous = "ambiguous"
+	reviewNoFormat  = "no known protocol format"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		filename string
+		format   string
+		review   string // expected review reason, "" = parsed without review
+	}{
+		{"Fremco", fremcoSnippet, fremcoExport, FremcoParserName, ""},
+		{"English Fremco at the threshold",
+			"Blowing distance: 512 Blowing time: 00:41:10\nLength [m] Speed [m/min] Pressure [bar] Torque [%] Time [hh:mm:ss]",
+			"export.pdf", FremcoParserName, ""},
+		{"Jetting", jettingSnippet, jettingExport, JettingParserName, ""},
+		{"Jetting without file name", jettingSnippet, "upload.pdf", JettingParserName, ""},
+		{"Plumettaz CSV", plumettazLogCSV, "run.csv", PlumettazParserName, ""},
+
+		// "bar" and "min" alone used to be read as Jetting
+		{"generic units", "Druck 10 bar nach 5 min", "scan.pdf", "", reviewNoFormat},
+		{"empty", "", "", "", reviewNoFormat},
+		{"log columns without a Plumettaz name", "Distance [m];Speed [m/s];Push force [daN]\n1,0;0,5;2,0", "log.csv", "", reviewNoFormat},
+		{"few Jetting labels", "Schubkraft\n[N]\nUhrzeit", "scan.pdf", JettingParserName, reviewLow},
+
+		// A Jetting file name adds 5 points, but only with the "..., NVT" pattern
+		{"Jetting labels without units", jettingLabels, "scan.pdf", JettingParserName, reviewLow},
+		{"Jetting labels with the export name", jettingLabels, jettingExport, JettingParserName, ""},
+		{"Jetting labels with date and time only", jettingLabels, "Export 29.10.2025 11 20.pdf", JettingParserName, reviewLow},
+		{"Jetting export name alone", "", jettingExport, JettingParserName, reviewLow},
+
+		// A Fremco device name rules out Jetting content
+		{"Jetting table with a Fremco name", "Fremco\n" + jettingSnippet, "scan.pdf", FremcoParserName, reviewLow},
+		{"Fremco and Plumettaz names", "Plumettaz MiniJet 400 compared to Fremco SpeedNet MicroFlow", "notes.pdf", PlumettazParserName, reviewAmbiguous},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := DetectFormat(tt.text, tt.filename)
+			if d.Format != tt.format {
+				t.Errorf("Format = %q (%.2f), want %q; candidates %+v", d.Format, d.Confidence, tt.format, d.Candidates)
+			}
+			if d.Review != (tt.review != "") || !strings.Contains(d.ReviewReason, tt.review) {
+				t.Errorf("Review = %v %q, want %q; candidates %+v", d.Review, d.ReviewReason, tt.review, d.Candidates)
+			}
+			if (d.Parser == nil) != (tt.format == "") || (d.Parser != nil && d.Parser.Name() != d.Format) {
+				t.Errorf("Parser = %v for format %q", d.Parser, d.Format)
+			}
+			if len(d.Candidates) != len(Parsers()) {
+				t.Fatalf("%d candidates, want one per parser", len(d.Candidates))
+			}
+			for i := 1; i < len(d.Candidates); i++ {
+				if d.Candidates[i].Confidence > d.Candidates[i-1].Confidence {
+					t.Errorf("candidates not ranked: %+v", d.Candidates)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectFormatLimits(t *testing.T) {
+	// The English Fremco snippet scores exactly the minimum confidence
+	d := DetectFormat("Blowing distance: 512 Blowing time: 00:41:10\nLength [m] Speed [m/min] Pressure [bar] Torque [%] Time [hh:mm:ss]", "")
+	if d.Confidence != MinDetectionConfidence || d.Review {
+		t.Errorf("confidence %.2f, review %v, want %.2f without review", d.Confidence, d.Review, MinDetectionConfidence)
+	}
+
+	// The Jetting content is ignored next to a Fremco name, not scored
+	jetting := jettingParser{}.Detect("Fremco\n"+jettingSnippet, "scan.pdf")
+	if jetting.Confidence != 0 || len(jetting.Evidence) != 1 || !strings.Contains(jetting.Evidence[0], "Fremco device name") {
+		t.Errorf("Jetting detection next to a Fremco name = %+v", jetting)
+	}
+	// The file name still counts
+	jetting = jettingParser{}.Detect("Fremco\n"+jettingSnippet, jettingExport)
+	if jetting.Confidence != 0.25 {
+		t.Errorf("Jetting file name next to a Fremco name = %+v, want 0.25", jetting)
+	}
+
+	// Both names are certain enough on their own; the lead over the
+	// runner-up is what makes it ambiguous
+	d = DetectFormat("Plumettaz MiniJet 400 compared to Fremco SpeedNet MicroFlow", "")
+	if len(d.Candidates) < 2 || d.Confidence < MinDetectionConfidence || d.Confidence-d.Candidates[1].Confidence >= MinDetectionLead {
+		t.Errorf("candidates %+v, want two above %.2f less than %.2f apart", d.Candidates, MinDetectionConfidence, MinDetectionLead)
+	}
+}
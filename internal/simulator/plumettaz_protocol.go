@@ -0,0 +1,95 @@
+package simulator
+
+import "time"
+
+// PlumettazProtocol represents a Plumettaz blowing machine run, read from the
+// machine's PDF report or from its data logger CSV export
+type PlumettazProtocol struct {
+	ProtocolInfo   PlumettazProtocolInfo   `json:"protocol_info"`
+	Equipment      PlumettazEquipment      `json:"equipment"`
+	Measurements   PlumettazMeasurements   `json:"measurements"`
+	ExportMetadata PlumettazExportMetadata `json:"export_metadata"`
+}
+
+// PlumettazProtocolInfo contains the job header of the report
+type PlumettazProtocolInfo struct {
+	System          string `json:"system"`           // Plumettaz
+	DocumentType    string `json:"document_type"`    // Blowing report / Data log
+	Date            string `json:"date"`             // 14.06.2025
+	StartTime       string `json:"start_time"`       // 09:12
+	ProjectNumber   string `json:"project_number"`   // Job
+	SectionNVT      string `json:"section_nvt"`      // Site: Lindenstr 12 / NVT 2V1100
+	Company         string `json:"company"`          // Customer
+	ServiceProvider string `json:"service_provider"` // Contractor
+	Operator        string `json:"operator"`
+	Remarks         string `json:"remarks"`
+}
+
+// PlumettazEquipment contains the machine, duct, cable and compressor of the run
+type PlumettazEquipment struct {
+	Machine    PlumettazMachine    `json:"machine"`
+	Duct       PlumettazDuct       `json:"duct"`
+	Cable      PlumettazCable      `json:"cable"`
+	Compressor PlumettazCompressor `json:"compressor"`
+}
+
+// PlumettazMachine represents the blowing machine
+type PlumettazMachine struct {
+	Model        string `json:"model"`         // MiniJet 400
+	SerialNumber string `json:"serial_number"` // stored as controller_sn
+}
+
+// PlumettazDuct represents the duct the cable is blown into
+type PlumettazDuct struct {
+	Manufacturer  string   `json:"manufacturer"`
+	Type          string   `json:"type"`           // "Gabocom 14/10", "SNR 7x1,5"
+	InnerDiameter *float64 `json:"inner_diameter"` // mm, from "outer/inner" or "outer x wall"
+	OuterDiameter *float64 `json:"outer_diameter"` // mm
+}
+
+// PlumettazCable represents the blown cable
+type PlumettazCable struct {
+	Manufacturer string   `json:"manufacturer"`
+	Designation  string   `json:"designation"`
+	FiberCount   *int     `json:"fiber_count"`
+	Diameter     *float64 `json:"diameter"` // mm
+	Lubricant    string   `json:"lubricant"`
+}
+
+// PlumettazCompressor represents the compressor
+type PlumettazCompressor struct {
+	Model string `json:"model"`
+}
+
+// PlumettazMeasurements contains the summary and the logged data points
+type PlumettazMeasurements struct {
+	Summary    PlumettazSummary     `json:"summary"`
+	DataPoints []PlumettazDataPoint `json:"data_points"`
+}
+
+// PlumettazSummary contains the summary of the run, from the report or computed from the data points
+type PlumettazSummary struct {
+	Distance           *int     `json:"distance"`            // m
+	BlowingTime        *string  `json:"blowing_time"`        // hh:mm:ss
+	AmbientTemperature *float64 `json:"ambient_temperature"` // °C
+}
+
+// PlumettazDataPoint is one logged row, converted to the units of the
+// Measurement quantities. Columns the export does not have are nil.
+type PlumettazDataPoint struct {
+	LengthM      float64  `json:"length_m"`
+	SpeedMMin    *float64 `json:"speed_m_min"`
+	PressureBar  *float64 `json:"pressure_bar"`
+	ForceN       *float64 `json:"force_n"`
+	TemperatureC *float64 `json:"temperature_c"`
+	TimeDuration string   `json:"time_duration"` // hh:mm:ss since the start
+}
+
+// PlumettazExportMetadata contains export/parsing metadata
+type PlumettazExportMetadata struct {
+	ParsedAt       time.Time      `json:"parsed_at"`
+	ParserVersion  string         `json:"parser_version"`
+	SourceFilename string         `json:"source_filename"`
+	SourceFormat   string         `json:"source_format"` // "pdf" or "csv"
+	Columns        []SourceColumn `json:"columns"`
+}
@@ -0,0 +1,162 @@
+package simulator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timing sources of a replay schedule
+const (
+	ReplayTimingTimestamp = "timestamp" // Fremco "Uhrzeit" wall clock
+	ReplayTimingDuration  = "duration"  // Jetting "Zeit - Dauer" elapsed time
+	ReplayTimingEstimated = "estimated" // derived from length and speed
+)
+
+// Fallbacks for samples without recorded timing
+const (
+	replayFallbackStep = time.Second
+	replayMinSpeed     = 1.0 // m/min, slower samples use the fallback step
+)
+
+// ReplaySample is the recorded timing of one measurement
+type ReplaySample struct {
+	Timestamp *time.Time     // wall clock (Fremco "Uhrzeit", or dated from the protocol start)
+	Elapsed   *time.Duration // time since the start (Jetting "Zeit - Dauer", or from the wall clock)
+	LengthM   float64
+	SpeedMMin float64
+}
+
+// ReplaySamples returns the recorded timing of measurements
+func ReplaySamples(measurements []Measurement) []ReplaySample {
+	samples := make([]ReplaySample, len(measurements))
+	for i, m := range measurements {
+		samples[i] = ReplaySample{
+			Timestamp: m.Timestamp,
+			Elapsed:   m.Elapsed,
+			LengthM:   m.LengthM,
+			SpeedMMin: floatValue(m.SpeedMMin),
+		}
+	}
+	return samples
+}
+
+// ParseElapsed parses a recorded elapsed time ("00:05:48", "05:48", "1 day 02:00:00"
+// as returned for PostgreSQL intervals, or a Go duration like "5m48s")
+func ParseElapsed(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var days time.Duration
+	if fields := strings.Fields(s); len(fields) >= 2 && strings.HasPrefix(fields[1], "day") {
+		n, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		s = strings.Join(fields[2:], " ")
+		if s == "" {
+			return days, nil
+		}
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		if d, err := time.ParseDuration(s); err == nil {
+			return days + d, nil
+		}
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	if len(parts) == 2 {
+		parts = append([]string{"0"}, parts...)
+	}
+	hours, err1 := strconv.Atoi(parts[0])
+	minutes, err2 := strconv.Atoi(parts[1])
+	seconds, err3 := strconv.ParseFloat(parts[2], 64)
+	if err1 != nil || err2 != nil || err3 != nil || hours < 0 || minutes < 0 || seconds < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return days + time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second)), nil
+}
+
+// FormatElapsed formats an elapsed time as "hh:mm:ss"
+func FormatElapsed(d time.Duration) string {
+	return formatDuration(d)
+}
+
+// ReplaySchedule returns the playback offset of every sample from the start of the
+// protocol and the timing source used. Wall clock timestamps are preferred, then
+// recorded elapsed times; samples without either are placed by their length
+// increment at the recorded speed. Offsets never decrease.
+func ReplaySchedule(samples []ReplaySample) ([]time.Duration, string) {
+	timestamps, durations := 0, 0
+	for _, s := range samples {
+		if s.Timestamp != nil {
+			timestamps++
+		}
+		if s.Elapsed != nil {
+			durations++
+		}
+	}
+	timing := ReplayTimingEstimated
+	if timestamps > 0 && timestamps >= durations {
+		timing = ReplayTimingTimestamp
+	} else if durations > 0 {
+		timing = ReplayTimingDuration
+	}
+
+	offsets := make([]time.Duration, len(samples))
+	var start *time.Time
+	var dayShift time.Duration
+	var startElapsed *time.Duration
+	for i, s := range samples {
+		var offset time.Duration
+		recorded := false
+		switch {
+		case timing == ReplayTimingTimestamp && s.Timestamp != nil:
+			if start == nil {
+				start = s.Timestamp
+			}
+			offset = s.Timestamp.Sub(*start) + dayShift
+			// Time-only clocks roll over at midnight
+			if i > 0 && offset < offsets[i-1]-12*time.Hour {
+				dayShift += 24 * time.Hour
+				offset += 24 * time.Hour
+			}
+			recorded = true
+		case timing == ReplayTimingDuration && s.Elapsed != nil:
+			if startElapsed == nil {
+				startElapsed = s.Elapsed
+			}
+			offset = *s.Elapsed - *startElapsed
+			recorded = true
+		}
+
+		if i == 0 {
+			offsets[i] = 0
+			continue
+		}
+		if !recorded {
+			offset = offsets[i-1] + estimatedStep(samples[i-1], s)
+		}
+		if offset < offsets[i-1] {
+			offset = offsets[i-1]
+		}
+		offsets[i] = offset
+	}
+	return offsets, timing
+}
+
+// estimatedStep estimates the time between two samples from the blown length and speed
+func estimatedStep(prev, cur ReplaySample) time.Duration {
+	distance := cur.LengthM - prev.LengthM
+	speed := (prev.SpeedMMin + cur.SpeedMMin) / 2
+	if distance <= 0 || speed < replayMinSpeed {
+		return replayFallbackStep
+	}
+	return time.Duration(distance / speed * float64(time.Minute)).Round(time.Second)
+}
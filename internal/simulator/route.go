@@ -0,0 +1,274 @@
+package simulator
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const earthRadiusM = 6371000.0
+
+// Route is the planned duct route of a section, attached to an NVT/address
+type Route struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	SectionNVT string         `json:"section_nvt"` // Haflinger Weg 5 / NVT1V3400
+	Address    string         `json:"address"`
+	Source     string         `json:"source"` // csv or gps
+	Segments   []RouteSegment `json:"segments"`
+}
+
+// GPSPoint is one vertex of a GPS polyline
+type GPSPoint struct {
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Altitude    float64 `json:"altitude"`
+	HasAltitude bool    `json:"has_altitude"`
+}
+
+// TotalLength returns the length of the route in m
+func (r *Route) TotalLength() float64 {
+	total := 0.0
+	for _, s := range r.Segments {
+		total += s.LengthM
+	}
+	return total
+}
+
+// TotalBendAngle returns the sum of all bend angles in degrees
+func (r *Route) TotalBendAngle() float64 {
+	total := 0.0
+	for _, s := range r.Segments {
+		total += math.Abs(s.BendAngleDeg)
+	}
+	return total
+}
+
+// BendCount returns the number of segments ending in a bend
+func (r *Route) BendCount() int {
+	count := 0
+	for _, s := range r.Segments {
+		if s.BendAngleDeg != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// ElevationGain returns the accumulated uphill elevation in m
+func (r *Route) ElevationGain() float64 {
+	gain := 0.0
+	for _, s := range r.Segments {
+		if s.ElevationM > 0 {
+			gain += s.ElevationM
+		}
+	}
+	return gain
+}
+
+// Duct returns the duct parameters of this route for the given pipe
+func (r *Route) Duct(innerDiameterMM float64, innerWall string) DuctParams {
+	return DuctParams{
+		InnerDiameterMM: innerDiameterMM,
+		InnerWall:       innerWall,
+		Segments:        r.Segments,
+	}
+}
+
+var nvtKeyRe = regexp.MustCompile(`(?i)NVT\s*([0-9A-Z]+)`)
+
+// SectionNVTKey returns the bare NVT identifier of a section such as
+// "Haflinger Weg 5 / NVT1V3400" or "Dammstr 8 / NVT 1V2300" (→ "1V3400", "1V2300").
+// It is used to link routes and protocols whose section strings differ in spelling.
+func SectionNVTKey(sectionNVT string) string {
+	if m := nvtKeyRe.FindStringSubmatch(sectionNVT); len(m) > 1 {
+		return strings.ToUpper(m[1])
+	}
+	parts := strings.Split(sectionNVT, "/")
+	key := strings.TrimSpace(parts[len(parts)-1])
+	return strings.ToUpper(strings.ReplaceAll(key, " ", ""))
+}
+
+// ParseRouteCSV parses route segments from a CSV file with the columns
+// length_m, bend_angle_deg, bend_radius_m, elevation_m. The header row is
+// optional; semicolon-separated files with decimal commas are accepted too.
+func ParseRouteCSV(r io.Reader) ([]RouteSegment, error) {
+	br := bufio.NewReader(r)
+	first, _ := br.Peek(512)
+	reader := csv.NewReader(br)
+	if strings.Contains(string(first), ";") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid route CSV: %v", err)
+	}
+
+	columns := map[string]int{"length_m": 0, "bend_angle_deg": 1, "bend_radius_m": 2, "elevation_m": 3}
+	var segments []RouteSegment
+	for i, record := range records {
+		if len(record) == 0 || strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if i == 0 && !isNumeric(csvNumber(record[0])) {
+			// Header row, map columns by name
+			columns = map[string]int{}
+			for idx, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = idx
+			}
+			if _, ok := columns["length_m"]; !ok {
+				return nil, fmt.Errorf("route CSV header must contain length_m")
+			}
+			continue
+		}
+
+		var segment RouteSegment
+		fields := []struct {
+			name string
+			dest *float64
+		}{
+			{"length_m", &segment.LengthM},
+			{"bend_angle_deg", &segment.BendAngleDeg},
+			{"bend_radius_m", &segment.BendRadiusM},
+			{"elevation_m", &segment.ElevationM},
+		}
+		for _, f := range fields {
+			idx, ok := columns[f.name]
+			if !ok || idx >= len(record) || strings.TrimSpace(record[idx]) == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(csvNumber(record[idx]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s %q", i+1, f.name, record[idx])
+			}
+			*f.dest = v
+		}
+		if segment.LengthM <= 0 {
+			return nil, fmt.Errorf("line %d: segment length must be positive", i+1)
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("route CSV contains no segments")
+	}
+	return segments, nil
+}
+
+func csvNumber(s string) string {
+	return strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+}
+
+var gpsPointRe = regexp.MustCompile(`(-?\d+(?:\.\d+)?)°?\s*[;,\s]\s*(-?\d+(?:\.\d+)?)°?(?:\s*[;,\s]\s*(-?\d+(?:\.\d+)?)\s*m?)?`)
+
+// ParseGPSPolyline parses one GPS point per line as "lat,lon[,alt]", "lat;lon;alt"
+// or the protocol notation "52.4914°;9.85174°;95.8000 m".
+func ParseGPSPolyline(text string) ([]GPSPoint, error) {
+	var points []GPSPoint
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := gpsPointRe.FindStringSubmatch(line)
+		if m == nil {
+			if i == 0 {
+				continue // header line
+			}
+			return nil, fmt.Errorf("line %d: invalid GPS point %q", i+1, line)
+		}
+		lat, _ := strconv.ParseFloat(m[1], 64)
+		lon, _ := strconv.ParseFloat(m[2], 64)
+		if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return nil, fmt.Errorf("line %d: GPS point out of range %q", i+1, line)
+		}
+		point := GPSPoint{Latitude: lat, Longitude: lon}
+		if m[3] != "" {
+			point.Altitude, _ = strconv.ParseFloat(m[3], 64)
+			point.HasAltitude = true
+		}
+		points = append(points, point)
+	}
+	if len(points) < 2 {
+		return nil, fmt.Errorf("GPS polyline needs at least two points")
+	}
+	return points, nil
+}
+
+// RouteSegmentsFromPolyline converts a GPS polyline into route segments. The
+// bend at each inner vertex is the change of bearing; its radius is the
+// largest arc that fits between the neighbouring segments.
+func RouteSegmentsFromPolyline(points []GPSPoint) []RouteSegment {
+	// Repeated fixes of the same position would give zero-length segments and
+	// shift the vertices the bends are computed from; the first fix is kept so
+	// the elevation change is measured from it.
+	var vertices []GPSPoint
+	for _, p := range points {
+		if len(vertices) > 0 && haversine(vertices[len(vertices)-1], p) <= 0 {
+			continue
+		}
+		vertices = append(vertices, p)
+	}
+
+	var segments []RouteSegment
+	for i := 1; i < len(vertices); i++ {
+		a, b := vertices[i-1], vertices[i]
+		segment := RouteSegment{LengthM: haversine(a, b)}
+		if a.HasAltitude && b.HasAltitude {
+			segment.ElevationM = b.Altitude - a.Altitude
+		}
+		segments = append(segments, segment)
+	}
+	for i := 0; i+1 < len(segments); i++ {
+		// segment i runs from vertex i to vertex i+1
+		turn := bearingChange(vertices, i)
+		if math.Abs(turn) < 1 {
+			continue
+		}
+		segments[i].BendAngleDeg = round(turn, 1)
+		shorter := math.Min(segments[i].LengthM, segments[i+1].LengthM)
+		segments[i].BendRadiusM = round(shorter/2/math.Tan(math.Abs(turn)*math.Pi/360), 1)
+	}
+	for i := range segments {
+		segments[i].LengthM = round(segments[i].LengthM, 2)
+		segments[i].ElevationM = round(segments[i].ElevationM, 2)
+	}
+	return segments
+}
+
+// bearingChange returns the turn in degrees at the vertex after segment i
+func bearingChange(points []GPSPoint, i int) float64 {
+	if i+2 >= len(points) {
+		return 0
+	}
+	turn := bearing(points[i+1], points[i+2]) - bearing(points[i], points[i+1])
+	for turn > 180 {
+		turn -= 360
+	}
+	for turn < -180 {
+		turn += 360
+	}
+	return turn
+}
+
+func haversine(a, b GPSPoint) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusM * math.Asin(math.Sqrt(h))
+}
+
+func bearing(a, b GPSPoint) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Atan2(y, x) * 180 / math.Pi
+}
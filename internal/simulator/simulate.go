@@ -0,0 +1,505 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Physical constants used by the blowing model
+const (
+	gravity              = 9.81     // m/s²
+	atmosphericPressure  = 1.01325  // bar (absolute)
+	pascalPerBar         = 100000.0 // Pa per bar
+	ductUndulationPerM   = 0.01     // rad/m of unavoidable duct waviness in a "straight" trench
+	cableDensityKgPerM3  = 1150.0   // typical micro cable density used when the weight is unknown
+	cableModulusPa       = 2.0e9    // effective Young's modulus used to estimate bending stiffness
+	defaultMaxSpeedMMin  = 80.0     // m/min, typical MicroFlow/MJET top speed
+	defaultStepM         = 1.0      // m between simulated data points
+	maxSimulationSteps   = 20000    // steps per simulated run, e.g. 20 km at 1 m
+	defaultSectionLength = 2000.0   // m, used when neither route nor length is given
+	speedDerateTorque    = 60.0     // % torque above which the machine starts slowing down
+	maxModelForceN       = 1.0e5    // N, forces above this are treated as a stuck cable
+)
+
+// RouteSegment is one piece of a duct route: a straight run followed by an
+// optional bend and an elevation change over its length.
+type RouteSegment struct {
+	LengthM      float64 `json:"length_m" db:"length_m"`
+	BendAngleDeg float64 `json:"bend_angle_deg" db:"bend_angle_deg"` // bend at the end of the segment
+	BendRadiusM  float64 `json:"bend_radius_m" db:"bend_radius_m"`
+	ElevationM   float64 `json:"elevation_m" db:"elevation_m"` // elevation change over the segment (+ = uphill)
+}
+
+// DuctParams describes the duct the cable is blown into
+type DuctParams struct {
+	InnerDiameterMM float64        `json:"inner_diameter_mm"` // 4 for SNR 7x1,5
+	InnerWall       string         `json:"inner_wall"`        // Glatt / Gerieft
+	LengthM         float64        `json:"length_m"`          // used when no segments are given
+	Segments        []RouteSegment `json:"segments"`
+}
+
+// CableParams describes the cable being installed
+type CableParams struct {
+	DiameterMM   float64 `json:"diameter_mm"`     // 2.5
+	WeightKgPerM float64 `json:"weight_kg_per_m"` // 0 = estimated from diameter
+	StiffnessNm2 float64 `json:"stiffness_nm2"`   // bending stiffness, 0 = estimated from diameter
+	Lubricant    string  `json:"lubricant"`       // Prelube 5000
+}
+
+// SimulationInput contains everything needed to simulate a blowing run
+type SimulationInput struct {
+	Duct                DuctParams  `json:"duct"`
+	Cable               CableParams `json:"cable"`
+	PressureBar         float64     `json:"pressure_bar"`         // compressor pressure (gauge)
+	PushForceN          float64     `json:"push_force_n"`         // max pushing force of the blowing device
+	MaxSpeedMMin        float64     `json:"max_speed_m_min"`      // 0 = default
+	FrictionCoefficient float64     `json:"friction_coefficient"` // 0 = derived from inner wall and lubricant
+	AmbientTemperatureC float64     `json:"ambient_temperature_c"`
+	StepM               float64     `json:"step_m"`     // 0 = default
+	StartTime           time.Time   `json:"start_time"` // zero = timestamps as elapsed hh:mm:ss
+}
+
+// SimulationResult contains the predicted blowing curve
+type SimulationResult struct {
+	SectionLengthM      float64            `json:"section_length_m"`
+	MaxDistanceM        float64            `json:"max_distance_m"`
+	Reached             bool               `json:"reached"` // true if the cable reached the end of the section
+	FrictionCoefficient float64            `json:"friction_coefficient"`
+	BlowingTime         string             `json:"blowing_time"`
+	FremcoDataPoints    []FremcoDataPoint  `json:"fremco_data_points"`
+	JettingDataPoints   []JettingDataPoint `json:"jetting_data_points"`
+}
+
+// lubricantFactors scales the dry friction coefficient for known lubricants
+var lubricantFactors = map[string]float64{
+	"prelube 5000": 0.65,
+	"prelube":      0.70,
+	"micro":        0.75,
+	"polywater":    0.70,
+	"lubricant":    0.80,
+}
+
+// LubricantNames returns the lubricants known to the friction model
+func LubricantNames() []string {
+	return []string{"kein", "lubricant", "micro", "polywater", "prelube", "prelube 5000"}
+}
+
+// LubricantFactor returns the friction multiplier for a lubricant name.
+// An empty name or "kein"/"none" means no lubricant.
+func LubricantFactor(lubricant string) float64 {
+	l := strings.ToLower(strings.TrimSpace(lubricant))
+	if l == "" || l == "kein" || l == "keins" || l == "none" || l == "nein" {
+		return 1.0
+	}
+	if f, ok := lubricantFactors[l]; ok {
+		return f
+	}
+	for name, f := range lubricantFactors {
+		if strings.Contains(l, name) {
+			return f
+		}
+	}
+	// Unknown product, assume an average lubricant
+	return lubricantFactors["lubricant"]
+}
+
+// DefaultFrictionCoefficient returns a textbook cable/duct friction coefficient
+// for the given inner wall type and lubricant.
+func DefaultFrictionCoefficient(innerWall, lubricant string) float64 {
+	dry := 0.22
+	wall := strings.ToLower(innerWall)
+	switch {
+	case strings.Contains(wall, "gerieft") || strings.Contains(wall, "ribbed") || strings.Contains(wall, "grooved"):
+		dry = 0.18
+	case strings.Contains(wall, "glatt") || strings.Contains(wall, "smooth"):
+		dry = 0.25
+	}
+	return dry * LubricantFactor(lubricant)
+}
+
+// EstimateCableStiffness estimates the bending stiffness (N·m²) of a micro cable from its diameter
+func EstimateCableStiffness(diameterMM float64) float64 {
+	d := diameterMM / 1000
+	return cableModulusPa * math.Pi * math.Pow(d, 4) / 64
+}
+
+// EstimateCableWeight estimates the weight per meter of a micro cable from its diameter
+func EstimateCableWeight(diameterMM float64) float64 {
+	r := diameterMM / 2000.0
+	return math.Pi * r * r * cableDensityKgPerM3
+}
+
+var pipeDimensionRe = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*[xX×]\s*(\d+(?:[.,]\d+)?)\s*$`)
+
+// ParsePipeDimensions extracts outer diameter and wall thickness from a pipe
+// type such as "SNR 7x1,5" or a bundle such as "SNRVe 22x7x1,5".
+func ParsePipeDimensions(pipeType string) (outerMM, wallMM float64, ok bool) {
+	m := pipeDimensionRe.FindStringSubmatch(strings.TrimSpace(pipeType))
+	if len(m) < 3 {
+		return 0, 0, false
+	}
+	outer, err1 := ParseDecimal(m[1])
+	wall, err2 := ParseDecimal(m[2])
+	if err1 != nil || err2 != nil || outer <= 2*wall {
+		return 0, 0, false
+	}
+	return outer, wall, true
+}
+
+// InnerDiameterFromPipeType returns the inner diameter for a pipe type such as "7x1,5" (→ 4 mm)
+func InnerDiameterFromPipeType(pipeType string) (float64, bool) {
+	outer, wall, ok := ParsePipeDimensions(pipeType)
+	if !ok {
+		return 0, false
+	}
+	return outer - 2*wall, true
+}
+
+// NewSimulationInputFromFremco builds a simulation input from parsed Fremco equipment
+func NewSimulationInputFromFremco(eq FremcoEquipment, pressureBar, pushForceN float64) SimulationInput {
+	innerDiameter, _ := InnerDiameterFromPipeType(eq.Pipe.PipeType)
+	return SimulationInput{
+		Duct: DuctParams{
+			InnerDiameterMM: innerDiameter,
+			InnerWall:       eq.Pipe.InnerWall,
+		},
+		Cable: CableParams{
+			DiameterMM: eq.Cable.Diameter,
+			Lubricant:  eq.Cable.Lubricant,
+		},
+		PressureBar: pressureBar,
+		PushForceN:  pushForceN,
+	}
+}
+
+// routePoint is the state of the route at a given distance from the entry
+type routePoint struct {
+	slope float64 // sin of the inclination
+	bend  float64 // bend angle in rad ending at this step
+}
+
+// Validate checks that the input describes a physically possible setup
+func (in SimulationInput) Validate() error {
+	if in.Duct.InnerDiameterMM <= 0 {
+		return fmt.Errorf("duct inner diameter must be positive")
+	}
+	if in.Cable.DiameterMM <= 0 {
+		return fmt.Errorf("cable diameter must be positive")
+	}
+	if in.Cable.DiameterMM >= in.Duct.InnerDiameterMM {
+		return fmt.Errorf("cable diameter %.2f mm does not fit into duct with %.2f mm inner diameter", in.Cable.DiameterMM, in.Duct.InnerDiameterMM)
+	}
+	if in.PressureBar < 0 {
+		return fmt.Errorf("pressure must not be negative")
+	}
+	if in.PushForceN <= 0 {
+		return fmt.Errorf("push force must be positive")
+	}
+	if steps := math.Ceil(in.SectionLength() / in.step()); steps > maxSimulationSteps {
+		return fmt.Errorf("section of %.0f m in %.2f m steps exceeds %d simulation steps", in.SectionLength(), in.step(), maxSimulationSteps)
+	}
+	return nil
+}
+
+// step returns the distance between simulated data points
+func (in SimulationInput) step() float64 {
+	if in.StepM <= 0 {
+		return defaultStepM
+	}
+	return in.StepM
+}
+
+// SectionLength returns the total duct length of the simulated section
+func (in SimulationInput) SectionLength() float64 {
+	if len(in.Duct.Segments) > 0 {
+		total := 0.0
+		for _, s := range in.Duct.Segments {
+			total += s.LengthM
+		}
+		return total
+	}
+	if in.Duct.LengthM > 0 {
+		return in.Duct.LengthM
+	}
+	return defaultSectionLength
+}
+
+// Simulate predicts the blowing curve for the given input.
+//
+// The model follows Griffioen's approach: the cable is pushed at the entry,
+// the compressed air drags it along with a force proportional to the local
+// pressure gradient, and friction (including capstan amplification in bends
+// and the waviness of a buried duct) and gravity hold it back. For every
+// installed length the required pushing force at the entry is computed; the
+// run stalls once it exceeds the force the blowing device can deliver.
+func Simulate(in SimulationInput) (*SimulationResult, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	maxSpeed := in.MaxSpeedMMin
+	if maxSpeed <= 0 {
+		maxSpeed = defaultMaxSpeedMMin
+	}
+	friction := in.FrictionCoefficient
+	if friction <= 0 {
+		friction = DefaultFrictionCoefficient(in.Duct.InnerWall, in.Cable.Lubricant)
+	}
+	m := newBlowingModel(in)
+	length := m.length
+
+	result := &SimulationResult{
+		SectionLengthM:      length,
+		FrictionCoefficient: friction,
+		FremcoDataPoints:    []FremcoDataPoint{},
+		JettingDataPoints:   []JettingDataPoint{},
+	}
+
+	var elapsed time.Duration
+	for i := 0; i <= len(m.route); i++ {
+		installed := m.installedLength(i)
+		force := m.requiredForce(i, friction)
+		torque := force / in.PushForceN * 100
+		if force > in.PushForceN {
+			break
+		}
+		speed := speedForTorque(torque, maxSpeed)
+		if i > 0 && speed > 0 {
+			elapsed += time.Duration(m.step / speed * float64(time.Minute))
+		}
+
+		result.MaxDistanceM = installed
+		result.FremcoDataPoints = append(result.FremcoDataPoints, FremcoDataPoint{
+			LengthM:       round(installed, 1),
+			SpeedMMin:     round(speed, 1),
+			PressureBar:   round(in.PressureBar, 2),
+			TorquePercent: round(math.Max(torque, 0), 1),
+			Timestamp:     formatSimulatedTime(in.StartTime, elapsed),
+		})
+		result.JettingDataPoints = append(result.JettingDataPoints, JettingDataPoint{
+			LengthM:      round(installed, 1),
+			TemperatureC: in.AmbientTemperatureC,
+			ForceN:       round(math.Max(force, 0), 1),
+			PressureBar:  round(in.PressureBar, 2),
+			SpeedMMin:    round(speed, 1),
+			TimeDuration: formatDuration(elapsed),
+		})
+	}
+
+	result.Reached = result.MaxDistanceM >= length
+	result.BlowingTime = formatDuration(elapsed)
+	return result, nil
+}
+
+// blowingModel holds the discretized route and the per-step forces of one simulation setup
+type blowingModel struct {
+	step     float64
+	length   float64
+	weightN  float64 // cable weight per meter in N
+	buckling float64 // r/(4B) for the helical buckling wall force
+	route    []routePoint
+	airForce []float64
+
+	// pushing force per installed steps for curveFriction, see forceCurve
+	curve         []float64
+	curveFriction float64
+}
+
+func newBlowingModel(in SimulationInput) *blowingModel {
+	step := in.step()
+	weight := in.Cable.WeightKgPerM
+	if weight <= 0 {
+		weight = EstimateCableWeight(in.Cable.DiameterMM)
+	}
+	stiffness := in.Cable.StiffnessNm2
+	if stiffness <= 0 {
+		stiffness = EstimateCableStiffness(in.Cable.DiameterMM)
+	}
+	length := in.SectionLength()
+	route := discretizeRoute(in.Duct, length, step)
+	// radial play of the cable in the duct, used for the buckling wall force
+	clearance := (in.Duct.InnerDiameterMM - in.Cable.DiameterMM) / 2000
+	return &blowingModel{
+		step:     step,
+		length:   length,
+		weightN:  weight * gravity,
+		buckling: clearance / (4 * stiffness),
+		route:    route,
+		airForce: airForcePerMeter(in.Duct.InnerDiameterMM, in.Cable.DiameterMM, in.PressureBar, length, step, len(route)),
+	}
+}
+
+// installedLength returns the cable length in the duct after the given number of steps
+func (m *blowingModel) installedLength(steps int) float64 {
+	return math.Min(float64(steps)*m.step, m.length)
+}
+
+// requiredForce returns the pushing force needed at the entry after the given number of steps
+func (m *blowingModel) requiredForce(steps int, friction float64) float64 {
+	if m.curve == nil || m.curveFriction != friction {
+		m.curve, m.curveFriction = m.forceCurve(friction), friction
+	}
+	return m.curve[steps]
+}
+
+// setAirForce replaces the air drag per step, e.g. after a pressure change
+func (m *blowingModel) setAirForce(airForce []float64) {
+	m.airForce = airForce
+	m.curve = nil
+}
+
+// pressureDominance returns the first length at which the air drag on the cable
+// exceeds the friction from its own weight, i.e. where pressure takes over from pushing.
+func (m *blowingModel) pressureDominance(friction float64) (float64, bool) {
+	for i, a := range m.airForce {
+		if a > friction*m.weightN {
+			return m.installedLength(i), true
+		}
+	}
+	return 0, false
+}
+
+// discretizeRoute splits the route into steps and returns the slope and bend for each step
+func discretizeRoute(duct DuctParams, length, step float64) []routePoint {
+	n := int(math.Ceil(length / step))
+	points := make([]routePoint, n)
+	if len(duct.Segments) == 0 {
+		return points
+	}
+	pos := 0.0
+	for _, seg := range duct.Segments {
+		if seg.LengthM <= 0 {
+			continue
+		}
+		slope := seg.ElevationM / seg.LengthM
+		if slope > 1 {
+			slope = 1
+		} else if slope < -1 {
+			slope = -1
+		}
+		start := int(pos / step)
+		pos += seg.LengthM
+		end := int(math.Ceil(pos/step)) - 1
+		if end >= n {
+			end = n - 1
+		}
+		for i := start; i <= end && i < n; i++ {
+			points[i].slope = slope
+		}
+		if end >= 0 && end < n {
+			points[end].bend += seg.BendAngleDeg * math.Pi / 180
+		}
+	}
+	return points
+}
+
+// airForcePerMeter returns the air drag on the cable for every step of the duct.
+// Isothermal compressible flow gives p(x)² = p0² - (p0² - pa²)·x/L and the
+// drag per meter on the cable is π/4 · d · D · (-dp/dx).
+func airForcePerMeter(ductMM, cableMM, pressureBar, length, step float64, n int) []float64 {
+	forces := make([]float64, n)
+	if pressureBar <= 0 || length <= 0 {
+		return forces
+	}
+	p0 := (pressureBar + atmosphericPressure) * pascalPerBar
+	pa := atmosphericPressure * pascalPerBar
+	d := cableMM / 1000
+	D := ductMM / 1000
+	for i := range forces {
+		x := (float64(i) + 0.5) * step
+		if x > length {
+			x = length
+		}
+		p := math.Sqrt(p0*p0 - (p0*p0-pa*pa)*x/length)
+		gradient := (p0*p0 - pa*pa) / (2 * length * p)
+		forces[i] = math.Pi / 4 * d * D * gradient
+	}
+	return forces
+}
+
+// forceCurve accumulates the pushing force needed at the entry step by step
+// as the cable advances, for 0 to len(route) installed steps. Every installed
+// step adds its friction, weight and air drag, amplified by the capstan effect
+// of the bends and the duct waviness between it and the entry. A pushed cable
+// buckles into a helix against the duct wall, adding a wall force of r·F²/(4B)
+// per meter (buckling = r/(4B)), see bucklingForce. Forces beyond
+// maxModelForceN mean the cable is stuck and are +Inf.
+func (m *blowingModel) forceCurve(friction float64) []float64 {
+	curve := make([]float64, len(m.route)+1)
+	base := 0.0 // force without buckling
+	installed := 0.0
+	pushed, pulled := 1.0, 1.0 // capstan factors from the current tip to the entry
+	for i, pt := range m.route {
+		cosine := math.Sqrt(1 - pt.slope*pt.slope)
+		local := (friction*m.weightN*cosine + m.weightN*pt.slope - m.airForce[i]) * m.step
+		if local > 0 {
+			base += pushed * local
+		} else {
+			base += pulled * local
+		}
+		installed += m.step
+
+		force := bucklingForce(base, friction*m.buckling, installed)
+		if force > maxModelForceN {
+			// far beyond any blowing device, the cable is stuck
+			for j := i + 1; j < len(curve); j++ {
+				curve[j] = math.Inf(1)
+			}
+			break
+		}
+		curve[i+1] = force
+
+		wrap := pt.bend + ductUndulationPerM*m.step
+		pushed *= math.Exp(friction * wrap)
+		pulled *= math.Exp(-friction * wrap)
+	}
+	return curve
+}
+
+// bucklingForce returns the entry force of a pushed cable of the given length
+// with the force base without buckling and the buckling wall friction k·F² per
+// meter. Spread evenly over the cable, dF/dx = base/length + k·F² from the tip
+// gives F = √(base/(k·length))·tan(√(base·k·length)); at tan's pole the wall
+// force grows faster than any push (lock-up) and the force is +Inf.
+func bucklingForce(base, k, length float64) float64 {
+	if base <= 0 || k <= 0 || length <= 0 {
+		return base
+	}
+	x := math.Sqrt(base * k * length)
+	if x >= math.Pi/2 {
+		return math.Inf(1)
+	}
+	return math.Sqrt(base/(k*length)) * math.Tan(x)
+}
+
+// speedForTorque models the blowing device slowing down once the torque gets high
+func speedForTorque(torque, maxSpeed float64) float64 {
+	if torque <= speedDerateTorque {
+		return maxSpeed
+	}
+	if torque >= 100 {
+		return 0
+	}
+	return maxSpeed * (100 - torque) / (100 - speedDerateTorque)
+}
+
+func formatSimulatedTime(start time.Time, elapsed time.Duration) string {
+	if start.IsZero() {
+		return formatDuration(elapsed)
+	}
+	return start.Add(elapsed).Format("2006-01-02 15:04:05")
+}
+
+// formatDuration formats a duration as hh:mm:ss
+func formatDuration(d time.Duration) string {
+	s := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, (s%3600)/60, s%60)
+}
+
+func round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
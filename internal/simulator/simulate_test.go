@@ -0,0 +1,220 @@
+package simulator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// stiffInput is a horizontal duct without air and a cable so stiff that it
+// does not buckle, leaving only friction, undulation and bends
+func stiffInput(segments ...RouteSegment) SimulationInput {
+	return SimulationInput{
+		Duct:                DuctParams{InnerDiameterMM: 4, LengthM: 1000, Segments: segments},
+		Cable:               CableParams{DiameterMM: 2.5, WeightKgPerM: 0.006, StiffnessNm2: 1e6},
+		PushForceN:          100,
+		FrictionCoefficient: 0.2,
+	}
+}
+
+func TestStraightDuctAnalyticForce(t *testing.T) {
+	in := stiffInput()
+	m := newBlowingModel(in)
+	mu, w := in.FrictionCoefficient, in.Cable.WeightKgPerM*gravity
+
+	// Every meter adds μ·W, amplified by the undulation between it and the
+	// entry: F(L) = μ·W·step·Σ e^(μ·u·step·i) = μ·W·step·(e^(μ·u·L) - 1)/(e^(μ·u·step) - 1)
+	for _, length := range []float64{1, 10, 100, 500, 1000} {
+		steps := int(length / m.step)
+		want := mu * w * m.step * math.Expm1(mu*ductUndulationPerM*length) / math.Expm1(mu*ductUndulationPerM*m.step)
+		got := m.requiredForce(steps, mu)
+		if math.Abs(got-want) > want*1e-6 {
+			t.Errorf("force after %.0f m = %.6f N, want %.6f N", length, got, want)
+		}
+	}
+
+	// Without undulation the force would be linear; with it it grows faster
+	if f500, f1000 := m.requiredForce(500, mu), m.requiredForce(1000, mu); f1000 <= 2*f500 {
+		t.Errorf("force after 1000 m = %.3f N, want more than twice the %.3f N after 500 m", f1000, f500)
+	}
+}
+
+func TestCapstanBend(t *testing.T) {
+	mu := 0.2
+	straight := newBlowingModel(stiffInput(RouteSegment{LengthM: 100}, RouteSegment{LengthM: 100}))
+	bend := newBlowingModel(stiffInput(RouteSegment{LengthM: 100, BendAngleDeg: 90, BendRadiusM: 1}, RouteSegment{LengthM: 100}))
+
+	// Up to the bend both routes need the same force
+	if a, b := straight.requiredForce(100, mu), bend.requiredForce(100, mu); math.Abs(a-b) > 1e-9 {
+		t.Errorf("force at the bend = %.6f N, want %.6f N", b, a)
+	}
+	// Every meter behind the bend is amplified by e^(μ·θ)
+	want := math.Exp(mu * math.Pi / 2)
+	got := (bend.requiredForce(200, mu) - bend.requiredForce(100, mu)) /
+		(straight.requiredForce(200, mu) - straight.requiredForce(100, mu))
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("capstan factor of a 90° bend = %.6f, want %.6f", got, want)
+	}
+}
+
+func TestElevation(t *testing.T) {
+	mu := 0.2
+	flat := newBlowingModel(stiffInput(RouteSegment{LengthM: 100}))
+	uphill := newBlowingModel(stiffInput(RouteSegment{LengthM: 100, ElevationM: 10}))
+	downhill := newBlowingModel(stiffInput(RouteSegment{LengthM: 100, ElevationM: -10}))
+
+	f, up, down := flat.requiredForce(100, mu), uphill.requiredForce(100, mu), downhill.requiredForce(100, mu)
+	if !(down < f && f < up) {
+		t.Errorf("force downhill %.3f N, flat %.3f N, uphill %.3f N, want increasing", down, f, up)
+	}
+}
+
+func TestBucklingForce(t *testing.T) {
+	base, length := 50.0, 500.0
+	if got := bucklingForce(base, 0, length); got != base {
+		t.Errorf("bucklingForce without wall friction = %v, want %v", got, base)
+	}
+	if got := bucklingForce(-5, 1e-4, length); got != -5 {
+		t.Errorf("bucklingForce of a pulled cable = %v, want -5", got)
+	}
+
+	// More wall friction never lowers the force, up to lock-up
+	previous := base
+	for _, k := range []float64{1e-8, 1e-7, 1e-6, 1e-5, 2e-5, 4e-5, 5e-5} {
+		got := bucklingForce(base, k, length)
+		if got < previous {
+			t.Errorf("bucklingForce(k=%g) = %v, below %v for less friction", k, got, previous)
+		}
+		previous = got
+	}
+	if k := math.Pow(math.Pi/2, 2) / (base * length); !math.IsInf(bucklingForce(base, k, length), 1) {
+		t.Errorf("bucklingForce at the lock-up limit is not +Inf")
+	}
+}
+
+func TestRequiredForceMonotoneInFriction(t *testing.T) {
+	// The default stiffness of a 2.5 mm cable buckles
+	in := testInput()
+	in.Duct.LengthM = 300
+	m := newBlowingModel(in)
+	previous := 0.0
+	for _, mu := range []float64{0.05, 0.1, 0.15, 0.2, 0.3, 0.4, 0.5} {
+		got := m.requiredForce(len(m.route), mu)
+		if got < previous {
+			t.Errorf("force with μ=%.2f = %.3f N, below %.3f N for less friction", mu, got, previous)
+		}
+		previous = got
+	}
+}
+
+func TestAirForcePerMeter(t *testing.T) {
+	ductMM, cableMM, pressure, length, step := 4.0, 2.5, 12.0, 1000.0, 1.0
+	forces := airForcePerMeter(ductMM, cableMM, pressure, length, step, int(length/step))
+
+	// Isothermal flow drops the pressure faster towards the exit
+	for i := 1; i < len(forces); i++ {
+		if forces[i] <= forces[i-1] {
+			t.Fatalf("air force at %d m = %v, not above %v before", i, forces[i], forces[i-1])
+		}
+	}
+	// In total the pressure drop p0 - pa acts on π/4·d·D
+	total := 0.0
+	for _, f := range forces {
+		total += f * step
+	}
+	want := math.Pi / 4 * cableMM / 1000 * ductMM / 1000 * pressure * pascalPerBar
+	if math.Abs(total-want) > want*1e-3 {
+		t.Errorf("total air force = %.3f N, want %.3f N", total, want)
+	}
+
+	for _, f := range airForcePerMeter(ductMM, cableMM, 0, length, step, 10) {
+		if f != 0 {
+			t.Fatalf("air force without pressure = %v", f)
+		}
+	}
+}
+
+func TestForceCurveCache(t *testing.T) {
+	in := stiffInput()
+	in.PressureBar = 10
+	m := newBlowingModel(in)
+
+	withAir := m.requiredForce(500, 0.2)
+	if m.curve == nil || m.curveFriction != 0.2 {
+		t.Fatalf("force curve for μ=0.2 not cached")
+	}
+	if got := m.requiredForce(500, 0.3); got <= withAir {
+		t.Errorf("force with μ=0.3 = %v, want above %v (curve not recomputed)", got, withAir)
+	}
+
+	m.setAirForce(make([]float64, len(m.route)))
+	if m.curve != nil {
+		t.Fatalf("setAirForce kept the cached force curve")
+	}
+	if got := m.requiredForce(500, 0.2); got <= withAir {
+		t.Errorf("force without air = %v, want above %v with air", got, withAir)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SimulationInput)
+		want   string // expected error, "" = valid
+	}{
+		{"valid", func(in *SimulationInput) {}, ""},
+		{"no duct", func(in *SimulationInput) { in.Duct.InnerDiameterMM = 0 }, "duct inner diameter"},
+		{"no cable", func(in *SimulationInput) { in.Cable.DiameterMM = 0 }, "cable diameter must be positive"},
+		{"cable does not fit", func(in *SimulationInput) { in.Cable.DiameterMM = 4 }, "does not fit"},
+		{"negative pressure", func(in *SimulationInput) { in.PressureBar = -1 }, "pressure"},
+		{"no push force", func(in *SimulationInput) { in.PushForceN = 0 }, "push force"},
+		{"longest section", func(in *SimulationInput) { in.Duct.LengthM = maxSimulationSteps }, ""},
+		{"too many steps", func(in *SimulationInput) { in.Duct.LengthM = maxSimulationSteps + 1 }, "simulation steps"},
+		{"larger steps", func(in *SimulationInput) { in.Duct.LengthM, in.StepM = 2*maxSimulationSteps, 2 }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := testInput()
+			tt.modify(&in)
+			err := in.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate() = %v, want no error", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateStopsAtPushForce(t *testing.T) {
+	in := testInput()
+	result, err := Simulate(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Reached || result.MaxDistanceM <= 0 || result.MaxDistanceM >= in.Duct.LengthM {
+		t.Fatalf("MaxDistanceM = %v, Reached = %v, want a stall inside the %v m section", result.MaxDistanceM, result.Reached, in.Duct.LengthM)
+	}
+	points := result.JettingDataPoints
+	last := points[len(points)-1]
+	if last.LengthM != result.MaxDistanceM || last.ForceN > in.PushForceN {
+		t.Errorf("last point %+v, want %v m with at most %v N", last, result.MaxDistanceM, in.PushForceN)
+	}
+	if next := newBlowingModel(in).requiredForce(len(points), in.FrictionCoefficient); next <= in.PushForceN {
+		t.Errorf("force of the next step = %v N, does not exceed the push force", next)
+	}
+	if len(result.FremcoDataPoints) != len(points) || result.FremcoDataPoints[len(points)-1].TorquePercent > 100 {
+		t.Errorf("Fremco points do not end at the stall: %+v", result.FremcoDataPoints[len(result.FremcoDataPoints)-1])
+	}
+
+	// A section shorter than the stall is reached
+	in.Duct.LengthM = math.Floor(result.MaxDistanceM / 2)
+	result, err = Simulate(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Reached || result.MaxDistanceM != in.Duct.LengthM {
+		t.Errorf("MaxDistanceM = %v, Reached = %v, want the end of the section", result.MaxDistanceM, result.Reached)
+	}
+}
@@ -0,0 +1,179 @@
+package simulator
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Parameters that can be swept in a what-if analysis
+const (
+	SweepPressure       = "pressure_bar"
+	SweepLubricant      = "lubricant"
+	SweepCableDiameter  = "cable_diameter_mm"
+	SweepPushForce      = "push_force_n"
+	maxSweepAxisValues  = 50
+	maxSweepCombination = 400
+)
+
+// SweepAxis describes the values of one swept parameter. Numeric parameters
+// use From/To/Step or explicit Values; lubricant sweeps use the lubricant
+// names in Values (all known lubricants if empty).
+type SweepAxis struct {
+	Parameter string   `json:"parameter"`
+	From      float64  `json:"from"`
+	To        float64  `json:"to"`
+	Step      float64  `json:"step"`
+	Values    []string `json:"values"`
+}
+
+// SweepRequest sweeps one or two parameters of a base simulation input
+type SweepRequest struct {
+	Base SimulationInput `json:"base"`
+	X    SweepAxis       `json:"x"`
+	Y    *SweepAxis      `json:"y"` // optional second parameter
+}
+
+// SweepCell is the prediction for one parameter combination
+type SweepCell struct {
+	X            string  `json:"x"`
+	Y            string  `json:"y,omitempty"`
+	MaxDistanceM float64 `json:"max_distance_m"`
+	Reached      bool    `json:"reached"`
+	GainM        float64 `json:"gain_m"` // difference to the base input
+	Error        string  `json:"error,omitempty"`
+}
+
+// SweepResult contains the sweep as table rows and as heatmap matrix
+type SweepResult struct {
+	XParameter    string       `json:"x_parameter"`
+	YParameter    string       `json:"y_parameter,omitempty"`
+	XValues       []string     `json:"x_values"`
+	YValues       []string     `json:"y_values,omitempty"`
+	SectionLength float64      `json:"section_length_m"`
+	BaseDistanceM float64      `json:"base_distance_m"`
+	Cells         []SweepCell  `json:"cells"`
+	Heatmap       [][]*float64 `json:"heatmap"` // [y][x] reachable distance in m (nil = invalid combination)
+}
+
+// values returns the swept values of the axis as strings
+func (a SweepAxis) values() ([]string, error) {
+	if a.Parameter == SweepLubricant {
+		if len(a.Values) == 0 {
+			return LubricantNames(), nil
+		}
+		return a.Values, nil
+	}
+	switch a.Parameter {
+	case SweepPressure, SweepCableDiameter, SweepPushForce:
+	default:
+		return nil, fmt.Errorf("unknown sweep parameter %q", a.Parameter)
+	}
+
+	if len(a.Values) > 0 {
+		for _, v := range a.Values {
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("invalid %s value %q", a.Parameter, v)
+			}
+		}
+		return a.Values, nil
+	}
+	if a.Step <= 0 || a.To < a.From {
+		return nil, fmt.Errorf("%s sweep needs from <= to and a positive step", a.Parameter)
+	}
+	var values []string
+	for i := 0; ; i++ {
+		v := a.From + float64(i)*a.Step
+		if v > a.To+a.Step/1000 {
+			break
+		}
+		if len(values) == maxSweepAxisValues {
+			return nil, fmt.Errorf("%s sweep exceeds %d values", a.Parameter, maxSweepAxisValues)
+		}
+		values = append(values, strconv.FormatFloat(round(v, 3), 'f', -1, 64))
+	}
+	return values, nil
+}
+
+// apply sets one swept parameter on a copy of the input
+func (a SweepAxis) apply(in SimulationInput, value string) SimulationInput {
+	if a.Parameter == SweepLubricant {
+		// An explicit friction coefficient is rescaled to the new lubricant
+		if in.FrictionCoefficient > 0 {
+			in.FrictionCoefficient *= LubricantFactor(value) / LubricantFactor(in.Cable.Lubricant)
+		}
+		in.Cable.Lubricant = value
+		return in
+	}
+	v, _ := strconv.ParseFloat(value, 64)
+	switch a.Parameter {
+	case SweepPressure:
+		in.PressureBar = v
+	case SweepCableDiameter:
+		// Weight and stiffness belong to the old cable, estimate them for the new diameter
+		in.Cable.DiameterMM = v
+		in.Cable.WeightKgPerM = 0
+		in.Cable.StiffnessNm2 = 0
+	case SweepPushForce:
+		in.PushForceN = v
+	}
+	return in
+}
+
+// Sweep predicts the reachable distance for every combination of the swept
+// parameters. Combinations that are physically impossible (e.g. a cable that
+// does not fit the duct) are reported per cell instead of failing the sweep.
+func Sweep(req SweepRequest) (*SweepResult, error) {
+	base, err := Simulate(req.Base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base input: %v", err)
+	}
+	xValues, err := req.X.values()
+	if err != nil {
+		return nil, err
+	}
+	yValues := []string{""}
+	result := &SweepResult{
+		XParameter:    req.X.Parameter,
+		XValues:       xValues,
+		SectionLength: base.SectionLengthM,
+		BaseDistanceM: base.MaxDistanceM,
+	}
+	if req.Y != nil {
+		if req.Y.Parameter == req.X.Parameter {
+			return nil, fmt.Errorf("cannot sweep %s on both axes", req.X.Parameter)
+		}
+		if yValues, err = req.Y.values(); err != nil {
+			return nil, err
+		}
+		result.YParameter = req.Y.Parameter
+		result.YValues = yValues
+	}
+	if len(xValues)*len(yValues) > maxSweepCombination {
+		return nil, fmt.Errorf("sweep has %d combinations, maximum is %d", len(xValues)*len(yValues), maxSweepCombination)
+	}
+
+	for _, y := range yValues {
+		row := make([]*float64, 0, len(xValues))
+		for _, x := range xValues {
+			in := req.X.apply(req.Base, x)
+			if req.Y != nil {
+				in = req.Y.apply(in, y)
+			}
+			cell := SweepCell{X: x, Y: y}
+			sim, err := Simulate(in)
+			if err != nil {
+				cell.Error = err.Error()
+				row = append(row, nil)
+			} else {
+				cell.MaxDistanceM = sim.MaxDistanceM
+				cell.Reached = sim.Reached
+				cell.GainM = round(sim.MaxDistanceM-base.MaxDistanceM, 1)
+				distance := sim.MaxDistanceM
+				row = append(row, &distance)
+			}
+			result.Cells = append(result.Cells, cell)
+		}
+		result.Heatmap = append(result.Heatmap, row)
+	}
+	return result, nil
+}
@@ -0,0 +1,340 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// TrainingVersion is stored as parser version of protocols created by training sessions
+const TrainingVersion = "1.0.0"
+
+// Control actions a trainee can send to a training session
+const (
+	TrainingActionAdvance      = "advance"        // keep blowing with the current settings
+	TrainingActionSetPressure  = "set_pressure"   // value = compressor pressure in bar
+	TrainingActionSetPushForce = "set_push_force" // value = pushing force limit in N
+	TrainingActionPause        = "pause"          // stop the machine for the given seconds
+	TrainingActionAddLubricant = "add_lubricant"  // lubricant = product name
+)
+
+// Training session states
+const (
+	TrainingRunning   = "running"
+	TrainingPaused    = "paused"
+	TrainingStalled   = "stalled"   // the required pushing force exceeds the limit
+	TrainingCompleted = "completed" // the cable reached the end of the section
+)
+
+// Limits of the training simulation
+const (
+	trainingLogInterval     = time.Second // Fremco devices log once per second while blowing
+	trainingDefaultAdvanceS = 10.0
+	trainingMaxAdvanceS     = 300.0
+	trainingMaxPressureBar  = 20.0
+	trainingDefaultDevice   = "Fremco MicroFlow LOG"
+)
+
+// TrainingSetup is chosen by the trainee when a session is created. Input holds
+// the physical duct and cable; pipe type, cable diameter and lubricant fall back
+// to the descriptive Pipe and Cable fields that end up in the protocol.
+type TrainingSetup struct {
+	Section    string          `json:"section"` // Streckenabschnitt / NVt
+	Company    string          `json:"company"`
+	Operator   string          `json:"operator"`
+	Device     string          `json:"device"` // blowing device model
+	Compressor string          `json:"compressor"`
+	Pipe       FremcoPipe      `json:"pipe"`
+	Cable      FremcoCable     `json:"cable"`
+	Input      SimulationInput `json:"input"`
+}
+
+// TrainingAction is one control action sent by the trainee
+type TrainingAction struct {
+	Action    string  `json:"action"`
+	Value     float64 `json:"value"`     // bar for set_pressure, N for set_push_force
+	Lubricant string  `json:"lubricant"` // product for add_lubricant
+	Seconds   float64 `json:"seconds"`   // simulated time to run after the action, 0 = default
+}
+
+// TrainingEvent records an action in the session log
+type TrainingEvent struct {
+	Elapsed string  `json:"elapsed"` // hh:mm:ss since the start
+	LengthM float64 `json:"length_m"`
+	Action  string  `json:"action"`
+	Value   float64 `json:"value,omitempty"`
+	Detail  string  `json:"detail,omitempty"`
+}
+
+// TrainingSession is a stateful blowing run driven step by step by a trainee
+type TrainingSession struct {
+	ID                  string            `json:"id"`
+	Setup               TrainingSetup     `json:"setup"`
+	Status              string            `json:"status"`
+	StartedAt           time.Time         `json:"started_at"`
+	Elapsed             string            `json:"elapsed"`
+	LengthM             float64           `json:"length_m"`
+	SectionLengthM      float64           `json:"section_length_m"`
+	PressureBar         float64           `json:"pressure_bar"`
+	PushForceN          float64           `json:"push_force_n"`
+	Lubricant           string            `json:"lubricant"`
+	FrictionCoefficient float64           `json:"friction_coefficient"`
+	TorquePercent       float64           `json:"torque_percent"`
+	RowCount            int               `json:"row_count"`
+	Events              []TrainingEvent   `json:"events"`
+	Rows                []FremcoDataPoint `json:"-"` // all logged rows, returned separately
+
+	model     *blowingModel
+	input     SimulationInput
+	maxSpeed  float64
+	installed float64
+	elapsed   time.Duration
+}
+
+// NewTrainingSession validates the setup and starts a session at the duct entry
+func NewTrainingSession(id string, setup TrainingSetup, start time.Time) (*TrainingSession, error) {
+	in := setup.Input
+	if in.Duct.InnerDiameterMM <= 0 {
+		in.Duct.InnerDiameterMM, _ = InnerDiameterFromPipeType(setup.Pipe.PipeType)
+	}
+	if in.Duct.InnerWall == "" {
+		in.Duct.InnerWall = setup.Pipe.InnerWall
+	}
+	if in.Cable.DiameterMM <= 0 {
+		in.Cable.DiameterMM = setup.Cable.Diameter
+	}
+	if in.Cable.Lubricant == "" {
+		in.Cable.Lubricant = setup.Cable.Lubricant
+	}
+	if setup.Device == "" {
+		setup.Device = trainingDefaultDevice
+	}
+	if in.PushForceN <= 0 {
+		in.PushForceN = DevicePushForce(setup.Device)
+	}
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	if in.PressureBar > trainingMaxPressureBar {
+		return nil, fmt.Errorf("pressure must not exceed %.0f bar", trainingMaxPressureBar)
+	}
+	if in.FrictionCoefficient <= 0 {
+		in.FrictionCoefficient = DefaultFrictionCoefficient(in.Duct.InnerWall, in.Cable.Lubricant)
+	}
+	maxSpeed := in.MaxSpeedMMin
+	if maxSpeed <= 0 {
+		maxSpeed = defaultMaxSpeedMMin
+	}
+	// Keep the protocol fields in line with what is simulated
+	setup.Cable.Diameter = in.Cable.DiameterMM
+	setup.Cable.Lubricant = in.Cable.Lubricant
+	setup.Input = in
+
+	s := &TrainingSession{
+		ID:        id,
+		Setup:     setup,
+		StartedAt: start,
+		Events:    []TrainingEvent{},
+		Rows:      []FremcoDataPoint{},
+		model:     newBlowingModel(in),
+		input:     in,
+		maxSpeed:  maxSpeed,
+	}
+	s.updateState(TrainingRunning, 0)
+	return s, nil
+}
+
+// Apply performs a control action and runs the simulation for the requested
+// time. It returns the measurement rows logged during that time.
+func (s *TrainingSession) Apply(a TrainingAction) ([]FremcoDataPoint, error) {
+	if s.Status == TrainingCompleted {
+		return nil, fmt.Errorf("cable reached the end of the section, finish the session")
+	}
+	seconds := a.Seconds
+	if seconds <= 0 {
+		seconds = trainingDefaultAdvanceS
+	}
+	if seconds > trainingMaxAdvanceS {
+		return nil, fmt.Errorf("seconds must not exceed %.0f", trainingMaxAdvanceS)
+	}
+
+	event := TrainingEvent{
+		Elapsed: formatDuration(s.elapsed),
+		LengthM: round(s.installed, 1),
+		Action:  a.Action,
+	}
+	paused := false
+	switch a.Action {
+	case TrainingActionAdvance:
+	case TrainingActionSetPressure:
+		if a.Value < 0 || a.Value > trainingMaxPressureBar {
+			return nil, fmt.Errorf("pressure must be between 0 and %.0f bar", trainingMaxPressureBar)
+		}
+		s.input.PressureBar = a.Value
+		s.model.setAirForce(airForcePerMeter(s.input.Duct.InnerDiameterMM, s.input.Cable.DiameterMM,
+			a.Value, s.model.length, s.model.step, len(s.model.route)))
+		event.Value = a.Value
+	case TrainingActionSetPushForce:
+		if a.Value <= 0 {
+			return nil, fmt.Errorf("push force must be positive")
+		}
+		s.input.PushForceN = a.Value
+		event.Value = a.Value
+	case TrainingActionPause:
+		paused = true
+		event.Value = seconds
+	case TrainingActionAddLubricant:
+		lubricant := strings.TrimSpace(a.Lubricant)
+		if lubricant == "" {
+			return nil, fmt.Errorf("lubricant is required")
+		}
+		// The new lubricant replaces the effect of the previous one on the whole run
+		s.input.FrictionCoefficient *= LubricantFactor(lubricant) / LubricantFactor(s.input.Cable.Lubricant)
+		s.input.Cable.Lubricant = lubricant
+		event.Detail = lubricant
+	default:
+		return nil, fmt.Errorf("unknown action %q", a.Action)
+	}
+	s.Events = append(s.Events, event)
+
+	rows := make([]FremcoDataPoint, 0, int(seconds))
+	for t := trainingLogInterval; t <= time.Duration(seconds*float64(time.Second)); t += trainingLogInterval {
+		rows = append(rows, s.tick(paused))
+		if s.Status == TrainingCompleted {
+			break
+		}
+	}
+	s.Rows = append(s.Rows, rows...)
+	s.RowCount = len(s.Rows)
+	return rows, nil
+}
+
+// tick advances the run by one logging interval and returns the logged row
+func (s *TrainingSession) tick(paused bool) FremcoDataPoint {
+	s.elapsed += trainingLogInterval
+	speed, torque := 0.0, 0.0
+	status := TrainingPaused
+	if !paused {
+		status = TrainingRunning
+		steps := int(s.installed / s.model.step)
+		if steps > len(s.model.route) {
+			steps = len(s.model.route)
+		}
+		force := s.model.requiredForce(steps, s.input.FrictionCoefficient)
+		torque = math.Max(force/s.input.PushForceN*100, 0)
+		if force > s.input.PushForceN {
+			status, torque = TrainingStalled, 100
+		} else {
+			speed = speedForTorque(torque, s.maxSpeed)
+			s.installed = math.Min(s.installed+speed*trainingLogInterval.Minutes(), s.model.length)
+		}
+	}
+	if s.installed >= s.model.length {
+		status = TrainingCompleted
+	}
+	s.updateState(status, torque)
+
+	return FremcoDataPoint{
+		LengthM:       round(s.installed, 1),
+		SpeedMMin:     round(speed, 0),
+		PressureBar:   round(s.input.PressureBar, 1),
+		TorquePercent: round(torque, 0),
+		Timestamp:     s.StartedAt.Add(s.elapsed).Format("2006-01-02 15:04:05"),
+	}
+}
+
+// updateState refreshes the exported state after the run changed
+func (s *TrainingSession) updateState(status string, torque float64) {
+	s.Status = status
+	s.Elapsed = formatDuration(s.elapsed)
+	s.LengthM = round(s.installed, 1)
+	s.SectionLengthM = s.model.length
+	s.PressureBar = s.input.PressureBar
+	s.PushForceN = s.input.PushForceN
+	s.Lubricant = s.input.Cable.Lubricant
+	s.FrictionCoefficient = round(s.input.FrictionCoefficient, 4)
+	s.TorquePercent = round(torque, 0)
+}
+
+// Protocol returns the run so far as a Fremco protocol that can be saved like a parsed one
+func (s *TrainingSession) Protocol() (*FremcoProtocol, error) {
+	if len(s.Rows) == 0 {
+		return nil, fmt.Errorf("session has no measurements yet")
+	}
+	lubricated := false
+	for _, e := range s.Events {
+		if e.Action == TrainingActionAddLubricant {
+			lubricated = true
+		}
+	}
+
+	setup := s.Setup
+	setup.Cable.Lubricant = s.input.Cable.Lubricant
+	distance := int(math.Round(s.installed))
+	p := &FremcoProtocol{
+		ProtocolInfo: FremcoProtocolInfo{
+			System:       "SpeedNet-System",
+			DocumentType: "Einblas - Protokoll",
+			Date:         s.StartedAt.Format("2006-01-02"),
+			StartTime:    s.StartedAt.Format("15:04"),
+			SectionNVT:   setup.Section,
+			Company:      setup.Company,
+			Operator:     setup.Operator,
+			Remarks:      s.remarks(),
+		},
+		Equipment: FremcoEquipment{
+			BlowingDevice: FremcoBlowingDevice{
+				Model:      setup.Device,
+				Lubricator: lubricated,
+			},
+			Pipe:       setup.Pipe,
+			Cable:      setup.Cable,
+			Compressor: FremcoCompressor{Model: setup.Compressor},
+		},
+		Measurements: FremcoMeasurements{
+			Summary: FremcoSummary{
+				Distance:    distance,
+				BlowingTime: formatDuration(s.elapsed),
+				Weather:     FremcoWeather{Temperature: s.input.AmbientTemperatureC},
+			},
+			DataPoints: s.Rows,
+		},
+		ExportMetadata: FremcoExportMetadata{
+			ParsedAt:       time.Now(),
+			ParserVersion:  "training-" + TrainingVersion,
+			SourceFilename: fmt.Sprintf("training_%s_%s.json", s.ID, s.StartedAt.Format("2006-01-02 15_04")),
+		},
+	}
+	if p.Equipment.Pipe.ColorCoding == nil {
+		p.Equipment.Pipe.ColorCoding = []string{}
+	}
+	return p, nil
+}
+
+// remarks summarizes the outcome and the trainee's actions for the protocol
+func (s *TrainingSession) remarks() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Trainingssitzung %s: ", s.ID)
+	switch s.Status {
+	case TrainingCompleted:
+		b.WriteString("Streckenende erreicht")
+	case TrainingStalled:
+		fmt.Fprintf(&b, "Kabel bei %.0f m festgefahren", s.installed)
+	default:
+		fmt.Fprintf(&b, "abgebrochen bei %.0f m", s.installed)
+	}
+	for _, e := range s.Events {
+		switch e.Action {
+		case TrainingActionSetPressure:
+			fmt.Fprintf(&b, "; %s Druck %.1f bar", e.Elapsed, e.Value)
+		case TrainingActionSetPushForce:
+			fmt.Fprintf(&b, "; %s Schubkraft %.0f N", e.Elapsed, e.Value)
+		case TrainingActionPause:
+			fmt.Fprintf(&b, "; %s Pause %.0f s", e.Elapsed, e.Value)
+		case TrainingActionAddLubricant:
+			fmt.Fprintf(&b, "; %s Gleitmittel %s", e.Elapsed, e.Detail)
+		}
+	}
+	return b.String()
+}
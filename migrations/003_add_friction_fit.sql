@@ -0,0 +1,31 @@
+-- Back-fitted cable/duct friction parameters per protocol
+-- Run this migration to add friction calibration support: 003_add_friction_fit.sql
+
+CREATE TABLE IF NOT EXISTS protocol_friction_fit (
+    id SERIAL PRIMARY KEY,
+    protocol_id INTEGER UNIQUE REFERENCES protocols(id) ON DELETE CASCADE,
+
+    friction_coefficient DECIMAL(6,4),   -- effective cable/duct friction coefficient
+    pressure_dominance_m DECIMAL(8,2),   -- length where air drag starts to dominate (NULL = never)
+    rms_error_n DECIMAL(10,3),           -- RMS error between model and measured force
+    sample_count INTEGER,
+    pressure_bar DECIMAL(8,3),           -- median pressure used for the fit
+    model_version VARCHAR(20),
+
+    fitted_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Calibrated friction values per pipe manufacturer, pipe type and lubricant
+CREATE OR REPLACE VIEW friction_calibration_view AS
+SELECT
+    COALESCE(pe.pipe_manufacturer, '') AS pipe_manufacturer,
+    COALESCE(pe.pipe_type, '') AS pipe_type,
+    COALESCE(pe.cable_lubricant, '') AS cable_lubricant,
+    COUNT(*) AS protocol_count,
+    AVG(f.friction_coefficient) AS avg_friction,
+    COALESCE(STDDEV_SAMP(f.friction_coefficient), 0) AS stddev_friction,
+    MIN(f.friction_coefficient) AS min_friction,
+    MAX(f.friction_coefficient) AS max_friction
+FROM protocol_friction_fit f
+JOIN protocol_equipment pe ON pe.protocol_id = f.protocol_id
+GROUP BY COALESCE(pe.pipe_manufacturer, ''), COALESCE(pe.pipe_type, ''), COALESCE(pe.cable_lubricant, '');
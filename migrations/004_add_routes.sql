@@ -0,0 +1,27 @@
+-- Planned duct routes with bends and elevation profile
+-- Run this migration to add route support: 004_add_routes.sql
+
+CREATE TABLE IF NOT EXISTS routes (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(200),
+    section_nvt VARCHAR(200),       -- e.g. "Haflinger Weg 5 / NVT1V3400"
+    nvt_key VARCHAR(50),            -- bare NVT identifier used to link protocols (e.g. "1V3400")
+    address TEXT,
+    source VARCHAR(20) CHECK (source IN ('csv', 'gps')),
+    total_length_m DECIMAL(10,2),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS route_segments (
+    id SERIAL PRIMARY KEY,
+    route_id INTEGER REFERENCES routes(id) ON DELETE CASCADE,
+    sequence_number INTEGER NOT NULL,
+    length_m DECIMAL(10,2) NOT NULL,
+    bend_angle_deg DECIMAL(6,1) DEFAULT 0,    -- bend at the end of the segment
+    bend_radius_m DECIMAL(8,2) DEFAULT 0,
+    elevation_m DECIMAL(8,2) DEFAULT 0        -- elevation change over the segment
+);
+
+CREATE INDEX IF NOT EXISTS idx_routes_section_nvt ON routes(section_nvt);
+CREATE INDEX IF NOT EXISTS idx_routes_nvt_key ON routes(nvt_key);
+CREATE INDEX IF NOT EXISTS idx_route_segments_route_id ON route_segments(route_id);
@@ -0,0 +1,29 @@
+-- Cable and duct product catalog with technical specifications
+-- Run this migration to add catalog support: 005_add_catalog.sql
+-- The catalog is seeded from schema/catalog_seed.json on startup if it is empty.
+
+CREATE TABLE IF NOT EXISTS catalog_cables (
+    id SERIAL PRIMARY KEY,
+    manufacturer VARCHAR(200),
+    designation VARCHAR(200) NOT NULL,        -- e.g. "A-D 2Y 1x6"
+    diameter_mm DECIMAL(5,2) NOT NULL,
+    weight_kg_per_m DECIMAL(8,5),
+    fiber_count INTEGER,
+    max_push_force_n DECIMAL(8,2),            -- maximum pushing force the cable tolerates
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS catalog_ducts (
+    id SERIAL PRIMARY KEY,
+    manufacturer VARCHAR(200),
+    bundle VARCHAR(100),                      -- e.g. "SNRVe 22x7x1,5"
+    pipe_type VARCHAR(100),                   -- e.g. "SNR 7x1,5"
+    outer_diameter_mm DECIMAL(5,2) NOT NULL,
+    inner_diameter_mm DECIMAL(5,2) NOT NULL,
+    inner_wall VARCHAR(100),                  -- e.g. "Gerieft", "Glatt"
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Catalog entries matched when a protocol is saved
+ALTER TABLE protocol_equipment ADD COLUMN IF NOT EXISTS catalog_cable_id INTEGER REFERENCES catalog_cables(id) ON DELETE SET NULL;
+ALTER TABLE protocol_equipment ADD COLUMN IF NOT EXISTS catalog_duct_id INTEGER REFERENCES catalog_ducts(id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+-- Equipment fields of the Jetting protocol form that Fremco protocols do not carry
+-- Run this migration to store complete Jetting equipment: 006_add_jetting_equipment.sql
+
+ALTER TABLE protocol_equipment ADD COLUMN IF NOT EXISTS pipe_inner_diameter DECIMAL(5,2);  -- Rohrinnendurchmesser [mm]
+ALTER TABLE protocol_equipment ADD COLUMN IF NOT EXISTS pipe_outer_diameter DECIMAL(5,2);  -- Rohraußendurchmesser [mm]
+ALTER TABLE protocol_equipment ADD COLUMN IF NOT EXISTS cable_drum_number VARCHAR(100);    -- Kabeltrommel-Nr
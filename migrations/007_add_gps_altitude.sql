@@ -0,0 +1,4 @@
+-- Altitude of the GPS position, recorded by Jetting protocols ("52.4914°;9.85174°;95.8000 m")
+-- Run this migration to store the altitude: 007_add_gps_altitude.sql
+
+ALTER TABLE protocol_summary ADD COLUMN IF NOT EXISTS gps_altitude DECIMAL(8,2);  -- meters
@@ -0,0 +1,17 @@
+-- Values the parser could not read, per protocol
+-- Run this migration to keep parse warnings and errors: 008_add_parse_diagnostics.sql
+
+CREATE TABLE IF NOT EXISTS protocol_diagnostics (
+    id SERIAL PRIMARY KEY,
+    protocol_id INTEGER REFERENCES protocols(id) ON DELETE CASCADE,
+
+    severity VARCHAR(10) NOT NULL CHECK (severity IN ('warning', 'error')),
+    line_number INTEGER NOT NULL DEFAULT 0,  -- line of the normalized text, 0 if unknown
+    field VARCHAR(50) NOT NULL DEFAULT '',
+    raw_text TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL,
+
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_protocol_diagnostics_protocol_id ON protocol_diagnostics(protocol_id);
@@ -0,0 +1,21 @@
+-- Protocols of Plumettaz blowing machines, read from their PDF reports and CSV logs
+-- Run this migration to store Plumettaz protocols: 009_add_plumettaz_protocol_type.sql
+
+ALTER TABLE protocols DROP CONSTRAINT IF EXISTS protocols_protocol_type_check;
+ALTER TABLE protocols ADD CONSTRAINT protocols_protocol_type_check
+    CHECK (protocol_type IN ('fremco', 'jetting', 'plumettaz'));
+
+-- Plumettaz protocols with summary
+CREATE OR REPLACE VIEW plumettaz_protocols_view AS
+SELECT
+    p.*,
+    ps.total_distance,
+    ps.blowing_time,
+    ps.weather_temperature,
+    pe.device_model,
+    pe.cable_manufacturer,
+    pe.cable_designation
+FROM protocols p
+LEFT JOIN protocol_summary ps ON p.id = ps.protocol_id
+LEFT JOIN protocol_equipment pe ON p.id = pe.protocol_id
+WHERE p.protocol_type = 'plumettaz';
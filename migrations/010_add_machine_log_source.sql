@@ -0,0 +1,7 @@
+-- Raw controller logs (CSV/USB export) attached to a protocol, replacing the measurements of its PDF table
+-- Run this migration to import machine logs: 010_add_machine_log_source.sql
+
+ALTER TABLE protocols ADD COLUMN IF NOT EXISTS log_filename VARCHAR(255);  -- machine log the measurements come from
+ALTER TABLE protocols ADD COLUMN IF NOT EXISTS log_imported_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_protocol_equipment_controller_sn ON protocol_equipment(controller_sn);
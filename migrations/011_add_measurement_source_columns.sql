@@ -0,0 +1,16 @@
+-- Columns of the exported table (PDF table, CSV log) the measurements of a protocol were read from
+-- Run this migration to keep the source columns of the measurement model: 011_add_measurement_source_columns.sql
+
+CREATE TABLE IF NOT EXISTS protocol_source_columns (
+    id SERIAL PRIMARY KEY,
+    protocol_id INTEGER REFERENCES protocols(id) ON DELETE CASCADE,
+
+    position INTEGER NOT NULL,              -- column order in the exported table, from 1
+    name VARCHAR(100) NOT NULL,             -- header text, e.g. "Push force"
+    unit VARCHAR(30) NOT NULL DEFAULT '',   -- unit as exported, e.g. "daN"
+    field VARCHAR(30) NOT NULL DEFAULT '',  -- protocol_measurements column or 'clock', empty if not read
+
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_protocol_source_columns_protocol_id ON protocol_source_columns(protocol_id);
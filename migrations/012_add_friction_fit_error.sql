@@ -0,0 +1,21 @@
+-- Friction fits are computed when a protocol is saved or imported; protocols that cannot be
+-- fitted keep the reason so that they are not refitted on every view
+-- Run this migration to store failed friction fits: 012_add_friction_fit_error.sql
+
+ALTER TABLE protocol_friction_fit ADD COLUMN IF NOT EXISTS fit_error TEXT;  -- NULL = fitted
+
+-- Calibrated friction values per pipe manufacturer, pipe type and lubricant, without failed fits
+CREATE OR REPLACE VIEW friction_calibration_view AS
+SELECT
+    COALESCE(pe.pipe_manufacturer, '') AS pipe_manufacturer,
+    COALESCE(pe.pipe_type, '') AS pipe_type,
+    COALESCE(pe.cable_lubricant, '') AS cable_lubricant,
+    COUNT(*) AS protocol_count,
+    AVG(f.friction_coefficient) AS avg_friction,
+    COALESCE(STDDEV_SAMP(f.friction_coefficient), 0) AS stddev_friction,
+    MIN(f.friction_coefficient) AS min_friction,
+    MAX(f.friction_coefficient) AS max_friction
+FROM protocol_friction_fit f
+JOIN protocol_equipment pe ON pe.protocol_id = f.protocol_id
+WHERE f.fit_error IS NULL
+GROUP BY COALESCE(pe.pipe_manufacturer, ''), COALESCE(pe.pipe_type, ''), COALESCE(pe.cable_lubricant, '');
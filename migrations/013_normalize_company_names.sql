@@ -0,0 +1,18 @@
+-- Company names are stored in the spelling of schema/known_companies.json; rows saved
+-- before the normalization keep the printed spelling and would be reported separately
+-- Run this migration to rewrite stored company names: 013_normalize_company_names.sql
+
+UPDATE protocols SET company = 'Wolken-ASM GmbH'
+WHERE company ILIKE 'Wolken-ASM' OR company ILIKE 'Wolken-ASM GmbH';
+
+UPDATE protocols SET service_provider = 'M.A.X. Bauservice'
+WHERE service_provider ILIKE 'M.A.X. Bauservice'
+   OR service_provider ILIKE 'M.A.X. Bauservice GmbH'
+   OR service_provider ILIKE 'MAX Bauservice';
+
+-- Trailing whitespace and doubled spaces from the PDF text
+UPDATE protocols SET company = regexp_replace(btrim(company), '\s+', ' ', 'g')
+WHERE company IS NOT NULL AND company <> regexp_replace(btrim(company), '\s+', ' ', 'g');
+
+UPDATE protocols SET service_provider = regexp_replace(btrim(service_provider), '\s+', ' ', 'g')
+WHERE service_provider IS NOT NULL AND service_provider <> regexp_replace(btrim(service_provider), '\s+', ' ', 'g');
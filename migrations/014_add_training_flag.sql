@@ -0,0 +1,28 @@
+-- Protocols saved from training sessions (parser version training-x.y.z) are simulated runs;
+-- they are kept out of the friction calibration and the length report
+-- Run this migration to mark training protocols: 014_add_training_flag.sql
+
+ALTER TABLE protocols ADD COLUMN IF NOT EXISTS is_training BOOLEAN
+    GENERATED ALWAYS AS (COALESCE(parser_version LIKE 'training-%', FALSE)) STORED;
+
+-- Calibrated friction values per pipe manufacturer, pipe type and lubricant, without failed fits
+-- and training runs
+CREATE OR REPLACE VIEW friction_calibration_view AS
+SELECT
+    COALESCE(pe.pipe_manufacturer, '') AS pipe_manufacturer,
+    COALESCE(pe.pipe_type, '') AS pipe_type,
+    COALESCE(pe.cable_lubricant, '') AS cable_lubricant,
+    COUNT(*) AS protocol_count,
+    AVG(f.friction_coefficient) AS avg_friction,
+    COALESCE(STDDEV_SAMP(f.friction_coefficient), 0) AS stddev_friction,
+    MIN(f.friction_coefficient) AS min_friction,
+    MAX(f.friction_coefficient) AS max_friction
+FROM protocol_friction_fit f
+JOIN protocol_equipment pe ON pe.protocol_id = f.protocol_id
+JOIN protocols p ON p.id = f.protocol_id
+WHERE f.fit_error IS NULL AND NOT p.is_training
+GROUP BY COALESCE(pe.pipe_manufacturer, ''), COALESCE(pe.pipe_type, ''), COALESCE(pe.cable_lubricant, '');
+
+-- Fits stored for training protocols before this migration
+DELETE FROM protocol_friction_fit f USING protocols p
+WHERE p.id = f.protocol_id AND p.is_training;
@@ -0,0 +1,4 @@
+-- Push force determined by the crash test of a Jetting blowing device ("Ermittelte Schubkraft")
+-- Run this migration to store the crash test push force: 015_add_crash_test_force.sql
+
+ALTER TABLE protocol_equipment ADD COLUMN IF NOT EXISTS crash_test_force VARCHAR(50);  -- Ermittelte Schubkraft